@@ -0,0 +1,430 @@
+// Package astkratos analyzer: Configurable project analysis built with functional options
+// Lets the api roots, the include and exclude patterns and the generated file suffixes be chosen per analyzer
+// Sends debug output to the logger of the analyzer instead of the package debug switch when one is given
+// Parses the files of the api roots in parallel up to the concurrency limit, keeping walk order in the results
+//
+// astkratos 分析器：通过函数式选项构建的可配置项目分析
+// 允许为每个分析器选择 api 根目录、包含和排除模式以及生成文件后缀
+// 提供日志记录器时，调试输出发送到分析器的日志记录器，而不是包级调试开关
+// 在并发上限内并行解析 api 根目录中的文件，结果保持遍历顺序
+package astkratos
+
+import (
+	"context"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/orzkratos/astkratos/internal/utils"
+	"github.com/yyle88/erero"
+	"github.com/yyle88/zaplog"
+	"go.uber.org/zap"
+)
+
+// GeneratedSuffixes holds the file suffixes identifying each kind of generated file
+//
+// GeneratedSuffixes 保存识别各类生成文件的文件后缀
+type GeneratedSuffixes struct {
+	Grpc   string // gRPC stubs, _grpc.pb.go by default // gRPC 存根，默认为 _grpc.pb.go
+	Http   string // Kratos HTTP bindings, _http.pb.go by default // Kratos HTTP 绑定，默认为 _http.pb.go
+	Errors string // Kratos error reasons, _errors.pb.go by default // Kratos 错误原因，默认为 _errors.pb.go
+}
+
+// DefaultGeneratedSuffixes returns the suffixes written by protoc-gen-go-grpc, protoc-gen-go-http and protoc-gen-go-errors
+//
+// DefaultGeneratedSuffixes 返回 protoc-gen-go-grpc、protoc-gen-go-http 和 protoc-gen-go-errors 写入的后缀
+func DefaultGeneratedSuffixes() GeneratedSuffixes {
+	return GeneratedSuffixes{
+		Grpc:   "_grpc.pb.go",
+		Http:   "_http.pb.go",
+		Errors: "_errors.pb.go",
+	}
+}
+
+// Analyzer runs the project analysis with its own configuration
+//
+// Analyzer 使用自身配置运行项目分析
+type Analyzer struct {
+	apiRoots    []string           // Api roots, relative ones resolved against the project root // api 根目录，相对路径基于项目根目录解析
+	includes    []string           // Patterns a file of the api roots must match, empty to take every file // api 根目录中的文件必须匹配的模式，为空时接受每个文件
+	excludes    []string           // Patterns of files of the api roots to skip // api 根目录中要跳过的文件模式
+	suffixes    GeneratedSuffixes  // Suffixes of the generated files // 生成文件的后缀
+	logger      *zap.SugaredLogger // Logger of debug output, nil to follow SetDebugMode // 调试输出的日志记录器，为 nil 时遵循 SetDebugMode
+	concurrency int                // Files parsed at the same time // 同时解析的文件数
+}
+
+// Option configures an Analyzer
+//
+// Option 配置 Analyzer
+type Option func(*Analyzer)
+
+// NewAnalyzer creates an analyzer with the defaults of the Kratos layout, then applies the options
+// The api tree is projectRoot/api, the suffixes are DefaultGeneratedSuffixes and files parse on every CPU
+//
+// NewAnalyzer 使用 Kratos 布局的默认值创建分析器，然后应用选项
+// api 目录树为 projectRoot/api，后缀为 DefaultGeneratedSuffixes，文件在所有 CPU 上解析
+func NewAnalyzer(options ...Option) *Analyzer {
+	analyzer := &Analyzer{
+		apiRoots:    []string{"api"},
+		suffixes:    DefaultGeneratedSuffixes(),
+		concurrency: runtime.GOMAXPROCS(0),
+	}
+	for _, option := range options {
+		option(analyzer)
+	}
+	return analyzer
+}
+
+// WithApiRoots sets the api roots, relative ones are resolved against the project root
+//
+// WithApiRoots 设置 api 根目录，相对路径基于项目根目录解析
+func WithApiRoots(roots ...string) Option {
+	return func(a *Analyzer) {
+		a.apiRoots = roots
+	}
+}
+
+// WithIncludePatterns keeps only the files of the api roots matching one of the patterns
+// A pattern matches the slash path relative to the api root, one of its directories or its base name
+//
+// WithIncludePatterns 只保留 api 根目录中匹配任一模式的文件
+// 模式匹配相对 api 根目录的斜杠路径、其中某个目录或文件名
+func WithIncludePatterns(patterns ...string) Option {
+	return func(a *Analyzer) {
+		a.includes = append(a.includes, patterns...)
+	}
+}
+
+// WithExcludePatterns skips the files of the api roots matching one of the patterns, such as third_party
+//
+// WithExcludePatterns 跳过 api 根目录中匹配任一模式的文件，例如 third_party
+func WithExcludePatterns(patterns ...string) Option {
+	return func(a *Analyzer) {
+		a.excludes = append(a.excludes, patterns...)
+	}
+}
+
+// WithGeneratedSuffixes sets the suffixes of the generated files, blank fields keep the defaults
+//
+// WithGeneratedSuffixes 设置生成文件的后缀，空字段保留默认值
+func WithGeneratedSuffixes(suffixes GeneratedSuffixes) Option {
+	return func(a *Analyzer) {
+		if suffixes.Grpc != "" {
+			a.suffixes.Grpc = suffixes.Grpc
+		}
+		if suffixes.Http != "" {
+			a.suffixes.Http = suffixes.Http
+		}
+		if suffixes.Errors != "" {
+			a.suffixes.Errors = suffixes.Errors
+		}
+	}
+}
+
+// WithLogger sends the debug output of the analyzer to the logger, whatever SetDebugMode says
+//
+// WithLogger 将分析器的调试输出发送到该日志记录器，不受 SetDebugMode 影响
+func WithLogger(logger *zap.SugaredLogger) Option {
+	return func(a *Analyzer) {
+		a.logger = logger
+	}
+}
+
+// WithConcurrency limits the number of files parsed at the same time, values below 1 mean 1
+//
+// WithConcurrency 限制同时解析的文件数，小于 1 的值视为 1
+func WithConcurrency(limit int) Option {
+	return func(a *Analyzer) {
+		a.concurrency = max(limit, 1)
+	}
+}
+
+// Analyze performs the comprehensive analysis of the Kratos project at the root
+// Stops with the context error once the context is done
+//
+// Analyze 对根目录下的 Kratos 项目执行全面分析
+// 上下文结束后以上下文错误停止
+func (a *Analyzer) Analyze(ctx context.Context, projectRoot string) (*ProjectReport, error) {
+	if err := a.checkPatterns(); err != nil {
+		return nil, erero.Wro(err)
+	}
+	module, err := resolveProjectModule(projectRoot)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	apiRoots := make([]string, 0, len(a.apiRoots))
+	for _, apiRoot := range a.apiRoots {
+		if !filepath.IsAbs(apiRoot) {
+			apiRoot = filepath.Join(projectRoot, apiRoot)
+		}
+		if apiRoot, err = checkApiRoot(apiRoot); err != nil {
+			return nil, erero.Wro(err)
+		}
+		apiRoots = append(apiRoots, apiRoot)
+	}
+	return a.analyzeProject(ctx, projectRoot, apiRoots, module.importPath, module.info)
+}
+
+// debugLogger returns the logger of debug output, nil when debug output is off
+//
+// debugLogger 返回调试输出的日志记录器，调试输出关闭时返回 nil
+func (a *Analyzer) debugLogger() *zap.SugaredLogger {
+	if a.logger != nil {
+		return a.logger
+	}
+	if debugModeOpen {
+		return zaplog.SUG
+	}
+	return nil
+}
+
+// checkPatterns reports the first malformed include or exclude pattern
+//
+// checkPatterns 报告第一个格式错误的包含或排除模式
+func (a *Analyzer) checkPatterns() error {
+	for _, pattern := range append(append([]string{}, a.includes...), a.excludes...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return erero.Wrapf(err, "pattern %q", pattern)
+		}
+	}
+	return nil
+}
+
+// isIncluded reports whether the file of the api root passes the include and exclude patterns
+//
+// isIncluded 判断 api 根目录中的文件是否通过包含和排除模式
+func (a *Analyzer) isIncluded(apiRoot string, filePath string) bool {
+	relPath, err := filepath.Rel(apiRoot, filePath)
+	if err != nil {
+		return true
+	}
+	relPath = filepath.ToSlash(relPath)
+	if matchPathPatterns(a.excludes, relPath) {
+		return false
+	}
+	return len(a.includes) == 0 || matchPathPatterns(a.includes, relPath)
+}
+
+// matchPathPatterns reports whether the slash path, one of its directories or its base name matches a pattern
+//
+// matchPathPatterns 判断斜杠路径、其中某个目录或文件名是否匹配任一模式
+func matchPathPatterns(patterns []string, relPath string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, path.Base(relPath)); ok {
+			return true
+		}
+		for dir := relPath; dir != "." && dir != "/"; dir = path.Dir(dir) {
+			if ok, _ := path.Match(pattern, dir); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// apiScanFile holds the extraction results of one file of the api roots
+//
+// apiScanFile 保存 api 根目录中单个文件的提取结果
+type apiScanFile struct {
+	grpcFile      *grpcPbGoFile      // Set on gRPC stubs // gRPC 存根时设置
+	httpFile      *httpPbGoFile      // Set on HTTP bindings // HTTP 绑定时设置
+	errorsFile    *errorsPbGoFile    // Set on error reasons // 错误原因时设置
+	protoFile     *ProtoFile         // Set on .proto files // .proto 文件时设置
+	httpErr       error              // Set on HTTP bindings failing to parse // HTTP 绑定解析失败时设置
+	errorsErr     error              // Set on error reasons failing to parse // 错误原因解析失败时设置
+	enumTypes     map[string]string  // Set on other .pb.go files, typed constants to their type names // 其他 .pb.go 文件时设置，带类型的常量到其类型名称
+	enumErr       error              // Set on other .pb.go files failing to parse // 其他 .pb.go 文件解析失败时设置
+	protoErr      error              // Set on .proto files failing to parse // .proto 文件解析失败时设置
+	generatedFile *GeneratedFileInfo // Set on generated files with a header // 带头部的生成文件时设置
+	headerErr     error              // Set on .pb.go files whose header could not be read // .pb.go 文件头部无法读取时设置
+}
+
+// scanApiFiles walks the api roots once and parses their files of the given kinds in parallel up to the concurrency limit
+// The first error in walk order is returned, so that the same broken file is reported on every run
+//
+// scanApiFiles 遍历 api 根目录一次，并在并发上限内并行解析其中给定种类的文件
+// 返回按遍历顺序的第一个错误，使每次运行报告的都是同一个损坏文件
+func (a *Analyzer) scanApiFiles(ctx context.Context, apiRoots []string, kinds apiScanKind) (*apiScanResult, error) {
+	logger := a.debugLogger()
+	type scanItem struct {
+		apiRoot string
+		path    string
+	}
+	var items []scanItem
+	suffixPattern := utils.NewSuffixPattern([]string{".pb.go", ".proto", a.suffixes.Grpc, a.suffixes.Http, a.suffixes.Errors})
+	for _, apiRoot := range apiRoots {
+		if logger != nil {
+			logger.Debugln("scanning generated api sources in project:", apiRoot)
+		}
+		if err := utils.WalkFiles(apiRoot, suffixPattern, func(path string, info os.FileInfo) error {
+			if a.fileScanKinds(path)&kinds != 0 && a.isIncluded(apiRoot, path) {
+				items = append(items, scanItem{apiRoot: apiRoot, path: path})
+			}
+			return nil
+		}); err != nil {
+			return nil, erero.Wro(err)
+		}
+	}
+
+	files := make([]*apiScanFile, len(items))
+	errs := make([]error, len(items))
+	semaphore := make(chan struct{}, max(a.concurrency, 1))
+	var wg sync.WaitGroup
+scheduling:
+	for idx, item := range items {
+		// Wait on the context as well, a canceled scan must not block on a full semaphore
+		// 同时等待上下文，已取消的扫描不能阻塞在已满的信号量上
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+			break scheduling
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			if logger != nil {
+				logger.Debugln("examining generated protobuf source:", item.path)
+			}
+			files[idx], errs[idx] = a.parseApiFile(item.apiRoot, item.path, kinds)
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, erero.Wro(err)
+	}
+	for _, err := range errs {
+		if err != nil {
+			return nil, erero.Wro(err)
+		}
+	}
+
+	result := &apiScanResult{suffixes: a.suffixes, logger: logger}
+	dirEnumTypes := map[string]map[string]string{}
+	var enumFailures []*scanFailure
+	for idx, file := range files {
+		if file.generatedFile != nil {
+			result.generatedFiles = append(result.generatedFiles, file.generatedFile)
+		}
+		if file.headerErr != nil {
+			result.headerFailures = append(result.headerFailures, &scanFailure{path: items[idx].path, err: file.headerErr})
+		}
+		switch {
+		case file.grpcFile != nil:
+			result.grpcFiles = append(result.grpcFiles, file.grpcFile)
+		case file.httpFile != nil:
+			result.httpFiles = append(result.httpFiles, file.httpFile)
+		case file.httpErr != nil:
+			result.httpFailures = append(result.httpFailures, &scanFailure{path: items[idx].path, err: file.httpErr})
+		case file.errorsFile != nil:
+			result.errorsFiles = append(result.errorsFiles, file.errorsFile)
+		case file.errorsErr != nil:
+			result.errorsFailures = append(result.errorsFailures, &scanFailure{path: items[idx].path, err: file.errorsErr})
+		case file.protoFile != nil:
+			result.protoFiles = append(result.protoFiles, file.protoFile)
+		case file.protoErr != nil:
+			result.protoFailures = append(result.protoFailures, &scanFailure{path: items[idx].path, err: file.protoErr})
+		case file.enumTypes != nil:
+			dir := filepath.Dir(items[idx].path)
+			if dirEnumTypes[dir] == nil {
+				dirEnumTypes[dir] = map[string]string{}
+			}
+			maps.Copy(dirEnumTypes[dir], file.enumTypes)
+		case file.enumErr != nil:
+			enumFailures = append(enumFailures, &scanFailure{path: items[idx].path, err: file.enumErr})
+		}
+	}
+
+	// The enum constants live in the .pb.go files of the same package as the error reasons
+	// A broken one only matters to the error view, and only when it sits next to error reasons
+	// 枚举常量位于与错误原因同一包的 .pb.go 文件中
+	// 损坏的文件只影响错误视图，且仅在其与错误原因位于同一目录时才有影响
+	errorsDirs := map[string]bool{}
+	for _, errorsFile := range result.errorsFiles {
+		errorsFile.resolveEnumTypes(dirEnumTypes[filepath.Dir(errorsFile.srcPath)])
+		errorsDirs[filepath.Dir(errorsFile.srcPath)] = true
+	}
+	for _, failure := range enumFailures {
+		if errorsDirs[filepath.Dir(failure.path)] {
+			result.errorsFailures = append(result.errorsFailures, failure)
+		}
+	}
+	return result, nil
+}
+
+// fileScanKinds returns the scan kinds reading the file, judged by its suffix
+// Every generated file carries a header, the ones with a known suffix are read by their own kind too
+// and the other .pb.go files by the enum constants
+//
+// fileScanKinds 根据后缀返回读取该文件的扫描种类
+// 每个生成文件都带有头部，带已知后缀的文件还会被其自身的种类读取
+// 其他 .pb.go 文件则被枚举常量种类读取
+func (a *Analyzer) fileScanKinds(path string) apiScanKind {
+	switch {
+	case strings.HasSuffix(path, ".proto"):
+		return scanProtoFiles
+	case strings.HasSuffix(path, a.suffixes.Grpc):
+		return scanGrpcFiles | scanHeaders
+	case strings.HasSuffix(path, a.suffixes.Http):
+		return scanHttpFiles | scanHeaders
+	case strings.HasSuffix(path, a.suffixes.Errors):
+		return scanErrorsFiles | scanHeaders
+	default:
+		return scanHeaders | scanEnumConsts
+	}
+}
+
+// parseApiFile parses one file of the api root according to its suffix, skipping the parts of kinds not asked for
+//
+// parseApiFile 根据后缀解析 api 根目录中的单个文件，跳过未请求种类的部分
+func (a *Analyzer) parseApiFile(apiRoot string, path string, kinds apiScanKind) (*apiScanFile, error) {
+	file := &apiScanFile{}
+	fileKinds := a.fileScanKinds(path) & kinds
+	if fileKinds&scanProtoFiles != 0 {
+		// Record the failure on the file, only the views reading the .proto files report it
+		// 在文件上记录失败，只有读取 .proto 文件的视图才会报告
+		file.protoFile, file.protoErr = ParseProtoFile(path)
+		return file, nil
+	}
+
+	// A header problem is reported as a generated issue, the declarations are still parsed
+	// 头部问题作为生成文件问题报告，声明仍然会被解析
+	if fileKinds&scanHeaders != 0 {
+		file.generatedFile, file.headerErr = parseGeneratedFileHeader(path, apiRoot)
+	}
+	switch {
+	case fileKinds&scanGrpcFiles != 0:
+		grpcFile, err := analyzeGrpcPbGoFile(path)
+		if err != nil {
+			return nil, erero.Wro(err)
+		}
+		file.grpcFile = grpcFile
+	case fileKinds&scanHttpFiles != 0:
+		// The HTTP and error views alone depend on these files, so their failures are kept on the file as well
+		// 只有 HTTP 和错误视图依赖这些文件，因此它们的失败同样保存在文件上
+		file.httpFile, file.httpErr = analyzeHttpPbGoFile(path)
+	case fileKinds&scanErrorsFiles != 0:
+		file.errorsFile, file.errorsErr = analyzeErrorsPbGoFile(path)
+	case fileKinds&scanEnumConsts != 0:
+		file.enumTypes, file.enumErr = analyzeEnumPbGoFile(path)
+	}
+	return file, nil
+}
+
+// checkApiRoot returns the api root when it is an existing directory
+//
+// checkApiRoot 在 api 根目录为已存在的目录时返回该目录
+func checkApiRoot(apiRoot string) (string, error) {
+	info, err := os.Stat(apiRoot)
+	if err != nil {
+		return "", erero.Wrapf(err, "api root %s", apiRoot)
+	}
+	if !info.IsDir() {
+		return "", erero.Errorf("api root %s is not a directory", apiRoot)
+	}
+	return apiRoot, nil
+}
@@ -0,0 +1,92 @@
+package astkratos_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/orzkratos/astkratos"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/rese"
+	"github.com/yyle88/runpath"
+	"go.uber.org/zap"
+)
+
+// TestAnalyzer_Analyze tests that the default analyzer gives the same report as AnalyzeProject
+//
+// TestAnalyzer_Analyze 测试默认分析器给出与 AnalyzeProject 相同的报告
+func TestAnalyzer_Analyze(t *testing.T) {
+	projectRoot := runpath.PARENT.Join("testdata", "demokratos")
+	report, err := astkratos.NewAnalyzer(
+		astkratos.WithLogger(zap.NewNop().Sugar()),
+		astkratos.WithConcurrency(1),
+	).Analyze(context.Background(), projectRoot)
+	require.NoError(t, err)
+	require.Equal(t, []string{demoApiRoot}, report.ApiRoots)
+	require.Equal(t, []string{"Echo", "Greeter", "Legacy"}, collectNames(report.Services))
+
+	expected := astkratos.AnalyzeProject(projectRoot)
+	require.Equal(t, expected.Services, report.Services)
+	require.Equal(t, expected.GeneratedFiles, report.GeneratedFiles)
+	require.Equal(t, expected.GeneratedIssues, report.GeneratedIssues)
+}
+
+// TestAnalyzer_Patterns tests that include and exclude patterns narrow the files of the api root
+//
+// TestAnalyzer_Patterns 测试包含和排除模式会缩小 api 根目录中的文件范围
+func TestAnalyzer_Patterns(t *testing.T) {
+	projectRoot := runpath.PARENT.Join("testdata", "demokratos")
+
+	report, err := astkratos.NewAnalyzer(astkratos.WithExcludePatterns("legacy")).Analyze(context.Background(), projectRoot)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Echo", "Greeter"}, collectNames(report.Services))
+	require.Len(t, report.GeneratedFiles, 4)
+	require.Empty(t, report.GeneratedIssues)
+
+	report, err = astkratos.NewAnalyzer(astkratos.WithIncludePatterns("helloworld", "*.proto")).Analyze(context.Background(), projectRoot)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Greeter"}, collectNames(report.Services))
+	require.Len(t, report.GeneratedFiles, 3)
+
+	_, err = astkratos.NewAnalyzer(astkratos.WithExcludePatterns("[")).Analyze(context.Background(), projectRoot)
+	require.Error(t, err)
+}
+
+// TestAnalyzer_ApiRoots tests several api roots and custom generated suffixes
+//
+// TestAnalyzer_ApiRoots 测试多个 api 根目录和自定义生成文件后缀
+func TestAnalyzer_ApiRoots(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"proto/echo/v1", "third_party/legacy/v1"} {
+		must.Done(os.MkdirAll(filepath.Join(root, dir), 0755))
+	}
+	must.Done(os.WriteFile(filepath.Join(root, "go.mod"), []byte("module shop\n\ngo 1.22\n"), 0644))
+	must.Done(os.WriteFile(filepath.Join(root, "proto", "echo", "v1", "echo.rpc.go"), rese.V1(os.ReadFile(filepath.Join(demoApiRoot, "echo", "v1", "echo_grpc.pb.go"))), 0644))
+	must.Done(os.WriteFile(filepath.Join(root, "third_party", "legacy", "v1", "legacy.rpc.go"), rese.V1(os.ReadFile(filepath.Join(demoApiRoot, "legacy", "v1", "legacy_grpc.pb.go"))), 0644))
+
+	analyzer := astkratos.NewAnalyzer(
+		astkratos.WithApiRoots("proto", filepath.Join(root, "third_party")),
+		astkratos.WithGeneratedSuffixes(astkratos.GeneratedSuffixes{Grpc: ".rpc.go"}),
+	)
+	report, err := analyzer.Analyze(context.Background(), root)
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(root, "proto"), filepath.Join(root, "third_party")}, report.ApiRoots)
+	require.Equal(t, []string{"Echo", "Legacy"}, collectNames(report.Services))
+
+	_, err = astkratos.NewAnalyzer(astkratos.WithApiRoots("missing")).Analyze(context.Background(), root)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), filepath.Join(root, "missing"))
+}
+
+// TestAnalyzer_Canceled tests that a done context stops the analysis with the context error
+//
+// TestAnalyzer_Canceled 测试已结束的上下文会以上下文错误停止分析
+func TestAnalyzer_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := astkratos.NewAnalyzer().Analyze(ctx, runpath.PARENT.Join("testdata", "demokratos"))
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,222 @@
+// Package astkratos api scan: Single-pass collection of generated API definitions
+// Walks the api tree once and parses each generated file exactly once
+// Keeps the per-file extraction results so that every listing view is a cheap filter
+// Shared by AnalyzeProject and the List* functions to avoid repeated IO and parsing
+//
+// astkratos API 扫描：单次遍历收集生成的 API 定义
+// 只遍历一次 api 目录树，每个生成文件只解析一次
+// 保存逐文件的提取结果，使每个列表视图都只是低成本的筛选
+// 由 AnalyzeProject 和 List* 函数共享，避免重复的 IO 和解析
+package astkratos
+
+import (
+	"context"
+
+	"github.com/yyle88/erero"
+	"github.com/yyle88/neatjson/neatjsons"
+	"go.uber.org/zap"
+)
+
+// apiScanResult holds the per-file extraction results of one api tree walk
+//
+// apiScanResult 保存一次 api 目录树遍历的逐文件提取结果
+type apiScanResult struct {
+	grpcFiles   []*grpcPbGoFile   // Parsed _grpc.pb.go files in walk order // 按遍历顺序解析的 _grpc.pb.go 文件
+	httpFiles   []*httpPbGoFile   // Parsed _http.pb.go files in walk order // 按遍历顺序解析的 _http.pb.go 文件
+	errorsFiles []*errorsPbGoFile // Parsed _errors.pb.go files in walk order // 按遍历顺序解析的 _errors.pb.go 文件
+	protoFiles  []*ProtoFile      // Parsed .proto files in walk order // 按遍历顺序解析的 .proto 文件
+
+	httpFailures   []*scanFailure // _http.pb.go files failing to parse in walk order // 按遍历顺序排列的解析失败的 _http.pb.go 文件
+	errorsFailures []*scanFailure // _errors.pb.go files failing to parse in walk order // 按遍历顺序排列的解析失败的 _errors.pb.go 文件
+	protoFailures  []*scanFailure // .proto files failing to parse in walk order // 按遍历顺序排列的解析失败的 .proto 文件
+
+	generatedFiles []*GeneratedFileInfo // Headers of the .pb.go files in walk order // 按遍历顺序排列的 .pb.go 文件头部信息
+	headerFailures []*scanFailure       // .pb.go files whose header could not be read in walk order // 按遍历顺序排列的无法读取头部的 .pb.go 文件
+
+	suffixes GeneratedSuffixes  // Suffixes the files were matched by // 匹配文件所用的后缀
+	logger   *zap.SugaredLogger // Logger of debug output, nil when off // 调试输出的日志记录器，关闭时为 nil
+}
+
+// apiScanKind selects the kinds of files one scan parses, so that each view reads only the files it lists
+//
+// apiScanKind 选择一次扫描解析的文件种类，使每个视图只读取其列出的文件
+type apiScanKind uint8
+
+const (
+	scanGrpcFiles   apiScanKind = 1 << iota // _grpc.pb.go stubs // _grpc.pb.go 存根
+	scanHttpFiles                           // _http.pb.go bindings // _http.pb.go 绑定
+	scanErrorsFiles                         // _errors.pb.go reasons // _errors.pb.go 错误原因
+	scanProtoFiles                          // .proto sources // .proto 源文件
+	scanHeaders                             // Headers of every generated file // 每个生成文件的头部
+	scanEnumConsts                          // Typed constants of the other .pb.go files, naming the enums of the error reasons // 其他 .pb.go 文件中带类型的常量，用于确定错误原因的枚举名称
+
+	scanAllFiles = scanGrpcFiles | scanHttpFiles | scanErrorsFiles | scanProtoFiles | scanHeaders | scanEnumConsts // Every kind, used by the project report // 所有种类，供项目报告使用
+)
+
+// scanFailure records a file of the api tree that could not be read or parsed
+// Kept on the scan instead of aborting it, so that each listing fails on its own files alone
+// and never on the .proto files, the HTTP bindings or the headers of unrelated generated files
+//
+// scanFailure 记录 api 目录树中无法读取或解析的文件
+// 保存在扫描结果中而不是中止扫描，使每个列表只因其自身的文件失败
+// 而不会因 .proto 文件、HTTP 绑定或无关生成文件的头部失败
+type scanFailure struct {
+	path string // Absolute path of the file // 文件的绝对路径
+	err  error  // Error naming the file // 指明该文件的错误
+}
+
+// UnreadFile names a generated file whose declarations could not be parsed, left out of the report
+//
+// UnreadFile 指明声明无法解析而未计入报告的生成文件
+type UnreadFile struct {
+	Path   string `json:"path"`   // Absolute path of the file // 文件的绝对路径
+	Detail string `json:"detail"` // Error met reading the file // 读取文件时遇到的错误
+}
+
+// newUnreadFiles converts the scan failures to unread files
+//
+// newUnreadFiles 将扫描失败转换为未读取文件
+func newUnreadFiles(failures ...[]*scanFailure) []*UnreadFile {
+	unreadFiles := make([]*UnreadFile, 0)
+	for _, group := range failures {
+		for _, failure := range group {
+			unreadFiles = append(unreadFiles, &UnreadFile{Path: failure.path, Detail: failure.err.Error()})
+		}
+	}
+	return unreadFiles
+}
+
+// scanApiFiles walks the root path once and parses the generated files of the given kinds with the default analyzer
+//
+// scanApiFiles 使用默认分析器遍历根目录一次并解析给定种类的生成文件
+func scanApiFiles(root string, kinds apiScanKind) (*apiScanResult, error) {
+	return NewAnalyzer().scanApiFiles(context.Background(), []string{root}, kinds)
+}
+
+// listClients returns the gRPC client interfaces of the scanned files
+//
+// listClients 返回已扫描文件中的 gRPC 客户端接口
+func (r *apiScanResult) listClients() []*GrpcTypeDefinition {
+	definitions := make([]*GrpcTypeDefinition, 0)
+	for _, grpcFile := range r.grpcFiles {
+		definitions = append(definitions, grpcFile.clients...)
+	}
+	return definitions
+}
+
+// listServers returns the gRPC server interfaces of the scanned files
+//
+// listServers 返回已扫描文件中的 gRPC 服务器接口
+func (r *apiScanResult) listServers() []*GrpcTypeDefinition {
+	definitions := make([]*GrpcTypeDefinition, 0)
+	for _, grpcFile := range r.grpcFiles {
+		definitions = append(definitions, grpcFile.servers...)
+	}
+	return definitions
+}
+
+// listUnimplementedServers returns the unimplemented gRPC server stubs of the scanned files
+//
+// listUnimplementedServers 返回已扫描文件中的未实现 gRPC 服务器存根
+func (r *apiScanResult) listUnimplementedServers() []*GrpcTypeDefinition {
+	definitions := make([]*GrpcTypeDefinition, 0)
+	for _, grpcFile := range r.grpcFiles {
+		definitions = append(definitions, grpcFile.unimplementedServers...)
+	}
+
+	if r.logger != nil {
+		r.logger.Debugln("discovered unimplemented server definitions:", neatjsons.S(definitions))
+	}
+	return definitions
+}
+
+// listServices returns the gRPC services derived from the unimplemented server stubs, with RPC methods
+//
+// listServices 返回从未实现服务器存根推导出的 gRPC 服务，包含 RPC 方法
+func (r *apiScanResult) listServices() []*GrpcTypeDefinition {
+	definitions := make([]*GrpcTypeDefinition, 0)
+	for _, grpcFile := range r.grpcFiles {
+		for _, service := range grpcFile.services {
+			if r.logger != nil {
+				r.logger.Debugln("identified service:", service.Name, "within package:", service.Package)
+			}
+			definitions = append(definitions, service)
+		}
+	}
+
+	if r.logger != nil {
+		r.logger.Debugln("resolved service definitions:", neatjsons.S(definitions))
+	}
+	return definitions
+}
+
+// listServiceDescriptors returns the decoded grpc.ServiceDesc variables of the scanned files
+//
+// listServiceDescriptors 返回已扫描文件中解码后的 grpc.ServiceDesc 变量
+func (r *apiScanResult) listServiceDescriptors() []*ServiceDescriptor {
+	descriptors := make([]*ServiceDescriptor, 0)
+	for _, grpcFile := range r.grpcFiles {
+		descriptors = append(descriptors, grpcFile.serviceDescs...)
+	}
+	return descriptors
+}
+
+// listHttpRoutes returns the HTTP routes of the scanned _http.pb.go files
+// Returns the first parse failure in walk order, since the caller asked for every route
+//
+// listHttpRoutes 返回已扫描 _http.pb.go 文件中的 HTTP 路由
+// 返回按遍历顺序的第一个解析失败，因为调用方需要每条路由
+func (r *apiScanResult) listHttpRoutes() ([]*HttpRouteDefinition, error) {
+	if len(r.httpFailures) > 0 {
+		return nil, erero.Wro(r.httpFailures[0].err)
+	}
+	return r.collectHttpRoutes(), nil
+}
+
+// collectHttpRoutes returns the HTTP routes of the _http.pb.go files that could be parsed
+//
+// collectHttpRoutes 返回可解析的 _http.pb.go 文件中的 HTTP 路由
+func (r *apiScanResult) collectHttpRoutes() []*HttpRouteDefinition {
+	routes := make([]*HttpRouteDefinition, 0)
+	for _, httpFile := range r.httpFiles {
+		routes = append(routes, httpFile.routes...)
+	}
+	return routes
+}
+
+// listErrorReasons returns the error reasons of the scanned _errors.pb.go files
+// Returns the first parse failure in walk order, since the caller asked for every reason
+//
+// listErrorReasons 返回已扫描 _errors.pb.go 文件中的错误原因
+// 返回按遍历顺序的第一个解析失败，因为调用方需要每个错误原因
+func (r *apiScanResult) listErrorReasons() ([]*ErrorReasonDefinition, error) {
+	if len(r.errorsFailures) > 0 {
+		return nil, erero.Wro(r.errorsFailures[0].err)
+	}
+	return r.collectErrorReasons(), nil
+}
+
+// collectErrorReasons returns the error reasons of the _errors.pb.go files that could be parsed
+//
+// collectErrorReasons 返回可解析的 _errors.pb.go 文件中的错误原因
+func (r *apiScanResult) collectErrorReasons() []*ErrorReasonDefinition {
+	reasons := make([]*ErrorReasonDefinition, 0)
+	for _, errorsFile := range r.errorsFiles {
+		reasons = append(reasons, errorsFile.reasons...)
+	}
+	return reasons
+}
+
+// listProtoFiles returns the parsed .proto files of the scan
+// Returns the first parse failure in walk order, since the caller asked for every .proto file
+//
+// listProtoFiles 返回扫描中解析的 .proto 文件
+// 返回按遍历顺序的第一个解析失败，因为调用方需要每个 .proto 文件
+func (r *apiScanResult) listProtoFiles() ([]*ProtoFile, error) {
+	if len(r.protoFailures) > 0 {
+		return nil, erero.Wro(r.protoFailures[0].err)
+	}
+	protoFiles := make([]*ProtoFile, 0)
+	protoFiles = append(protoFiles, r.protoFiles...)
+	return protoFiles, nil
+}
@@ -0,0 +1,43 @@
+package astkratos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+	"github.com/yyle88/runpath"
+)
+
+// TestScanApiFiles tests that one walk parses each generated file once and serves every view
+//
+// TestScanApiFiles 测试一次遍历只解析每个生成文件一次并支持所有视图
+func TestScanApiFiles(t *testing.T) {
+	apiScan := rese.P1(scanApiFiles(runpath.PARENT.Join("testdata", "demokratos", "api"), scanAllFiles))
+	require.Len(t, apiScan.grpcFiles, 3)
+	require.Len(t, apiScan.protoFiles, 4)
+	require.Len(t, apiScan.listClients(), 6)
+	require.Len(t, apiScan.listServers(), 6)
+	require.Len(t, apiScan.listUnimplementedServers(), 3)
+
+	services := apiScan.listServices()
+	require.Len(t, services, 3)
+	require.Equal(t, "Echo", services[0].Name)
+	require.Equal(t, apiScan.listUnimplementedServers()[0].SrcPath, services[0].SrcPath)
+}
+
+// TestScanApiFiles_Kinds tests that a scan parses the files of the asked kinds alone
+//
+// TestScanApiFiles_Kinds 测试扫描只解析所请求种类的文件
+func TestScanApiFiles_Kinds(t *testing.T) {
+	apiScan := rese.P1(scanApiFiles(runpath.PARENT.Join("testdata", "demokratos", "api"), scanGrpcFiles))
+	require.Len(t, apiScan.grpcFiles, 3)
+	require.Empty(t, apiScan.httpFiles)
+	require.Empty(t, apiScan.errorsFiles)
+	require.Empty(t, apiScan.protoFiles)
+	require.Empty(t, apiScan.generatedFiles)
+
+	apiScan = rese.P1(scanApiFiles(runpath.PARENT.Join("testdata", "demokratos", "api"), scanHeaders))
+	require.Empty(t, apiScan.grpcFiles)
+	require.Empty(t, apiScan.protoFiles)
+	require.Len(t, apiScan.generatedFiles, 5)
+}
@@ -11,19 +11,8 @@ package astkratos
 
 import (
 	"go/ast"
-	"os"
-	"path/filepath"
-	"strings"
-
-	"github.com/orzkratos/astkratos/internal/utils"
-	"github.com/yyle88/must"
-	"github.com/yyle88/neatjson/neatjsons"
-	"github.com/yyle88/osexistpath/osmustexist"
+
 	"github.com/yyle88/rese"
-	"github.com/yyle88/syntaxgo/syntaxgo_ast"
-	"github.com/yyle88/syntaxgo/syntaxgo_astnode"
-	"github.com/yyle88/syntaxgo/syntaxgo_search"
-	"github.com/yyle88/zaplog"
 )
 
 // GrpcTypeDefinition represents a gRPC type definition with its name and package
@@ -33,155 +22,205 @@ type GrpcTypeDefinition struct {
 	Name    string // Name of the gRPC type // gRPC 类型名称
 	Package string // Package name where the type is defined // 类型定义所在的包名
 	SrcPath string // Source file path where the type is defined // 类型定义所在的源文件路径
+
+	Methods []*GrpcMethodDefinition `json:"Methods,omitempty"` // RPC methods, set on services // RPC 方法，仅服务包含
 }
 
 // ListGrpcClients lists gRPC client types in the specified root path
 //
 // ListGrpcClients 列出指定根目录下的 gRPC 客户端类型
 func ListGrpcClients(root string) (definitions []*GrpcTypeDefinition) {
-	definitions = make([]*GrpcTypeDefinition, 0)
-
-	must.Done(utils.WalkFiles(root, utils.NewSuffixPattern([]string{"_grpc.pb.go"}), func(path string, info os.FileInfo) error {
-		// Get the package name from the file path
-		// 从文件路径获取包名
-		pkgName := syntaxgo_ast.GetPackageNameFromPath(path)
-		// Read and trim lines from the file
-		// 读取并修剪文件中的行
-		lines := rese.V1(utils.GetTrimmedLines(path))
-		for _, s := range lines {
-			// Check if the line defines a gRPC client interface
-			// 检查该行是否定义了 gRPC 客户端接口
-			if strings.HasPrefix(s, "type ") && strings.HasSuffix(s, "Client interface {") {
-				name := utils.GetSubstringBetween(s, "type ", " interface {")
-				// Append the gRPC client definition to the list
-				// 将 gRPC 客户端定义添加到列表中
-				definitions = append(definitions, &GrpcTypeDefinition{
-					Name:    name,
-					Package: pkgName,
-					SrcPath: rese.V1(filepath.Abs(path)),
-				})
-			}
-		}
-		return nil
-	}))
-	return definitions
+	return rese.V1(ListGrpcClientsE(root))
 }
 
 // ListGrpcServers lists gRPC server types in the specified root path
 //
 // ListGrpcServers 列出指定根目录下的 gRPC 服务器类型
 func ListGrpcServers(root string) (definitions []*GrpcTypeDefinition) {
-	definitions = make([]*GrpcTypeDefinition, 0)
-
-	must.Done(utils.WalkFiles(root, utils.NewSuffixPattern([]string{"_grpc.pb.go"}), func(path string, info os.FileInfo) error {
-		// Get the package name from the file path
-		// 从文件路径获取包名
-		pkgName := syntaxgo_ast.GetPackageNameFromPath(path)
-		// Read and trim lines from the file
-		// 读取并修剪文件中的行
-		lines := rese.V1(utils.GetTrimmedLines(path))
-		for _, s := range lines {
-			// Check if the line defines a gRPC server interface
-			// 检查该行是否定义了 gRPC 服务器接口
-			if strings.HasPrefix(s, "type ") && strings.HasSuffix(s, "Server interface {") && !strings.HasPrefix(s, "type Unsafe") {
-				name := utils.GetSubstringBetween(s, "type ", " interface {")
-				// Append the gRPC server definition to the list
-				// 将 gRPC 服务器定义添加到列表中
-				definitions = append(definitions, &GrpcTypeDefinition{
-					Name:    name,
-					Package: pkgName,
-					SrcPath: rese.V1(filepath.Abs(path)),
-				})
-			}
-		}
-		return nil
-	}))
-	return definitions
+	return rese.V1(ListGrpcServersE(root))
 }
 
 // ListGrpcUnimplementedServers lists unimplemented gRPC server types in the specified root path
 //
 // ListGrpcUnimplementedServers 列出指定根目录下的未实现 gRPC 服务器类型
 func ListGrpcUnimplementedServers(root string) (definitions []*GrpcTypeDefinition) {
-	if debugModeOpen {
-		zaplog.SUG.Debugln("discovering unimplemented gRPC servers in project:", root)
-	}
-
-	definitions = make([]*GrpcTypeDefinition, 0)
-
-	must.Done(utils.WalkFiles(root, utils.NewSuffixPattern([]string{"_grpc.pb.go"}), func(path string, info os.FileInfo) error {
-		if debugModeOpen {
-			zaplog.SUG.Debugln("examining generated protobuf source:", path)
-		}
-		// Get the package name from the file path
-		// 从文件路径获取包名
-		pkgName := syntaxgo_ast.GetPackageNameFromPath(path)
-		// Read and trim lines from the file
-		// 读取并修剪文件中的行
-		lines := rese.V1(utils.GetTrimmedLines(path))
-		for _, s := range lines {
-			// Check if the line defines an unimplemented gRPC server
-			// 检查该行是否定义了未实现的 gRPC 服务器
-			if strings.HasPrefix(s, "type Unimplemented") {
-				var name string
-				switch {
-				case strings.HasSuffix(s, "Server struct {"): // Old version // 旧版本格式
-					name = utils.GetSubstringBetween(s, "type ", " struct {")
-					must.OK(name)
-				case strings.HasSuffix(s, "Server struct{}"): // New version // 新版本格式
-					name = utils.GetSubstringBetween(s, "type ", " struct{}")
-					must.OK(name)
-				}
-				if name != "" { // Match old version or new version // 匹配旧版本或新版本格式
-					// Append the unimplemented gRPC server definition to the list
-					// 将未实现的 gRPC 服务器定义添加到列表中
-					definitions = append(definitions, &GrpcTypeDefinition{
-						Name:    name,
-						Package: pkgName,
-						SrcPath: rese.V1(filepath.Abs(path)),
-					})
-				}
-			}
-		}
-		return nil
-	}))
-
-	if debugModeOpen {
-		zaplog.SUG.Debugln("discovered unimplemented server definitions:", neatjsons.S(definitions))
-	}
-	return definitions
+	return rese.V1(ListGrpcUnimplementedServersE(root))
 }
 
 // ListGrpcServices lists gRPC services in the specified root path
 //
 // ListGrpcServices 列出指定根目录下的 gRPC 服务
 func ListGrpcServices(root string) (definitions []*GrpcTypeDefinition) {
-	if debugModeOpen {
-		zaplog.SUG.Debugln("resolving service definitions in project:", root)
-	}
-
-	definitions = make([]*GrpcTypeDefinition, 0)
-	// Iterate through unimplemented gRPC servers and extract service names
-	// 遍历未实现的 gRPC 服务器并提取服务名称
-	for _, unimplement := range ListGrpcUnimplementedServers(root) {
-		if debugModeOpen {
-			zaplog.SUG.Debugln("identified service:", unimplement.Name, "within package:", unimplement.Package)
-		}
-		serviceName := utils.GetSubstringBetween(unimplement.Name, "Unimplemented", "Server")
-		must.OK(serviceName)
-		// Append the gRPC service definition to the list
-		// 将 gRPC 服务定义添加到列表中
-		definitions = append(definitions, &GrpcTypeDefinition{
-			Name:    serviceName,
-			Package: unimplement.Package,
-			SrcPath: unimplement.SrcPath,
-		})
-	}
-
-	if debugModeOpen {
-		zaplog.SUG.Debugln("resolved service definitions:", neatjsons.S(definitions))
-	}
-	return definitions
+	return rese.V1(ListGrpcServicesE(root))
+}
+
+// ListGrpcServiceDescriptors lists decoded grpc.ServiceDesc variables in the specified root path
+// Provides the fully qualified proto service name and source proto path of each service
+//
+// ListGrpcServiceDescriptors 列出指定根目录下解码后的 grpc.ServiceDesc 变量
+// 提供每个服务的完整 proto 服务名和源 proto 文件路径
+func ListGrpcServiceDescriptors(root string) []*ServiceDescriptor {
+	return rese.V1(ListGrpcServiceDescriptorsE(root))
+}
+
+// ListHttpRoutes lists HTTP routes registered by the generated _http.pb.go files in the specified root path
+// Returns the verb, path template, operation, handler and owning service of each route
+//
+// ListHttpRoutes 列出指定根目录下生成的 _http.pb.go 文件注册的 HTTP 路由
+// 返回每条路由的 HTTP 方法、路径模板、操作、处理函数和所属服务
+func ListHttpRoutes(root string) []*HttpRouteDefinition {
+	return rese.V1(ListHttpRoutesE(root))
+}
+
+// ListErrorReasons lists Kratos error reasons generated into _errors.pb.go files in the specified root path
+// Returns the enum value, HTTP status code, package and both helper function names of each reason
+//
+// ListErrorReasons 列出指定根目录下生成到 _errors.pb.go 文件中的 Kratos 错误原因
+// 返回每个错误原因的枚举值、HTTP 状态码、包名和两个辅助函数名称
+func ListErrorReasons(root string) []*ErrorReasonDefinition {
+	return rese.V1(ListErrorReasonsE(root))
+}
+
+// ListProtoFiles lists the .proto files in the specified root path, parsed without protoc
+// Works even when the generated Go code is missing or out of date
+//
+// ListProtoFiles 列出指定根目录下的 .proto 文件，无需 protoc 即可解析
+// 即使生成的 Go 代码缺失或过期也能正常工作
+func ListProtoFiles(root string) []*ProtoFile {
+	return rese.V1(ListProtoFilesE(root))
+}
+
+// CheckProtoDrift compares the .proto files in the specified root path with their generated Go code
+// Returns the services, RPCs and HTTP bindings that are missing, extra or changed, empty when in sync
+//
+// CheckProtoDrift 比较指定根目录下的 .proto 文件与其生成的 Go 代码
+// 返回缺失、多余或已变更的服务、RPC 和 HTTP 绑定，同步时返回空列表
+func CheckProtoDrift(root string) []*ProtoDrift {
+	return rese.V1(CheckProtoDriftE(root))
+}
+
+// ListGeneratedFiles lists the headers of the generated .pb.go files in the specified root path
+// Returns the generator, the versions block and the source .proto of each file
+//
+// ListGeneratedFiles 列出指定根目录下生成的 .pb.go 文件头部信息
+// 返回每个文件的生成器、versions 块和源 .proto
+func ListGeneratedFiles(root string) []*GeneratedFileInfo {
+	return rese.V1(ListGeneratedFilesE(root))
+}
+
+// CheckGeneratedFiles reports generators used at mixed versions and generated files whose source .proto is gone
+// Versions differing from the one most files use are listed at their header lines
+//
+// CheckGeneratedFiles 报告以不同版本使用的生成器以及源 .proto 已不存在的生成文件
+// 与多数文件所用版本不同的版本会连同其头部行号一起列出
+func CheckGeneratedFiles(root string) []*GeneratedIssue {
+	return rese.V1(CheckGeneratedFilesE(root))
+}
+
+// ListServiceImplementations maps the gRPC services of the project to the structs in internal/service
+// Links each service to its implementation struct, its NewXxxService constructor and its source file
+//
+// ListServiceImplementations 将项目中的 gRPC 服务映射到 internal/service 中的结构体
+// 将每个服务关联到其实现结构体、NewXxxService 构造函数和源文件
+func ListServiceImplementations(projectRoot string) []*ServiceImplementation {
+	return rese.V1(ListServiceImplementationsE(projectRoot))
+}
+
+// ListServiceCoverage reports the RPCs of each service implementation that fall back to the Unimplemented stub
+// Those RPCs return codes.Unimplemented at runtime, each one is listed with its position
+//
+// ListServiceCoverage 报告每个服务实现中回退到 Unimplemented 存根的 RPC
+// 这些 RPC 在运行时返回 codes.Unimplemented，每个都连同位置一起列出
+func ListServiceCoverage(projectRoot string) []*ServiceCoverage {
+	return rese.V1(ListServiceCoverageE(projectRoot))
+}
+
+// ListServiceExposures reports whether each gRPC service is registered in internal/server
+// Tells for each service whether it is served over gRPC, over HTTP, over both or over neither
+//
+// ListServiceExposures 报告每个 gRPC 服务是否在 internal/server 中注册
+// 说明每个服务是通过 gRPC、HTTP、两者还是都未对外提供
+func ListServiceExposures(projectRoot string) []*ServiceExposure {
+	return rese.V1(ListServiceExposuresE(projectRoot))
+}
+
+// GetWireGraph extracts the wire ProviderSets and injectors of the project without running wire
+// Links each provider to its package and constructor signature and flattens the sets of each injector
+//
+// GetWireGraph 在不运行 wire 的情况下提取项目中的 wire ProviderSet 和注入器
+// 将每个提供者关联到其包和构造函数签名，并展开每个注入器引用的集合
+func GetWireGraph(projectRoot string) *WireGraph {
+	return rese.P1(GetWireGraphE(projectRoot))
+}
+
+// GetDependencyGraph builds the constructor graph over the NewXxx functions in internal/biz, data, service and server
+// Reports cycles and unsatisfied param types before wire does, the graph also renders as DOT
+//
+// GetDependencyGraph 基于 internal/biz、data、service 和 server 中的 NewXxx 函数构建构造函数图
+// 在 wire 之前报告循环依赖和无法满足的参数类型，该图也可渲染为 DOT
+func GetDependencyGraph(projectRoot string) *DependencyGraph {
+	return rese.P1(GetDependencyGraphE(projectRoot))
+}
+
+// ListRepoImplementations links the repo interfaces of internal/biz to the structs of internal/data
+// Reports interfaces without implementation and methods that are missing or have mismatched signatures
+//
+// ListRepoImplementations 将 internal/biz 的仓储接口关联到 internal/data 的结构体
+// 报告没有实现的接口以及缺失或签名不一致的方法
+func ListRepoImplementations(projectRoot string) []*RepoImplementation {
+	return rese.V1(ListRepoImplementationsE(projectRoot))
+}
+
+// GetConfigSchema extracts the configuration tree of the app from the .proto files in internal/conf
+// Expands Bootstrap into nested fields with JSON names, types and durations, nil without internal/conf
+//
+// GetConfigSchema 从 internal/conf 的 .proto 文件中提取应用的配置树
+// 将 Bootstrap 展开为带有 JSON 名称、类型和时长的嵌套字段，没有 internal/conf 时返回 nil
+func GetConfigSchema(projectRoot string) *ConfigSchema {
+	return rese.V1(GetConfigSchemaE(projectRoot))
+}
+
+// CheckConfigFiles checks the YAML files in configs against the configuration schema of internal/conf
+// Reports unknown keys, wrong value types and missing root sections with line numbers
+//
+// CheckConfigFiles 根据 internal/conf 的配置结构检查 configs 中的 YAML 文件
+// 报告未知的键、错误的值类型以及缺失的根配置段，并附带行号
+func CheckConfigFiles(projectRoot string) []*ConfigIssue {
+	return rese.V1(CheckConfigFilesE(projectRoot))
+}
+
+// CheckLayering checks the imports of the layers against the default Kratos layering policy
+// Reports biz importing data or service, service importing data and other forbidden imports
+//
+// CheckLayering 根据默认的 Kratos 分层策略检查各层的导入
+// 报告 biz 导入 data 或 service、service 导入 data 等被禁止的导入
+func CheckLayering(projectRoot string) []*LayerViolation {
+	return CheckLayeringWithPolicy(projectRoot, DefaultLayeringPolicy())
+}
+
+// CheckLayeringWithPolicy checks the imports of the layers against a custom layering policy
+//
+// CheckLayeringWithPolicy 根据自定义分层策略检查各层的导入
+func CheckLayeringWithPolicy(projectRoot string, policy *LayeringPolicy) []*LayerViolation {
+	return rese.V1(CheckLayeringWithPolicyE(projectRoot, policy))
+}
+
+// CheckKratosUpgrade builds the per-file checklist of usages to revisit before bumping Kratos
+// Covers deprecated transport and middleware options, old log helpers and old or pointer embedded stubs
+//
+// CheckKratosUpgrade 构建升级 Kratos 前需要重新检查的用法的逐文件清单
+// 涵盖已弃用的传输和中间件选项、旧的日志辅助函数以及旧的或指针嵌入的存根
+func CheckKratosUpgrade(projectRoot string) *UpgradeChecklist {
+	return rese.P1(CheckKratosUpgradeE(projectRoot))
+}
+
+// CheckWireStaleness compares wire_gen.go with wire.go and the ProviderSets it references
+// Reports providers never called in wire_gen.go, calls of removed providers and changed signatures
+//
+// CheckWireStaleness 比较 wire_gen.go 与 wire.go 及其引用的 ProviderSet
+// 报告 wire_gen.go 中从未调用的提供者、已移除提供者的调用以及变更的签名
+func CheckWireStaleness(projectRoot string) []*WireStaleness {
+	return rese.V1(CheckWireStalenessE(projectRoot))
 }
 
 // StructDefinition represents a struct definition with its name, type, source code, and code snippet
@@ -198,44 +237,7 @@ type StructDefinition struct {
 //
 // GetStructsMap 获取指定文件中的结构体定义并返回映射表
 func GetStructsMap(path string) map[string]*StructDefinition {
-	if debugModeOpen {
-		zaplog.SUG.Debugln("parsing Go struct definitions from:", path)
-	}
-
-	structMap := map[string]*StructDefinition{}
-
-	// Read the entire source code of the file
-	// 读取文件的完整源代码
-	fileSource := rese.V1(os.ReadFile(path))
-	// Parse the source code into an AST bundle
-	// 将源代码解析为 AST 包
-	astBundle := rese.P1(syntaxgo_ast.NewAstBundleV1(fileSource))
-	astFile, _ := astBundle.GetBundle()
-
-	// Map struct types based on name
-	// 按名称映射结构体类型
-	structTypes := syntaxgo_search.MapStructTypesByName(astFile)
-	for structName, structType := range structTypes {
-		// Get the code snippet defining the struct
-		// 获取定义结构体的代码片段
-		structCode := syntaxgo_astnode.GetText(fileSource, structType)
-		if debugModeOpen {
-			zaplog.SUG.Debugln("extracted struct:", structName, "with source:", structCode)
-		}
-		// Add the struct definition to the map
-		// 将结构体定义添加到映射表中
-		structMap[structName] = &StructDefinition{
-			Name:       structName,
-			Type:       structType,
-			FileSource: fileSource,
-			StructCode: structCode,
-		}
-	}
-
-	if debugModeOpen {
-		zaplog.SUG.Debugln("struct parsing completed, discovered", len(structMap), "definitions")
-	}
-	return structMap
+	return rese.V1(GetStructsMapE(path))
 }
 
 // HasGrpcClients checks if gRPC clients exist in the specified root path
@@ -244,7 +246,7 @@ func GetStructsMap(path string) map[string]*StructDefinition {
 // HasGrpcClients 检查指定根目录下是否存在 gRPC 客户端
 // 找到至少一个 gRPC 客户端时返回 true
 func HasGrpcClients(root string) bool {
-	return len(ListGrpcClients(root)) > 0
+	return rese.V1(HasGrpcClientsE(root))
 }
 
 // HasGrpcServers checks if gRPC services exist in the specified root path
@@ -253,7 +255,7 @@ func HasGrpcClients(root string) bool {
 // HasGrpcServers 检查指定根目录下是否存在 gRPC 服务器
 // 找到至少一个 gRPC 服务器时返回 true
 func HasGrpcServers(root string) bool {
-	return len(ListGrpcServers(root)) > 0
+	return rese.V1(HasGrpcServersE(root))
 }
 
 // CountGrpcServices returns the count of gRPC services in the specified root path
@@ -262,7 +264,18 @@ func HasGrpcServers(root string) bool {
 // CountGrpcServices 返回指定根目录下 gRPC 服务的总数
 // 提供快速统计信息而无需返回完整的服务列表
 func CountGrpcServices(root string) int {
-	return len(ListGrpcServices(root))
+	return rese.V1(CountGrpcServicesE(root))
+}
+
+// AnalyzeWorkspace finds every Kratos app under the root and analyzes each of them
+// An app is a directory holding cmd and internal/server, governed by the nearest go.mod above it
+// Apps without their own api use the matching subtree of an api tree found above them
+//
+// AnalyzeWorkspace 查找根目录下的每个 Kratos 应用并逐个分析
+// 应用是包含 cmd 和 internal/server 的目录，由其上层最近的 go.mod 管理
+// 没有自己 api 的应用使用在上层找到的 api 目录树中对应的子树
+func AnalyzeWorkspace(root string) *WorkspaceReport {
+	return rese.P1(AnalyzeWorkspaceE(root))
 }
 
 // ProjectReport provides comprehensive Kratos project analysis results
@@ -271,10 +284,29 @@ func CountGrpcServices(root string) int {
 // ProjectReport 提供全面的 Kratos 项目分析结果
 // 聚合分析数据，包括 gRPC 服务、模块信息和文件统计
 type ProjectReport struct {
-	ModuleInfo *ModuleInfo           `json:"moduleInfo"` // Module and dependency information // 模块和依赖信息
-	Clients    []*GrpcTypeDefinition `json:"clients"`    // List of gRPC clients // gRPC 客户端列表
-	Servers    []*GrpcTypeDefinition `json:"servers"`    // List of gRPC servers // gRPC 服务器列表
-	Services   []*GrpcTypeDefinition `json:"services"`   // List of gRPC services // gRPC 服务列表
+	ModuleInfo      *ModuleInfo              `json:"moduleInfo"`      // Module and dependency information // 模块和依赖信息
+	ApiRoots        []string                 `json:"apiRoots"`        // Api roots scanned // 已扫描的 api 根目录
+	Clients         []*GrpcTypeDefinition    `json:"clients"`         // List of gRPC clients // gRPC 客户端列表
+	Servers         []*GrpcTypeDefinition    `json:"servers"`         // List of gRPC servers // gRPC 服务器列表
+	Services        []*GrpcTypeDefinition    `json:"services"`        // List of gRPC services // gRPC 服务列表
+	ServiceDescs    []*ServiceDescriptor     `json:"serviceDescs"`    // Decoded grpc.ServiceDesc variables // 解码后的 grpc.ServiceDesc 变量
+	HttpRoutes      []*HttpRouteDefinition   `json:"httpRoutes"`      // HTTP routes from _http.pb.go files // 来自 _http.pb.go 文件的 HTTP 路由
+	ErrorReasons    []*ErrorReasonDefinition `json:"errorReasons"`    // Error reasons from _errors.pb.go files // 来自 _errors.pb.go 文件的错误原因
+	ProtoDrifts     []*ProtoDrift            `json:"protoDrifts"`     // Differences between .proto files and generated code // .proto 文件与生成代码之间的差异
+	GeneratedFiles  []*GeneratedFileInfo     `json:"generatedFiles"`  // Headers of the generated .pb.go files // 生成的 .pb.go 文件头部信息
+	GeneratedIssues []*GeneratedIssue        `json:"generatedIssues"` // Mixed generator versions and missing source protos // 不一致的生成器版本和缺失的源 proto
+	Implementations []*ServiceImplementation `json:"implementations"` // Service implementation structs in internal/service // internal/service 中的服务实现结构体
+	Coverage        []*ServiceCoverage       `json:"coverage"`        // RPCs falling back to the Unimplemented stub // 回退到 Unimplemented 存根的 RPC
+	Exposures       []*ServiceExposure       `json:"exposures"`       // Exposure matrix of the services // 服务的暴露矩阵
+	WireGraph       *WireGraph               `json:"wireGraph"`       // Wire ProviderSets and injectors // wire ProviderSet 和注入器
+	WireStaleness   []*WireStaleness         `json:"wireStaleness"`   // Differences between wire_gen.go and the providers // wire_gen.go 与提供者之间的差异
+	DependencyGraph *DependencyGraph         `json:"dependencyGraph"` // Constructor graph of the layers // 各层的构造函数图
+	LayerViolations []*LayerViolation        `json:"layerViolations"` // Imports forbidden by the default layering policy // 默认分层策略禁止的导入
+	Repositories    []*RepoImplementation    `json:"repositories"`    // Biz repo interfaces and their data implementations // biz 仓储接口及其 data 实现
+	ConfigSchema    *ConfigSchema            `json:"configSchema"`    // Configuration tree from internal/conf // 来自 internal/conf 的配置树
+	ConfigIssues    []*ConfigIssue           `json:"configIssues"`    // Problems in configs/*.yaml // configs/*.yaml 中的问题
+	Upgrade         *UpgradeChecklist        `json:"upgrade"`         // Usages to revisit before bumping Kratos // 升级 Kratos 前需要重新检查的用法
+	UnreadFiles     []*UnreadFile            `json:"unreadFiles"`     // _http.pb.go and _errors.pb.go files that could not be parsed // 无法解析的 _http.pb.go 和 _errors.pb.go 文件
 }
 
 // AnalyzeProject performs comprehensive Kratos project analysis
@@ -285,20 +317,5 @@ type ProjectReport struct {
 // 扫描 gRPC 定义并在一次操作中提取完整的模块信息
 // 返回包含发现组件和元数据的聚合项目分析
 func AnalyzeProject(projectRoot string) *ProjectReport {
-	// Get module information first
-	// 首先获取模块信息
-	moduleInfo := rese.P1(GetModuleInfo(projectRoot))
-
-	// Analyze gRPC components in API path
-	// 分析 API 目录中的 gRPC 组件
-	apiPath := osmustexist.ROOT(filepath.Join(projectRoot, "api"))
-
-	// Build comprehensive report
-	// 构建全面报告
-	return &ProjectReport{
-		ModuleInfo: moduleInfo,
-		Clients:    ListGrpcClients(apiPath),
-		Servers:    ListGrpcServers(apiPath),
-		Services:   ListGrpcServices(apiPath),
-	}
+	return rese.P1(AnalyzeProjectE(projectRoot))
 }
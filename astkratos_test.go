@@ -1,17 +1,110 @@
-//go:build ignore
-
 package astkratos_test
 
-// Test file marked as ignored - unit tests need complete Kratos project setup
-// See internal/demos/ DIR to run actual tests with real projects
-//
-// 测试文件标记为忽略 - 单元测试需要完整的 Kratos 项目环境
-// 请查看 internal/demos/ 目录运行真实项目的功能测试
-
 import (
 	"testing"
+
+	"github.com/orzkratos/astkratos"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/neatjson/neatjsons"
+	"github.com/yyle88/runpath"
 )
 
-func TestMain(m *testing.M) {
-	m.Run()
+// demoApiRoot is the api DIR of the demo Kratos project in testdata
+//
+// demoApiRoot 是 testdata 中演示 Kratos 项目的 api 目录
+var demoApiRoot = runpath.PARENT.Join("testdata", "demokratos", "api")
+
+// collectNames returns the names of the definitions in order
+//
+// collectNames 按顺序返回定义的名称
+func collectNames(definitions []*astkratos.GrpcTypeDefinition) []string {
+	names := make([]string, 0, len(definitions))
+	for _, definition := range definitions {
+		names = append(names, definition.Name)
+	}
+	return names
+}
+
+// TestListGrpcClients tests gRPC client interface discovery
+//
+// TestListGrpcClients 测试 gRPC 客户端接口发现
+func TestListGrpcClients(t *testing.T) {
+	definitions := astkratos.ListGrpcClients(demoApiRoot)
+	t.Log(neatjsons.S(definitions))
+	require.Equal(t, []string{
+		"EchoClient",
+		"GreeterClient",
+		"LegacyClient",
+		"Legacy_TailClient",
+		"Legacy_UploadClient",
+		"Legacy_SyncClient",
+	}, collectNames(definitions))
+	require.Equal(t, "v1", definitions[0].Package)
+}
+
+// TestListGrpcServers tests gRPC server interface discovery
+//
+// TestListGrpcServers 测试 gRPC 服务器接口发现
+func TestListGrpcServers(t *testing.T) {
+	definitions := astkratos.ListGrpcServers(demoApiRoot)
+	t.Log(neatjsons.S(definitions))
+	require.Equal(t, []string{
+		"EchoServer",
+		"GreeterServer",
+		"LegacyServer",
+		"Legacy_TailServer",
+		"Legacy_UploadServer",
+		"Legacy_SyncServer",
+	}, collectNames(definitions))
+}
+
+// TestListGrpcUnimplementedServers tests unimplemented server stub discovery
+//
+// TestListGrpcUnimplementedServers 测试未实现服务器存根发现
+func TestListGrpcUnimplementedServers(t *testing.T) {
+	definitions := astkratos.ListGrpcUnimplementedServers(demoApiRoot)
+	t.Log(neatjsons.S(definitions))
+	require.Equal(t, []string{
+		"UnimplementedEchoServer",
+		"UnimplementedGreeterServer",
+		"UnimplementedLegacyServer",
+	}, collectNames(definitions))
+}
+
+// TestListGrpcServices tests gRPC service name resolution
+//
+// TestListGrpcServices 测试 gRPC 服务名称解析
+func TestListGrpcServices(t *testing.T) {
+	definitions := astkratos.ListGrpcServices(demoApiRoot)
+	t.Log(neatjsons.S(definitions))
+	require.Equal(t, []string{"Echo", "Greeter", "Legacy"}, collectNames(definitions))
+	require.True(t, astkratos.HasGrpcClients(demoApiRoot))
+	require.True(t, astkratos.HasGrpcServers(demoApiRoot))
+	require.Equal(t, 3, astkratos.CountGrpcServices(demoApiRoot))
+}
+
+// TestAnalyzeProject tests the aggregated analysis of the demo project
+//
+// TestAnalyzeProject 测试演示项目的聚合分析
+func TestAnalyzeProject(t *testing.T) {
+	report := astkratos.AnalyzeProject(runpath.PARENT.Join("testdata", "demokratos"))
+	t.Log(neatjsons.S(report))
+	require.Equal(t, "demokratos", report.ModuleInfo.Module.Path)
+	require.Len(t, report.Clients, 6)
+	require.Len(t, report.Servers, 6)
+	require.Equal(t, []string{"Echo", "Greeter", "Legacy"}, collectNames(report.Services))
+	require.Empty(t, report.ProtoDrifts)
+	require.Len(t, report.GeneratedFiles, 5)
+	require.Len(t, report.GeneratedIssues, 2)
+	require.Len(t, report.Implementations, 2)
+	require.Len(t, report.Coverage, 2)
+	require.Len(t, report.Exposures, 3)
+	require.Len(t, report.WireGraph.Injectors, 1)
+	require.Empty(t, report.WireStaleness)
+	require.Len(t, report.Repositories, 1)
+	require.Equal(t, "Bootstrap", report.ConfigSchema.Root)
+	require.Empty(t, report.ConfigIssues)
+	require.Equal(t, "v2.7.2", report.Upgrade.KratosVersion)
+	require.Len(t, report.Upgrade.Files, 1)
+	require.Empty(t, report.LayerViolations)
 }
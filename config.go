@@ -0,0 +1,231 @@
+// Package astkratos config schema: Configuration tree of a Kratos app from internal/conf
+// Parses the .proto files of internal/conf with the native proto parser
+// Expands the Bootstrap message into nested fields with proto names, JSON names and types
+// Marks google.protobuf.Duration fields so that config tooling can check duration values
+//
+// astkratos 配置结构：从 internal/conf 提取 Kratos 应用的配置树
+// 使用原生 proto 解析器解析 internal/conf 中的 .proto 文件
+// 将 Bootstrap 消息展开为带有 proto 名称、JSON 名称和类型的嵌套字段
+// 标记 google.protobuf.Duration 字段，便于配置工具检查时长值
+package astkratos
+
+import (
+	"os"
+	"strings"
+
+	"github.com/orzkratos/astkratos/internal/utils"
+	"github.com/yyle88/erero"
+)
+
+// configRootMessage is the root message of the Kratos layout configuration
+//
+// configRootMessage 是 Kratos 布局配置的根消息
+const configRootMessage = "Bootstrap"
+
+// protoScalarTypes lists the scalar value types of protobuf
+//
+// protoScalarTypes 列出 protobuf 的标量值类型
+var protoScalarTypes = map[string]bool{
+	"double": true, "float": true,
+	"int32": true, "int64": true, "uint32": true, "uint64": true,
+	"sint32": true, "sint64": true, "fixed32": true, "fixed64": true, "sfixed32": true, "sfixed64": true,
+	"bool": true, "string": true, "bytes": true,
+}
+
+// ConfigFieldKind represents the value kind of a configuration field
+//
+// ConfigFieldKind 表示配置字段的值类型
+type ConfigFieldKind string
+
+const (
+	ConfigFieldScalar   ConfigFieldKind = "scalar"   // Scalar value, such as string or int32 // 标量值，例如 string 或 int32
+	ConfigFieldEnum     ConfigFieldKind = "enum"     // Enum value written by name // 以名称书写的枚举值
+	ConfigFieldMessage  ConfigFieldKind = "message"  // Nested message with its own fields // 带有自身字段的嵌套消息
+	ConfigFieldDuration ConfigFieldKind = "duration" // google.protobuf.Duration written as 1.5s // 以 1.5s 形式书写的 google.protobuf.Duration
+	ConfigFieldExternal ConfigFieldKind = "external" // Message defined outside internal/conf // 定义在 internal/conf 之外的消息
+)
+
+// ConfigField represents one field of the configuration tree
+//
+// ConfigField 表示配置树中的一个字段
+type ConfigField struct {
+	Name       string          // Proto field name, such as read_timeout // proto 字段名称，例如 read_timeout
+	JsonName   string          // JSON name, such as readTimeout // JSON 名称，例如 readTimeout
+	Type       string          // Type as written, the value type of map fields // 源码中的类型，map 字段为值类型
+	Kind       ConfigFieldKind // Value kind // 值类型
+	Repeated   bool            // Repeated field // 重复字段
+	MapKeyType string          // Key type of map fields, blank otherwise // map 字段的键类型，否则为空
+	Message    string          // Resolved message or enum name without the package, such as Server.HTTP // 解析后不含包名的消息或枚举名称，例如 Server.HTTP
+	EnumValues []string        // Value names of enum fields // 枚举字段的值名称
+	Fields     []*ConfigField  // Fields of message kinds, empty when the message recurses // 消息类型的字段，消息递归时为空
+	Comment    string          // Leading comment // 前置注释
+	Position   Position        // Position in the .proto file // 在 .proto 文件中的位置
+}
+
+// ConfigSchema represents the configuration tree of an app
+//
+// ConfigSchema 表示应用的配置树
+type ConfigSchema struct {
+	Root       string         // Root message, such as Bootstrap // 根消息，例如 Bootstrap
+	Package    string         // Proto package, such as kratos.api // proto 包名，例如 kratos.api
+	ProtoFiles []string       // Parsed .proto files // 已解析的 .proto 文件
+	Position   Position       // Position of the root message // 根消息的位置
+	Fields     []*ConfigField // Fields of the root message // 根消息的字段
+}
+
+// configProtoType holds a message or enum with the file declaring it
+//
+// configProtoType 保存消息或枚举及其声明文件
+type configProtoType struct {
+	protoFile *ProtoFile    // Declaring file // 声明文件
+	message   *ProtoMessage // Message, nil for enums // 消息，枚举时为 nil
+	enum      *ProtoEnum    // Enum, nil for messages // 枚举，消息时为 nil
+}
+
+// analyzeConfigSchema parses the .proto files under the conf root and expands the root message
+// Returns nil when the conf root holds no message
+//
+// analyzeConfigSchema 解析 conf 根目录下的 .proto 文件并展开根消息
+// conf 根目录中没有消息时返回 nil
+func analyzeConfigSchema(confRoot string) (*ConfigSchema, error) {
+	if _, err := os.Stat(confRoot); os.IsNotExist(err) {
+		return nil, nil
+	}
+	var protoFiles []*ProtoFile
+	if err := utils.WalkFiles(confRoot, utils.NewSuffixPattern([]string{".proto"}), func(path string, info os.FileInfo) error {
+		protoFile, err := ParseProtoFile(path)
+		if err != nil {
+			return erero.Wro(err)
+		}
+		protoFiles = append(protoFiles, protoFile)
+		return nil
+	}); err != nil {
+		return nil, erero.Wro(err)
+	}
+
+	protoTypes := map[string]*configProtoType{}
+	var rootFile *ProtoFile
+	var rootMessage *ProtoMessage
+	for _, protoFile := range protoFiles {
+		for _, message := range protoFile.Messages {
+			indexConfigMessage(protoTypes, protoFile, protoQualifiedName(protoFile.Package, message.Name), message)
+			if rootMessage == nil || (message.Name == configRootMessage && rootMessage.Name != configRootMessage) {
+				rootFile, rootMessage = protoFile, message
+			}
+		}
+		for _, enum := range protoFile.Enums {
+			protoTypes[protoQualifiedName(protoFile.Package, enum.Name)] = &configProtoType{protoFile: protoFile, enum: enum}
+		}
+	}
+	if rootMessage == nil {
+		return nil, nil
+	}
+
+	schema := &ConfigSchema{
+		Root:       rootMessage.Name,
+		Package:    rootFile.Package,
+		ProtoFiles: make([]string, 0, len(protoFiles)),
+		Position:   Position{Path: rootFile.Path, Line: rootMessage.Line},
+	}
+	for _, protoFile := range protoFiles {
+		schema.ProtoFiles = append(schema.ProtoFiles, protoFile.Path)
+	}
+	rootName := protoQualifiedName(rootFile.Package, rootMessage.Name)
+	schema.Fields = newConfigFields(protoTypes, rootFile, rootName, rootMessage, map[string]bool{rootName: true})
+	return schema, nil
+}
+
+// indexConfigMessage indexes the message and its nested messages and enums by full name
+//
+// indexConfigMessage 以全名索引消息及其嵌套的消息和枚举
+func indexConfigMessage(protoTypes map[string]*configProtoType, protoFile *ProtoFile, fullName string, message *ProtoMessage) {
+	protoTypes[fullName] = &configProtoType{protoFile: protoFile, message: message}
+	for _, nested := range message.Messages {
+		indexConfigMessage(protoTypes, protoFile, fullName+"."+nested.Name, nested)
+	}
+	for _, enum := range message.Enums {
+		protoTypes[fullName+"."+enum.Name] = &configProtoType{protoFile: protoFile, enum: enum}
+	}
+}
+
+// newConfigFields converts the fields of the message, expanding nested messages outside the ancestry
+//
+// newConfigFields 转换消息的字段，并展开不在祖先链中的嵌套消息
+func newConfigFields(protoTypes map[string]*configProtoType, protoFile *ProtoFile, scope string, message *ProtoMessage, ancestry map[string]bool) []*ConfigField {
+	fields := make([]*ConfigField, 0, len(message.Fields))
+	for _, protoField := range message.Fields {
+		field := &ConfigField{
+			Name:       protoField.Name,
+			JsonName:   protoField.JsonName,
+			Type:       protoField.Type,
+			Repeated:   protoField.Label == "repeated",
+			MapKeyType: protoField.MapKeyType,
+			Comment:    protoField.Comment,
+			Position:   Position{Path: protoFile.Path, Line: protoField.Line},
+		}
+		typeName := strings.TrimPrefix(protoField.Type, ".")
+		switch {
+		case protoScalarTypes[typeName]:
+			field.Kind = ConfigFieldScalar
+		case typeName == "google.protobuf.Duration":
+			field.Kind = ConfigFieldDuration
+		default:
+			fullName, protoType := resolveConfigType(protoTypes, scope, protoField.Type)
+			switch {
+			case protoType == nil:
+				field.Kind = ConfigFieldExternal
+			case protoType.enum != nil:
+				field.Kind = ConfigFieldEnum
+				field.Message = strings.TrimPrefix(fullName, protoType.protoFile.Package+".")
+				field.EnumValues = make([]string, 0, len(protoType.enum.Values))
+				for _, value := range protoType.enum.Values {
+					field.EnumValues = append(field.EnumValues, value.Name)
+				}
+			default:
+				field.Kind = ConfigFieldMessage
+				field.Message = strings.TrimPrefix(fullName, protoType.protoFile.Package+".")
+				field.Fields = make([]*ConfigField, 0)
+				if !ancestry[fullName] {
+					ancestry[fullName] = true
+					field.Fields = newConfigFields(protoTypes, protoType.protoFile, fullName, protoType.message, ancestry)
+					delete(ancestry, fullName)
+				}
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// resolveConfigType resolves the type name the way protoc does, from the innermost scope outwards
+//
+// resolveConfigType 按 protoc 的方式从最内层作用域向外解析类型名称
+func resolveConfigType(protoTypes map[string]*configProtoType, scope string, typeName string) (string, *configProtoType) {
+	if fullName, ok := strings.CutPrefix(typeName, "."); ok {
+		return fullName, protoTypes[fullName]
+	}
+	for {
+		fullName := protoQualifiedName(scope, typeName)
+		if protoType, ok := protoTypes[fullName]; ok {
+			return fullName, protoType
+		}
+		if scope == "" {
+			return typeName, nil
+		}
+		if idx := strings.LastIndex(scope, "."); idx >= 0 {
+			scope = scope[:idx]
+		} else {
+			scope = ""
+		}
+	}
+}
+
+// protoQualifiedName joins the scope and the name with a dot, skipping a blank scope
+//
+// protoQualifiedName 用点连接作用域和名称，作用域为空时跳过
+func protoQualifiedName(scope string, name string) string {
+	if scope == "" {
+		return name
+	}
+	return scope + "." + name
+}
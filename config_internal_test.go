@@ -0,0 +1,74 @@
+package astkratos
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/rese"
+)
+
+// TestAnalyzeConfigSchema_Types tests enums, maps, repeated fields, recursion and types from a second file
+//
+// TestAnalyzeConfigSchema_Types 测试枚举、map、重复字段、递归以及来自第二个文件的类型
+func TestAnalyzeConfigSchema_Types(t *testing.T) {
+	root := t.TempDir()
+	must.Done(os.WriteFile(filepath.Join(root, "conf.proto"), []byte(`syntax = "proto3";
+package demo.conf;
+
+import "google/protobuf/struct.proto";
+import "registry.proto";
+
+message Bootstrap {
+  enum Mode {
+    DEV = 0;
+    PROD = 1;
+  }
+  Mode mode = 1;
+  map<string, Registry> registries = 2;
+  repeated string tags = 3;
+  Node root = 4;
+  google.protobuf.Struct extra = 5;
+}
+
+message Node {
+  repeated Node children = 1;
+}
+`), 0644))
+	must.Done(os.WriteFile(filepath.Join(root, "registry.proto"), []byte(`syntax = "proto3";
+package demo.conf;
+
+message Registry {
+  string endpoint = 1;
+}
+`), 0644))
+
+	schema := rese.P1(analyzeConfigSchema(root))
+	require.Equal(t, "Bootstrap", schema.Root)
+	require.Len(t, schema.ProtoFiles, 2)
+	require.Len(t, schema.Fields, 5)
+
+	mode := schema.Fields[0]
+	require.Equal(t, ConfigFieldEnum, mode.Kind)
+	require.Equal(t, "Bootstrap.Mode", mode.Message)
+	require.Equal(t, []string{"DEV", "PROD"}, mode.EnumValues)
+
+	registries := schema.Fields[1]
+	require.Equal(t, ConfigFieldMessage, registries.Kind)
+	require.Equal(t, "string", registries.MapKeyType)
+	require.Equal(t, "Registry", registries.Message)
+	require.Equal(t, "endpoint", registries.Fields[0].Name)
+
+	require.True(t, schema.Fields[2].Repeated)
+	require.Equal(t, ConfigFieldScalar, schema.Fields[2].Kind)
+
+	node := schema.Fields[3]
+	require.Len(t, node.Fields, 1)
+	require.True(t, node.Fields[0].Repeated)
+	require.Equal(t, "Node", node.Fields[0].Message)
+	require.Empty(t, node.Fields[0].Fields)
+
+	require.Equal(t, ConfigFieldExternal, schema.Fields[4].Kind)
+}
@@ -0,0 +1,51 @@
+package astkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/astkratos"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/neatjson/neatjsons"
+	"github.com/yyle88/runpath"
+)
+
+// TestGetConfigSchema tests the Bootstrap tree of the demo conf.proto
+//
+// TestGetConfigSchema 测试示例 conf.proto 中的 Bootstrap 配置树
+func TestGetConfigSchema(t *testing.T) {
+	schema := astkratos.GetConfigSchema(runpath.PARENT.Join("testdata", "demokratos"))
+	t.Log(neatjsons.S(schema))
+
+	require.Equal(t, "Bootstrap", schema.Root)
+	require.Equal(t, "kratos.api", schema.Package)
+	require.Equal(t, []string{runpath.PARENT.Join("testdata", "demokratos", "internal", "conf", "conf.proto")}, schema.ProtoFiles)
+	require.Equal(t, 8, schema.Position.Line)
+	require.Len(t, schema.Fields, 2)
+
+	server := schema.Fields[0]
+	require.Equal(t, "server", server.Name)
+	require.Equal(t, astkratos.ConfigFieldMessage, server.Kind)
+	require.Equal(t, "Server", server.Message)
+	require.Len(t, server.Fields, 2)
+	require.Equal(t, "Server.HTTP", server.Fields[0].Message)
+	require.Equal(t, "Server.GRPC", server.Fields[1].Message)
+
+	timeout := server.Fields[0].Fields[2]
+	require.Equal(t, "timeout", timeout.Name)
+	require.Equal(t, "google.protobuf.Duration", timeout.Type)
+	require.Equal(t, astkratos.ConfigFieldDuration, timeout.Kind)
+	require.Equal(t, 17, timeout.Position.Line)
+
+	redis := schema.Fields[1].Fields[1]
+	require.Equal(t, "Data.Redis", redis.Message)
+	require.Equal(t, "read_timeout", redis.Fields[2].Name)
+	require.Equal(t, "readTimeout", redis.Fields[2].JsonName)
+	require.Equal(t, astkratos.ConfigFieldScalar, redis.Fields[1].Kind)
+}
+
+// TestGetConfigSchema_NoConf tests that a project without internal/conf has no schema
+//
+// TestGetConfigSchema_NoConf 测试没有 internal/conf 的项目没有配置结构
+func TestGetConfigSchema_NoConf(t *testing.T) {
+	require.Nil(t, astkratos.GetConfigSchema(t.TempDir()))
+}
@@ -0,0 +1,313 @@
+// Package astkratos config check: Validation of configs/*.yaml against the Bootstrap schema
+// Loads each YAML file in the configs directory with line numbers and walks it along the schema
+// Reports unknown keys, values of the wrong type and root sections that no file provides
+// Skips ${...} placeholders since Kratos resolves them from the environment at startup
+//
+// astkratos 配置检查：根据 Bootstrap 结构校验 configs/*.yaml
+// 加载 configs 目录中的每个 YAML 文件并保留行号，然后沿着配置结构遍历
+// 报告未知的键、类型错误的值以及没有任何文件提供的根配置段
+// 跳过 ${...} 占位符，因为 Kratos 会在启动时从环境变量中解析它们
+package astkratos
+
+import (
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/orzkratos/astkratos/internal/utils"
+	"github.com/yyle88/erero"
+	"gopkg.in/yaml.v3"
+)
+
+// durationPattern matches the protobuf JSON form of google.protobuf.Duration, such as 1.5s
+//
+// durationPattern 匹配 google.protobuf.Duration 的 protobuf JSON 形式，例如 1.5s
+var durationPattern = regexp.MustCompile(`^-?\d+(\.\d+)?s$`)
+
+// integerPattern matches a decimal integer written as a string
+//
+// integerPattern 匹配以字符串书写的十进制整数
+var integerPattern = regexp.MustCompile(`^-?\d+$`)
+
+// ConfigIssueKind represents the kind of problem found in a config file
+//
+// ConfigIssueKind 表示在配置文件中发现的问题类型
+type ConfigIssueKind string
+
+const (
+	ConfigIssueUnknownKey     ConfigIssueKind = "unknown_key"     // Key not declared in the schema // 配置结构中未声明的键
+	ConfigIssueWrongType      ConfigIssueKind = "wrong_type"      // Value not matching the field type // 与字段类型不匹配的值
+	ConfigIssueMissingSection ConfigIssueKind = "missing_section" // Root section no file provides // 没有任何文件提供的根配置段
+)
+
+// ConfigIssue represents one problem found in a config file
+//
+// ConfigIssue 表示在配置文件中发现的一个问题
+type ConfigIssue struct {
+	Kind     ConfigIssueKind // Issue kind // 问题类型
+	Path     string          // Dotted key path, such as server.http.timeout // 以点分隔的键路径，例如 server.http.timeout
+	Detail   string          // Human readable description // 可读的描述
+	Position Position        // Position in the YAML file // 在 YAML 文件中的位置
+}
+
+// configFile holds a parsed YAML config file
+//
+// configFile 保存已解析的 YAML 配置文件
+type configFile struct {
+	path string     // Absolute file path // 文件绝对路径
+	root *yaml.Node // Root node of the first document, nil when empty // 第一个文档的根节点，文件为空时为 nil
+}
+
+// checkConfigFiles checks the YAML files under the configs root against the schema
+// Root sections are required across the files, as Kratos merges every file of the directory
+//
+// checkConfigFiles 根据配置结构检查 configs 根目录下的 YAML 文件
+// 根配置段按所有文件合并后要求，因为 Kratos 会合并目录中的每个文件
+func checkConfigFiles(configsRoot string, schema *ConfigSchema) ([]*ConfigIssue, error) {
+	issues := make([]*ConfigIssue, 0)
+	if schema == nil {
+		return issues, nil
+	}
+	if _, err := os.Stat(configsRoot); os.IsNotExist(err) {
+		return issues, nil
+	}
+	var configFiles []*configFile
+	if err := utils.WalkFiles(configsRoot, utils.NewSuffixPattern([]string{".yaml", ".yml"}), func(path string, info os.FileInfo) error {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return erero.Wro(err)
+		}
+		var document yaml.Node
+		if err := yaml.Unmarshal(content, &document); err != nil {
+			return erero.Wrapf(err, "parse %s", path)
+		}
+		item := &configFile{path: path}
+		if len(document.Content) > 0 {
+			item.root = document.Content[0]
+		}
+		configFiles = append(configFiles, item)
+		return nil
+	}); err != nil {
+		return nil, erero.Wro(err)
+	}
+	if len(configFiles) == 0 {
+		return issues, nil
+	}
+
+	provided := map[string]bool{}
+	for _, item := range configFiles {
+		if item.root == nil {
+			continue
+		}
+		checker := &configChecker{path: item.path}
+		checker.checkMessage(item.root, schema.Fields, "")
+		issues = append(issues, checker.issues...)
+		if item.root.Kind == yaml.MappingNode {
+			for idx := 0; idx+1 < len(item.root.Content); idx += 2 {
+				provided[item.root.Content[idx].Value] = true
+			}
+		}
+	}
+	for _, field := range schema.Fields {
+		if field.Kind != ConfigFieldMessage || provided[field.Name] || provided[field.JsonName] {
+			continue
+		}
+		position := Position{Path: configFiles[0].path, Line: 1}
+		if configFiles[0].root != nil {
+			position.Line = configFiles[0].root.Line
+		}
+		issues = append(issues, &ConfigIssue{
+			Kind:     ConfigIssueMissingSection,
+			Path:     field.Name,
+			Detail:   "section " + field.Name + " of " + schema.Root + " is missing in every config file",
+			Position: position,
+		})
+	}
+	return issues, nil
+}
+
+// configChecker walks the nodes of one file and collects the issues
+//
+// configChecker 遍历单个文件的节点并收集问题
+type configChecker struct {
+	path   string         // File path // 文件路径
+	issues []*ConfigIssue // Collected issues // 收集到的问题
+}
+
+// addIssue records an issue at the node
+//
+// addIssue 在节点处记录一个问题
+func (c *configChecker) addIssue(kind ConfigIssueKind, path string, detail string, node *yaml.Node) {
+	c.issues = append(c.issues, &ConfigIssue{
+		Kind:     kind,
+		Path:     path,
+		Detail:   detail,
+		Position: Position{Path: c.path, Line: node.Line},
+	})
+}
+
+// checkMessage checks a mapping node against the fields of a message, accepting proto and JSON names
+//
+// checkMessage 根据消息的字段检查映射节点，同时接受 proto 名称和 JSON 名称
+func (c *configChecker) checkMessage(node *yaml.Node, fields []*ConfigField, path string) {
+	node = resolveYamlAlias(node)
+	if isYamlNull(node) {
+		return
+	}
+	if node.Kind != yaml.MappingNode {
+		c.addIssue(ConfigIssueWrongType, path, "expect a mapping at "+displayConfigPath(path)+", got "+describeYamlNode(node), node)
+		return
+	}
+	for idx := 0; idx+1 < len(node.Content); idx += 2 {
+		keyNode, valueNode := node.Content[idx], node.Content[idx+1]
+		if keyNode.Value == "<<" {
+			continue
+		}
+		keyPath := joinConfigPath(path, keyNode.Value)
+		idxField := slices.IndexFunc(fields, func(field *ConfigField) bool {
+			return field.Name == keyNode.Value || field.JsonName == keyNode.Value
+		})
+		if idxField < 0 {
+			c.addIssue(ConfigIssueUnknownKey, keyPath, "unknown key "+keyNode.Value+" at "+displayConfigPath(path), keyNode)
+			continue
+		}
+		c.checkField(valueNode, fields[idxField], keyPath)
+	}
+}
+
+// checkField checks the value node of a field, handling repeated and map fields
+//
+// checkField 检查字段的值节点，处理重复字段和 map 字段
+func (c *configChecker) checkField(node *yaml.Node, field *ConfigField, path string) {
+	node = resolveYamlAlias(node)
+	if isYamlNull(node) {
+		return
+	}
+	switch {
+	case field.MapKeyType != "":
+		if node.Kind != yaml.MappingNode {
+			c.addIssue(ConfigIssueWrongType, path, "expect a map at "+path+", got "+describeYamlNode(node), node)
+			return
+		}
+		for idx := 0; idx+1 < len(node.Content); idx += 2 {
+			c.checkValue(node.Content[idx+1], field, joinConfigPath(path, node.Content[idx].Value))
+		}
+	case field.Repeated:
+		if node.Kind != yaml.SequenceNode {
+			c.addIssue(ConfigIssueWrongType, path, "expect a list at "+path+", got "+describeYamlNode(node), node)
+			return
+		}
+		for idx, item := range node.Content {
+			c.checkValue(item, field, path+"["+strconv.Itoa(idx)+"]")
+		}
+	default:
+		c.checkValue(node, field, path)
+	}
+}
+
+// checkValue checks a single value against the kind of the field
+//
+// checkValue 根据字段的值类型检查单个值
+func (c *configChecker) checkValue(node *yaml.Node, field *ConfigField, path string) {
+	node = resolveYamlAlias(node)
+	if isYamlNull(node) {
+		return
+	}
+	if field.Kind == ConfigFieldMessage {
+		c.checkMessage(node, field.Fields, path)
+		return
+	}
+	if field.Kind == ConfigFieldExternal {
+		return
+	}
+	if node.Kind != yaml.ScalarNode {
+		c.addIssue(ConfigIssueWrongType, path, "expect a "+field.Type+" value at "+path+", got "+describeYamlNode(node), node)
+		return
+	}
+	if strings.Contains(node.Value, "${") {
+		return
+	}
+	var valid bool
+	switch field.Kind {
+	case ConfigFieldDuration:
+		valid = node.Tag == "!!str" && durationPattern.MatchString(node.Value)
+	case ConfigFieldEnum:
+		valid = slices.Contains(field.EnumValues, node.Value) || node.Tag == "!!int"
+	default:
+		valid = isScalarTagValid(field.Type, node)
+	}
+	if !valid {
+		c.addIssue(ConfigIssueWrongType, path, "expect a "+field.Type+" value at "+path+", got "+describeYamlNode(node), node)
+	}
+}
+
+// isScalarTagValid reports whether the YAML tag of the scalar suits the protobuf scalar type
+// Integers may be quoted since protobuf JSON accepts 64-bit integers as strings
+//
+// isScalarTagValid 判断标量的 YAML 标签是否适合 protobuf 标量类型
+// 整数可以加引号，因为 protobuf JSON 接受以字符串表示的 64 位整数
+func isScalarTagValid(protoType string, node *yaml.Node) bool {
+	switch protoType {
+	case "string", "bytes":
+		return node.Tag == "!!str"
+	case "bool":
+		return node.Tag == "!!bool"
+	case "double", "float":
+		return node.Tag == "!!float" || node.Tag == "!!int"
+	default:
+		return node.Tag == "!!int" || (node.Tag == "!!str" && integerPattern.MatchString(node.Value))
+	}
+}
+
+// resolveYamlAlias follows alias nodes to the anchored node
+//
+// resolveYamlAlias 沿别名节点找到锚点节点
+func resolveYamlAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
+
+// isYamlNull reports whether the node is a null scalar
+//
+// isYamlNull 判断节点是否为 null 标量
+func isYamlNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
+
+// describeYamlNode describes the node for issue details, such as string "abc" or mapping
+//
+// describeYamlNode 为问题描述节点，例如 string "abc" 或 mapping
+func describeYamlNode(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "mapping"
+	case yaml.SequenceNode:
+		return "list"
+	default:
+		return strings.TrimPrefix(node.Tag, "!!") + " " + strconv.Quote(node.Value)
+	}
+}
+
+// joinConfigPath appends the key to the dotted path
+//
+// joinConfigPath 将键追加到以点分隔的路径
+func joinConfigPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// displayConfigPath names the path in issue details, the root shown as the top level
+//
+// displayConfigPath 在问题描述中显示路径，根路径显示为 top level
+func displayConfigPath(path string) string {
+	if path == "" {
+		return "top level"
+	}
+	return path
+}
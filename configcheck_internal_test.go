@@ -0,0 +1,27 @@
+package astkratos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// TestIsScalarTagValid tests the YAML tags accepted by each protobuf scalar type
+//
+// TestIsScalarTagValid 测试每种 protobuf 标量类型接受的 YAML 标签
+func TestIsScalarTagValid(t *testing.T) {
+	parse := func(value string) *yaml.Node {
+		var document yaml.Node
+		require.NoError(t, yaml.Unmarshal([]byte(value), &document))
+		return document.Content[0]
+	}
+	require.True(t, isScalarTagValid("string", parse("abc")))
+	require.False(t, isScalarTagValid("string", parse("8000")))
+	require.True(t, isScalarTagValid("string", parse(`"8000"`)))
+	require.True(t, isScalarTagValid("bool", parse("true")))
+	require.False(t, isScalarTagValid("bool", parse("yes please")))
+	require.True(t, isScalarTagValid("int64", parse(`"-42"`)))
+	require.False(t, isScalarTagValid("int32", parse("4.2")))
+	require.True(t, isScalarTagValid("double", parse("4")))
+}
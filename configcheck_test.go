@@ -0,0 +1,65 @@
+package astkratos_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/orzkratos/astkratos"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/neatjson/neatjsons"
+	"github.com/yyle88/rese"
+	"github.com/yyle88/runpath"
+)
+
+// TestCheckConfigFiles tests that the demo config.yaml matches the Bootstrap schema
+//
+// TestCheckConfigFiles 测试示例 config.yaml 与 Bootstrap 结构一致
+func TestCheckConfigFiles(t *testing.T) {
+	issues := astkratos.CheckConfigFiles(runpath.PARENT.Join("testdata", "demokratos"))
+	require.Empty(t, issues)
+}
+
+// TestCheckConfigFiles_Issues tests unknown keys, wrong types and a missing section
+//
+// TestCheckConfigFiles_Issues 测试未知键、错误类型和缺失的配置段
+func TestCheckConfigFiles_Issues(t *testing.T) {
+	root := t.TempDir()
+	confRoot := filepath.Join(root, "internal", "conf")
+	must.Done(os.MkdirAll(confRoot, 0755))
+	must.Done(os.WriteFile(filepath.Join(confRoot, "conf.proto"), rese.V1(os.ReadFile(runpath.PARENT.Join("testdata", "demokratos", "internal", "conf", "conf.proto"))), 0644))
+	configsRoot := filepath.Join(root, "configs")
+	must.Done(os.MkdirAll(configsRoot, 0755))
+	must.Done(os.WriteFile(filepath.Join(configsRoot, "config.yaml"), []byte(`server:
+  http:
+    addr: 0.0.0.0:8000
+    timeout: 1m
+  grpc:
+    address: 0.0.0.0:9000
+    timeout: ${GRPC_TIMEOUT}
+  debug: true
+`), 0644))
+
+	issues := astkratos.CheckConfigFiles(root)
+	t.Log(neatjsons.S(issues))
+	require.Len(t, issues, 4)
+
+	require.Equal(t, astkratos.ConfigIssueWrongType, issues[0].Kind)
+	require.Equal(t, "server.http.timeout", issues[0].Path)
+	require.Equal(t, `expect a google.protobuf.Duration value at server.http.timeout, got str "1m"`, issues[0].Detail)
+	require.Equal(t, filepath.Join(configsRoot, "config.yaml"), issues[0].Position.Path)
+	require.Equal(t, 4, issues[0].Position.Line)
+
+	require.Equal(t, astkratos.ConfigIssueUnknownKey, issues[1].Kind)
+	require.Equal(t, "server.grpc.address", issues[1].Path)
+	require.Equal(t, 6, issues[1].Position.Line)
+
+	require.Equal(t, astkratos.ConfigIssueUnknownKey, issues[2].Kind)
+	require.Equal(t, "server.debug", issues[2].Path)
+	require.Equal(t, 8, issues[2].Position.Line)
+
+	require.Equal(t, astkratos.ConfigIssueMissingSection, issues[3].Kind)
+	require.Equal(t, "data", issues[3].Path)
+	require.Equal(t, 1, issues[3].Position.Line)
+}
@@ -0,0 +1,79 @@
+// Package astkratos service coverage: RPCs that still fall back to the Unimplemented stub
+// Compares the methods declared on each implementation struct with the RPCs of its server interface
+// Every RPC not overridden returns codes.Unimplemented at runtime and is listed with its position
+//
+// astkratos 服务覆盖率：仍然回退到 Unimplemented 存根的 RPC
+// 比较每个实现结构体上声明的方法与其服务器接口中的 RPC
+// 每个未重写的 RPC 在运行时都会返回 codes.Unimplemented，并连同位置一起列出
+package astkratos
+
+// ServiceCoverage represents the RPC coverage of one service implementation struct
+//
+// ServiceCoverage 表示单个服务实现结构体的 RPC 覆盖情况
+type ServiceCoverage struct {
+	Service           string              // gRPC service name, such as Greeter // gRPC 服务名称，例如 Greeter
+	StructName        string              // Implementation struct name, such as GreeterService // 实现结构体名称，例如 GreeterService
+	Position          Position            // Position of the implementation struct // 实现结构体的位置
+	TotalRpcs         int                 // Number of RPCs in the server interface // 服务器接口中的 RPC 数量
+	ImplementedRpcs   []string            // RPCs overridden by the struct // 结构体重写的 RPC
+	UnimplementedRpcs []*UnimplementedRpc // RPCs that fall back to the stub // 回退到存根的 RPC
+}
+
+// UnimplementedRpc represents an RPC that returns codes.Unimplemented at runtime
+//
+// UnimplementedRpc 表示运行时返回 codes.Unimplemented 的 RPC
+type UnimplementedRpc struct {
+	Name           string   // RPC method name // RPC 方法名称
+	FullMethodName string   // Full method name, such as /echo.v1.Echo/Chat // 完整方法名，例如 /echo.v1.Echo/Chat
+	Position       Position // Position in the generated XxxServer interface // 在生成的 XxxServer 接口中的位置
+}
+
+// IsComplete reports whether every RPC of the service is implemented
+//
+// IsComplete 判断服务的每个 RPC 是否都已实现
+func (c *ServiceCoverage) IsComplete() bool {
+	return len(c.UnimplementedRpcs) == 0
+}
+
+// newServiceCoverages compares each implementation with the RPCs of its service
+//
+// newServiceCoverages 将每个实现与其服务的 RPC 进行比较
+func newServiceCoverages(services []*GrpcTypeDefinition, implementations []*ServiceImplementation) []*ServiceCoverage {
+	serviceMap := map[string]*GrpcTypeDefinition{}
+	for _, service := range services {
+		serviceMap[service.SrcPath+"#"+service.Name] = service
+	}
+
+	coverages := make([]*ServiceCoverage, 0)
+	for _, implementation := range implementations {
+		service, ok := serviceMap[implementation.ServiceSrcPath+"#"+implementation.Service]
+		if !ok {
+			continue
+		}
+		declared := map[string]bool{}
+		for _, name := range implementation.Methods {
+			declared[name] = true
+		}
+		coverage := &ServiceCoverage{
+			Service:           implementation.Service,
+			StructName:        implementation.StructName,
+			Position:          implementation.Position,
+			TotalRpcs:         len(service.Methods),
+			ImplementedRpcs:   make([]string, 0),
+			UnimplementedRpcs: make([]*UnimplementedRpc, 0),
+		}
+		for _, method := range service.Methods {
+			if declared[method.Name] {
+				coverage.ImplementedRpcs = append(coverage.ImplementedRpcs, method.Name)
+				continue
+			}
+			coverage.UnimplementedRpcs = append(coverage.UnimplementedRpcs, &UnimplementedRpc{
+				Name:           method.Name,
+				FullMethodName: method.FullMethodName,
+				Position:       Position{Path: service.SrcPath, Line: method.Line},
+			})
+		}
+		coverages = append(coverages, coverage)
+	}
+	return coverages
+}
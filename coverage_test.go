@@ -0,0 +1,35 @@
+package astkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/astkratos"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/neatjson/neatjsons"
+	"github.com/yyle88/runpath"
+)
+
+// TestListServiceCoverage tests that RPCs not overridden by the struct are reported with positions
+//
+// TestListServiceCoverage 测试结构体未重写的 RPC 连同位置一起被报告
+func TestListServiceCoverage(t *testing.T) {
+	coverages := astkratos.ListServiceCoverage(runpath.PARENT.Join("testdata", "demokratos"))
+	t.Log(neatjsons.S(coverages))
+	require.Len(t, coverages, 2)
+
+	echoGrpcPath := runpath.PARENT.Join("testdata", "demokratos", "api", "echo", "v1", "echo_grpc.pb.go")
+	echo := coverages[0]
+	require.Equal(t, "EchoService", echo.StructName)
+	require.False(t, echo.IsComplete())
+	require.Equal(t, 4, echo.TotalRpcs)
+	require.Equal(t, []string{"Ping", "Watch"}, echo.ImplementedRpcs)
+	require.Equal(t, []*astkratos.UnimplementedRpc{
+		{Name: "Collect", FullMethodName: "/echo.v1.Echo/Collect", Position: astkratos.Position{Path: echoGrpcPath, Line: 107}},
+		{Name: "Chat", FullMethodName: "/echo.v1.Echo/Chat", Position: astkratos.Position{Path: echoGrpcPath, Line: 108}},
+	}, echo.UnimplementedRpcs)
+
+	greeter := coverages[1]
+	require.Equal(t, "GreeterService", greeter.StructName)
+	require.True(t, greeter.IsComplete())
+	require.Equal(t, []string{"SayHello"}, greeter.ImplementedRpcs)
+}
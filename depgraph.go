@@ -0,0 +1,318 @@
+// Package astkratos dependency graph: Constructor graph over the NewXxx functions of the Kratos layers
+// Builds type nodes from the params and results of the constructors in internal/biz, data, service and server
+// An edge from a type to another means the first is needed to construct the second
+// Reports cycles and param types that no constructor, injector input or wire.Bind provides
+//
+// astkratos 依赖图：基于 Kratos 各层 NewXxx 函数的构造函数图
+// 根据 internal/biz、data、service 和 server 中构造函数的参数和返回值构建类型节点
+// 从一个类型指向另一个类型的边表示构造后者需要前者
+// 报告循环依赖以及没有构造函数、注入器输入或 wire.Bind 提供的参数类型
+package astkratos
+
+import (
+	"fmt"
+	"go/ast"
+	"slices"
+	"strings"
+
+	"github.com/yyle88/erero"
+)
+
+// dependencyLayers lists the packages under internal scanned for constructors
+//
+// dependencyLayers 列出在 internal 下扫描构造函数的包
+var dependencyLayers = []string{"biz", "data", "service", "server"}
+
+// DependencyNodeKind represents how a type node is satisfied
+//
+// DependencyNodeKind 表示类型节点如何被满足
+type DependencyNodeKind string
+
+const (
+	DependencyNodeConstructed DependencyNodeKind = "constructed" // Returned by a constructor // 由构造函数返回
+	DependencyNodeBound       DependencyNodeKind = "bound"       // Interface bound through wire.Bind // 通过 wire.Bind 绑定的接口
+	DependencyNodeInput       DependencyNodeKind = "input"       // Param of a wire injector // wire 注入器的参数
+	DependencyNodeUnsatisfied DependencyNodeKind = "unsatisfied" // Needed but never provided // 被需要但从未被提供
+)
+
+// DependencyConstructor represents a NewXxx function in one of the layers
+//
+// DependencyConstructor 表示某一层中的 NewXxx 函数
+type DependencyConstructor struct {
+	Name       string   // Function name, such as NewGreeterUsecase // 函数名，例如 NewGreeterUsecase
+	Package    string   // Package name, such as biz // 包名，例如 biz
+	ImportPath string   // Import path of the package // 包的导入路径
+	Params     []string // Qualified param types // 限定参数类型
+	Results    []string // Qualified result types // 限定返回类型
+	Provides   []string // Result types except the cleanup func and error // 除清理函数和 error 以外的返回类型
+	Cleanup    bool     // Returns a func() cleanup // 返回 func() 清理函数
+	Error      bool     // Returns an error // 返回 error
+	Position   Position // Position of the function // 函数所在的位置
+}
+
+// DependencyNode represents a type in the graph
+//
+// DependencyNode 表示图中的一个类型
+type DependencyNode struct {
+	Type      string             // Qualified type, such as *biz.GreeterUsecase // 限定类型，例如 *biz.GreeterUsecase
+	Kind      DependencyNodeKind // How the type is satisfied // 类型如何被满足
+	Providers []string           // Constructors providing the type as package.Name // 以 package.Name 表示的提供该类型的构造函数
+	Cleanup   bool               // Provided along with a cleanup func // 与清理函数一同提供
+}
+
+// DependencyEdge means the From type is needed to construct the To type
+//
+// DependencyEdge 表示构造 To 类型需要 From 类型
+type DependencyEdge struct {
+	From        string // Needed type // 被需要的类型
+	To          string // Constructed type // 被构造的类型
+	Constructor string // Constructor as package.Name, or wire.Bind // 以 package.Name 表示的构造函数，或 wire.Bind
+}
+
+// UnsatisfiedDependency represents a param type that nothing provides
+//
+// UnsatisfiedDependency 表示没有任何来源提供的参数类型
+type UnsatisfiedDependency struct {
+	Type        string   // Qualified param type // 限定参数类型
+	Constructor string   // Constructor needing the type as package.Name // 以 package.Name 表示的需要该类型的构造函数
+	Position    Position // Position of the constructor // 构造函数的位置
+}
+
+// DependencyGraph holds the constructors, the type nodes and the problems found in them
+//
+// DependencyGraph 保存构造函数、类型节点以及其中发现的问题
+type DependencyGraph struct {
+	Constructors []*DependencyConstructor // Constructors in walk order // 按遍历顺序排列的构造函数
+	Nodes        []*DependencyNode        // Type nodes in order of appearance // 按出现顺序排列的类型节点
+	Edges        []*DependencyEdge        // Needed-to-construct edges // "构造所需"的边
+	Cycles       [][]string               // Types constructing each other, one group per cycle // 相互构造的类型，每个循环一组
+	Unsatisfied  []*UnsatisfiedDependency // Params nothing provides // 没有来源提供的参数
+}
+
+// DOT renders the graph in the Graphviz DOT language
+//
+// DOT 以 Graphviz DOT 语言渲染该图
+func (g *DependencyGraph) DOT() string {
+	var builder strings.Builder
+	builder.WriteString("digraph dependencies {\n")
+	for _, node := range g.Nodes {
+		switch node.Kind {
+		case DependencyNodeInput:
+			builder.WriteString(fmt.Sprintf("\t%q [shape=box];\n", node.Type))
+		case DependencyNodeUnsatisfied:
+			builder.WriteString(fmt.Sprintf("\t%q [color=red];\n", node.Type))
+		default:
+			builder.WriteString(fmt.Sprintf("\t%q;\n", node.Type))
+		}
+	}
+	for _, edge := range g.Edges {
+		builder.WriteString(fmt.Sprintf("\t%q -> %q [label=%q];\n", edge.From, edge.To, edge.Constructor))
+	}
+	builder.WriteString("}\n")
+	return builder.String()
+}
+
+// analyzeDependencyGraph builds the constructor graph of the project
+//
+// analyzeDependencyGraph 构建项目的构造函数图
+func analyzeDependencyGraph(projectRoot string, modulePath string) (*DependencyGraph, error) {
+	project, err := loadWireProject(projectRoot, modulePath)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return project.dependencyGraph(modulePath), nil
+}
+
+// dependencyGraph builds the graph from the constructors in the layers and the wire inputs and bindings
+//
+// dependencyGraph 根据各层中的构造函数以及 wire 的输入和绑定构建图
+func (p *wireProject) dependencyGraph(modulePath string) *DependencyGraph {
+	graph := &DependencyGraph{
+		Constructors: make([]*DependencyConstructor, 0),
+		Nodes:        make([]*DependencyNode, 0),
+		Edges:        make([]*DependencyEdge, 0),
+		Cycles:       make([][]string, 0),
+		Unsatisfied:  make([]*UnsatisfiedDependency, 0),
+	}
+	nodes := map[string]*DependencyNode{}
+	getNode := func(typeName string) *DependencyNode {
+		node, ok := nodes[typeName]
+		if !ok {
+			node = &DependencyNode{Type: typeName, Kind: DependencyNodeUnsatisfied, Providers: make([]string, 0)}
+			nodes[typeName] = node
+			graph.Nodes = append(graph.Nodes, node)
+		}
+		return node
+	}
+
+	for _, pkg := range p.packages {
+		importPath := p.builder.importPaths[pkg]
+		if !isDependencyLayer(modulePath, importPath) {
+			continue
+		}
+		for _, goFile := range pkg.files {
+			imports := importPaths(goFile.astFile)
+			for _, decl := range goFile.astFile.Decls {
+				funcDecl, ok := decl.(*ast.FuncDecl)
+				if !ok || funcDecl.Recv != nil || !funcDecl.Name.IsExported() || !strings.HasPrefix(funcDecl.Name.Name, "New") {
+					continue
+				}
+				constructor := &DependencyConstructor{
+					Name:       funcDecl.Name.Name,
+					Package:    pkg.name,
+					ImportPath: importPath,
+					Params:     qualifiedFieldTypes(funcDecl.Type.Params, pkg.name, imports),
+					Results:    qualifiedFieldTypes(funcDecl.Type.Results, pkg.name, imports),
+					Provides:   make([]string, 0),
+					Position:   Position{Path: goFile.srcPath, Line: goFile.fset.Position(funcDecl.Pos()).Line},
+				}
+				for _, result := range constructor.Results {
+					switch result {
+					case "func()":
+						constructor.Cleanup = true
+					case "error":
+						constructor.Error = true
+					default:
+						constructor.Provides = append(constructor.Provides, result)
+					}
+				}
+				graph.Constructors = append(graph.Constructors, constructor)
+			}
+		}
+	}
+
+	for _, constructor := range graph.Constructors {
+		for _, provided := range constructor.Provides {
+			node := getNode(provided)
+			node.Kind = DependencyNodeConstructed
+			node.Providers = append(node.Providers, constructor.Package+"."+constructor.Name)
+			node.Cleanup = node.Cleanup || constructor.Cleanup
+		}
+	}
+	for _, injector := range p.graph.Injectors {
+		for _, param := range injector.Params {
+			if node := getNode(param); node.Kind == DependencyNodeUnsatisfied {
+				node.Kind = DependencyNodeInput
+			}
+		}
+	}
+	for _, providerSet := range p.graph.ProviderSets {
+		for _, provider := range providerSet.Providers {
+			if provider.Kind != WireProviderKindBind {
+				continue
+			}
+			if node := getNode(provider.Type); node.Kind == DependencyNodeUnsatisfied {
+				node.Kind = DependencyNodeBound
+			}
+			getNode(provider.Implementation)
+			graph.Edges = append(graph.Edges, &DependencyEdge{From: provider.Implementation, To: provider.Type, Constructor: "wire.Bind"})
+		}
+	}
+
+	for _, constructor := range graph.Constructors {
+		name := constructor.Package + "." + constructor.Name
+		for _, param := range constructor.Params {
+			if node := getNode(param); node.Kind == DependencyNodeUnsatisfied {
+				graph.Unsatisfied = append(graph.Unsatisfied, &UnsatisfiedDependency{
+					Type:        param,
+					Constructor: name,
+					Position:    constructor.Position,
+				})
+			}
+			for _, provided := range constructor.Provides {
+				graph.Edges = append(graph.Edges, &DependencyEdge{From: param, To: provided, Constructor: name})
+			}
+		}
+	}
+	graph.Cycles = findDependencyCycles(graph.Nodes, graph.Edges)
+	return graph
+}
+
+// isDependencyLayer reports whether the import path is one of the layers or below it
+//
+// isDependencyLayer 判断导入路径是否为某一层或位于其下
+func isDependencyLayer(modulePath string, importPath string) bool {
+	for _, layer := range dependencyLayers {
+		if isLayerImportPath(modulePath, importPath, layer) {
+			return true
+		}
+	}
+	return false
+}
+
+// isLayerImportPath reports whether the import path is internal/layer of the module or below it
+//
+// isLayerImportPath 判断导入路径是否为模块的 internal/layer 或位于其下
+func isLayerImportPath(modulePath string, importPath string, layer string) bool {
+	layerPath := modulePath + "/internal/" + layer
+	return importPath == layerPath || strings.HasPrefix(importPath, layerPath+"/")
+}
+
+// findDependencyCycles finds the strongly connected components forming cycles with Tarjan's algorithm
+// Types in each cycle follow the node order, and the cycles follow the order of their first type
+//
+// findDependencyCycles 使用 Tarjan 算法查找构成循环的强连通分量
+// 每个循环中的类型按节点顺序排列，循环之间按其首个类型的顺序排列
+func findDependencyCycles(nodes []*DependencyNode, edges []*DependencyEdge) [][]string {
+	orders := map[string]int{}
+	for idx, node := range nodes {
+		orders[node.Type] = idx
+	}
+	adjacency := map[string][]string{}
+	selfLoops := map[string]bool{}
+	for _, edge := range edges {
+		adjacency[edge.From] = append(adjacency[edge.From], edge.To)
+		if edge.From == edge.To {
+			selfLoops[edge.From] = true
+		}
+	}
+
+	var (
+		index   = 0
+		indexes = map[string]int{}
+		lowLink = map[string]int{}
+		onStack = map[string]bool{}
+		stack   []string
+		cycles  = make([][]string, 0)
+	)
+	var connect func(typeName string)
+	connect = func(typeName string) {
+		indexes[typeName] = index
+		lowLink[typeName] = index
+		index++
+		stack = append(stack, typeName)
+		onStack[typeName] = true
+		for _, next := range adjacency[typeName] {
+			if _, visited := indexes[next]; !visited {
+				connect(next)
+				lowLink[typeName] = min(lowLink[typeName], lowLink[next])
+			} else if onStack[next] {
+				lowLink[typeName] = min(lowLink[typeName], indexes[next])
+			}
+		}
+		if lowLink[typeName] != indexes[typeName] {
+			return
+		}
+		var component []string
+		for {
+			last := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[last] = false
+			component = append(component, last)
+			if last == typeName {
+				break
+			}
+		}
+		if len(component) > 1 || selfLoops[typeName] {
+			slices.SortFunc(component, func(a, b string) int { return orders[a] - orders[b] })
+			cycles = append(cycles, component)
+		}
+	}
+	for _, node := range nodes {
+		if _, visited := indexes[node.Type]; !visited {
+			connect(node.Type)
+		}
+	}
+	slices.SortFunc(cycles, func(a, b []string) int { return orders[a[0]] - orders[b[0]] })
+	return cycles
+}
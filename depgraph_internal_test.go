@@ -0,0 +1,76 @@
+package astkratos
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/rese"
+)
+
+// TestAnalyzeDependencyGraph_Problems tests cycle detection, unsatisfied params and wire.Bind edges
+//
+// TestAnalyzeDependencyGraph_Problems 测试循环检测、无法满足的参数以及 wire.Bind 边
+func TestAnalyzeDependencyGraph_Problems(t *testing.T) {
+	root := t.TempDir()
+	bizRoot := filepath.Join(root, "internal", "biz")
+	must.Done(os.MkdirAll(bizRoot, 0755))
+	must.Done(os.WriteFile(filepath.Join(bizRoot, "biz.go"), []byte(`package biz
+
+import "time"
+
+type Repo interface{}
+
+type A struct{}
+
+type B struct{}
+
+type C struct{}
+
+func NewA(b *B) *A {
+	return &A{}
+}
+
+func NewB(a *A, clock time.Duration) (*B, error) {
+	return &B{}, nil
+}
+
+func NewC(c *C, repo Repo) *C {
+	return c
+}
+`), 0644))
+	dataRoot := filepath.Join(root, "internal", "data")
+	must.Done(os.MkdirAll(dataRoot, 0755))
+	must.Done(os.WriteFile(filepath.Join(dataRoot, "data.go"), []byte(`package data
+
+import (
+	"demo/internal/biz"
+
+	"github.com/google/wire"
+)
+
+var ProviderSet = wire.NewSet(NewRepo, wire.Bind(new(biz.Repo), new(*repo)))
+
+type repo struct{}
+
+func NewRepo() *repo {
+	return &repo{}
+}
+`), 0644))
+
+	graph := rese.P1(analyzeDependencyGraph(root, "demo"))
+	require.Len(t, graph.Constructors, 4)
+	require.True(t, graph.Constructors[1].Error)
+	require.Equal(t, []string{"*biz.B"}, graph.Constructors[1].Provides)
+
+	require.Equal(t, [][]string{{"*biz.A", "*biz.B"}, {"*biz.C"}}, graph.Cycles)
+
+	require.Len(t, graph.Unsatisfied, 1)
+	require.Equal(t, "time.Duration", graph.Unsatisfied[0].Type)
+	require.Equal(t, "biz.NewB", graph.Unsatisfied[0].Constructor)
+	require.Equal(t, 17, graph.Unsatisfied[0].Position.Line)
+
+	require.Contains(t, graph.Edges, &DependencyEdge{From: "*data.repo", To: "biz.Repo", Constructor: "wire.Bind"})
+}
@@ -0,0 +1,53 @@
+package astkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/astkratos"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/neatjson/neatjsons"
+	"github.com/yyle88/runpath"
+)
+
+// TestGetDependencyGraph tests the constructor graph of the demo project
+//
+// TestGetDependencyGraph 测试示例项目的构造函数图
+func TestGetDependencyGraph(t *testing.T) {
+	graph := astkratos.GetDependencyGraph(runpath.PARENT.Join("testdata", "demokratos"))
+	t.Log(neatjsons.S(graph))
+
+	var constructors []string
+	for _, constructor := range graph.Constructors {
+		constructors = append(constructors, constructor.Package+"."+constructor.Name)
+	}
+	require.ElementsMatch(t, []string{
+		"biz.NewGreeterUsecase",
+		"data.NewData",
+		"data.NewGreeterRepo",
+		"server.NewGRPCServer",
+		"server.NewHTTPServer",
+		"service.NewEchoService",
+		"service.NewGreeterService",
+	}, constructors)
+
+	nodes := map[string]*astkratos.DependencyNode{}
+	for _, node := range graph.Nodes {
+		nodes[node.Type] = node
+	}
+	require.Equal(t, astkratos.DependencyNodeConstructed, nodes["*data.Data"].Kind)
+	require.True(t, nodes["*data.Data"].Cleanup)
+	require.Equal(t, []string{"data.NewGreeterRepo"}, nodes["biz.GreeterRepo"].Providers)
+	require.Equal(t, astkratos.DependencyNodeInput, nodes["*conf.Data"].Kind)
+	require.Equal(t, astkratos.DependencyNodeInput, nodes["log.Logger"].Kind)
+	require.NotContains(t, nodes, "func()")
+	require.NotContains(t, nodes, "error")
+
+	require.Contains(t, graph.Edges, &astkratos.DependencyEdge{From: "biz.GreeterRepo", To: "*biz.GreeterUsecase", Constructor: "biz.NewGreeterUsecase"})
+	require.Empty(t, graph.Cycles)
+	require.Empty(t, graph.Unsatisfied)
+
+	dot := graph.DOT()
+	t.Log(dot)
+	require.Contains(t, dot, "\t\"*service.GreeterService\" -> \"*grpc.Server\" [label=\"server.NewGRPCServer\"];\n")
+	require.Contains(t, dot, "\t\"log.Logger\" [shape=box];\n")
+}
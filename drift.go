@@ -0,0 +1,401 @@
+// Package astkratos proto drift: Comparison of .proto files with their generated Go code
+// Matches each .proto file with the _grpc.pb.go and _http.pb.go files of the same basename
+// Reports services, RPCs and HTTP bindings that are missing, extra or changed in the generated code
+// Carries positions on both sides so that CI can point at the stale code generation
+//
+// astkratos proto 漂移：比较 .proto 文件与其生成的 Go 代码
+// 将每个 .proto 文件与同名的 _grpc.pb.go 和 _http.pb.go 文件进行匹配
+// 报告生成代码中缺失、多余或已变更的服务、RPC 和 HTTP 绑定
+// 同时携带两侧的位置信息，便于 CI 定位过期的代码生成
+package astkratos
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Position represents a location in a source file
+//
+// Position 表示源文件中的位置
+type Position struct {
+	Path string // File path // 文件路径
+	Line int    // Line number, 0 when unknown // 行号，未知时为 0
+}
+
+// String returns the position in path:line form
+//
+// String 以 path:line 形式返回位置
+func (p Position) String() string {
+	if p.Line <= 0 {
+		return p.Path
+	}
+	return fmt.Sprintf("%s:%d", p.Path, p.Line)
+}
+
+// ProtoDriftKind represents how the generated code differs from the .proto file
+//
+// ProtoDriftKind 表示生成代码与 .proto 文件的差异类型
+type ProtoDriftKind string
+
+const (
+	ProtoDriftMissing ProtoDriftKind = "missing" // Declared in the .proto file but absent from the generated code // 在 .proto 文件中声明但生成代码中缺失
+	ProtoDriftExtra   ProtoDriftKind = "extra"   // Present in the generated code but not declared in the .proto file // 存在于生成代码中但 .proto 文件中未声明
+	ProtoDriftChanged ProtoDriftKind = "changed" // Present on both sides with different definitions // 两侧都存在但定义不同
+	ProtoDriftUnread  ProtoDriftKind = "unread"  // The .proto or _http.pb.go file could not be parsed, so it was not compared // .proto 或 _http.pb.go 文件无法解析，因此未比较
+)
+
+// ProtoDriftTarget represents the kind of entry that drifted
+//
+// ProtoDriftTarget 表示发生漂移的条目类型
+type ProtoDriftTarget string
+
+const (
+	ProtoDriftFile    ProtoDriftTarget = "file"    // Generated file // 生成文件
+	ProtoDriftService ProtoDriftTarget = "service" // gRPC service // gRPC 服务
+	ProtoDriftRpc     ProtoDriftTarget = "rpc"     // RPC method // RPC 方法
+	ProtoDriftHttp    ProtoDriftTarget = "http"    // HTTP binding // HTTP 绑定
+)
+
+// ProtoDrift represents one difference between a .proto file and its generated code
+//
+// ProtoDrift 表示 .proto 文件与其生成代码之间的一处差异
+type ProtoDrift struct {
+	Kind              ProtoDriftKind   // Missing, extra or changed // 缺失、多余或已变更
+	Target            ProtoDriftTarget // File, service, rpc or http // 文件、服务、RPC 或 HTTP
+	Service           string           // Service name, such as Greeter // 服务名称，例如 Greeter
+	RpcMethod         string           // RPC method name, blank for file and service entries // RPC 方法名称，文件和服务条目为空
+	Detail            string           // Human readable description // 可读的描述
+	ProtoPosition     Position         // Position in the .proto file // 在 .proto 文件中的位置
+	GeneratedPosition Position         // Position in the generated file // 在生成文件中的位置
+}
+
+// String returns the drift in a single line suitable for CI logs
+//
+// String 以适合 CI 日志的单行形式返回漂移
+func (d *ProtoDrift) String() string {
+	position := d.ProtoPosition
+	if position.Path == "" {
+		position = d.GeneratedPosition
+	}
+	return fmt.Sprintf("%s: %s %s: %s", position, d.Kind, d.Target, d.Detail)
+}
+
+// checkProtoDrifts compares each scanned .proto file with the generated files of the same basename
+// The .proto and _http.pb.go files failing to parse come first as unread drifts
+//
+// checkProtoDrifts 将每个已扫描的 .proto 文件与同名生成文件进行比较
+// 解析失败的 .proto 和 _http.pb.go 文件作为未读取漂移排在最前面
+func (r *apiScanResult) checkProtoDrifts() []*ProtoDrift {
+	grpcFiles := map[string]*grpcPbGoFile{}
+	for _, grpcFile := range r.grpcFiles {
+		grpcFiles[grpcFile.srcPath] = grpcFile
+	}
+	httpFiles := map[string]*httpPbGoFile{}
+	for _, httpFile := range r.httpFiles {
+		httpFiles[httpFile.srcPath] = httpFile
+	}
+
+	drifts := make([]*ProtoDrift, 0)
+	for _, failure := range r.protoFailures {
+		drifts = append(drifts, &ProtoDrift{
+			Kind:          ProtoDriftUnread,
+			Target:        ProtoDriftFile,
+			Detail:        failure.err.Error(),
+			ProtoPosition: Position{Path: failure.path},
+		})
+	}
+	unreadHttpPaths := map[string]bool{}
+	for _, failure := range r.httpFailures {
+		unreadHttpPaths[failure.path] = true
+		drifts = append(drifts, &ProtoDrift{
+			Kind:              ProtoDriftUnread,
+			Target:            ProtoDriftFile,
+			Detail:            failure.err.Error(),
+			GeneratedPosition: Position{Path: failure.path},
+		})
+	}
+	for _, protoFile := range r.protoFiles {
+		if len(protoFile.Services) == 0 {
+			continue
+		}
+		basePath := strings.TrimSuffix(protoFile.Path, ".proto")
+		grpcPath := basePath + r.suffixes.Grpc
+		if grpcFile, ok := grpcFiles[grpcPath]; ok {
+			drifts = append(drifts, compareProtoGrpc(protoFile, grpcFile)...)
+		} else {
+			drifts = append(drifts, newMissingFileDrift(protoFile, grpcPath, protoFile.Services[0].Line))
+		}
+
+		httpPath := basePath + r.suffixes.Http
+		if httpFile, ok := httpFiles[httpPath]; ok {
+			drifts = append(drifts, compareProtoHttp(protoFile, httpFile)...)
+		} else if line := firstHttpRuleLine(protoFile); line > 0 && !unreadHttpPaths[httpPath] {
+			drifts = append(drifts, newMissingFileDrift(protoFile, httpPath, line))
+		}
+	}
+	return drifts
+}
+
+// newMissingFileDrift reports a generated file that does not exist
+//
+// newMissingFileDrift 报告不存在的生成文件
+func newMissingFileDrift(protoFile *ProtoFile, generatedPath string, line int) *ProtoDrift {
+	return &ProtoDrift{
+		Kind:              ProtoDriftMissing,
+		Target:            ProtoDriftFile,
+		Detail:            "generated file " + filepath.Base(generatedPath) + " not found",
+		ProtoPosition:     Position{Path: protoFile.Path, Line: line},
+		GeneratedPosition: Position{Path: generatedPath},
+	}
+}
+
+// firstHttpRuleLine returns the line of the first google.api.http annotation, 0 when absent
+//
+// firstHttpRuleLine 返回第一个 google.api.http 注解的行号，不存在时返回 0
+func firstHttpRuleLine(protoFile *ProtoFile) int {
+	for _, service := range protoFile.Services {
+		for _, rpc := range service.Rpcs {
+			if len(rpc.HttpRules) > 0 {
+				return rpc.HttpRules[0].Line
+			}
+		}
+	}
+	return 0
+}
+
+// compareProtoGrpc compares the services and RPCs of the .proto file with the _grpc.pb.go file
+//
+// compareProtoGrpc 比较 .proto 文件与 _grpc.pb.go 文件中的服务和 RPC
+func compareProtoGrpc(protoFile *ProtoFile, grpcFile *grpcPbGoFile) []*ProtoDrift {
+	drifts := make([]*ProtoDrift, 0)
+	services := map[string]*GrpcTypeDefinition{}
+	for _, service := range grpcFile.services {
+		services[service.Name] = service
+	}
+
+	protoServices := map[string]bool{}
+	for _, protoService := range protoFile.Services {
+		protoServices[protoService.Name] = true
+		service, ok := services[protoService.Name]
+		if !ok {
+			drifts = append(drifts, &ProtoDrift{
+				Kind:              ProtoDriftMissing,
+				Target:            ProtoDriftService,
+				Service:           protoService.Name,
+				Detail:            "service " + protoService.Name + " is not generated",
+				ProtoPosition:     Position{Path: protoFile.Path, Line: protoService.Line},
+				GeneratedPosition: Position{Path: grpcFile.srcPath},
+			})
+			continue
+		}
+
+		methods := map[string]*GrpcMethodDefinition{}
+		for _, method := range service.Methods {
+			methods[method.Name] = method
+		}
+		protoRpcs := map[string]bool{}
+		for _, rpc := range protoService.Rpcs {
+			protoRpcs[rpc.Name] = true
+			protoPosition := Position{Path: protoFile.Path, Line: rpc.Line}
+			method, ok := methods[rpc.Name]
+			if !ok {
+				drifts = append(drifts, &ProtoDrift{
+					Kind:              ProtoDriftMissing,
+					Target:            ProtoDriftRpc,
+					Service:           protoService.Name,
+					RpcMethod:         rpc.Name,
+					Detail:            "rpc " + protoService.Name + "." + rpc.Name + " is not generated",
+					ProtoPosition:     protoPosition,
+					GeneratedPosition: Position{Path: grpcFile.srcPath, Line: grpcFile.serviceLines[service.Name]},
+				})
+				continue
+			}
+
+			var changes []string
+			if kind := rpc.StreamingKind(); kind != method.StreamingKind {
+				changes = append(changes, fmt.Sprintf("streaming kind %s, generated %s", kind, method.StreamingKind))
+			}
+			if !matchProtoGoType(rpc.RequestType, method.RequestType) {
+				changes = append(changes, fmt.Sprintf("request type %s, generated %s", rpc.RequestType, method.RequestType))
+			}
+			if !matchProtoGoType(rpc.ResponseType, method.ResponseType) {
+				changes = append(changes, fmt.Sprintf("response type %s, generated %s", rpc.ResponseType, method.ResponseType))
+			}
+			if len(changes) > 0 {
+				drifts = append(drifts, &ProtoDrift{
+					Kind:              ProtoDriftChanged,
+					Target:            ProtoDriftRpc,
+					Service:           protoService.Name,
+					RpcMethod:         rpc.Name,
+					Detail:            "rpc " + protoService.Name + "." + rpc.Name + " has " + strings.Join(changes, "; "),
+					ProtoPosition:     protoPosition,
+					GeneratedPosition: Position{Path: grpcFile.srcPath, Line: method.Line},
+				})
+			}
+		}
+		for _, method := range service.Methods {
+			if !protoRpcs[method.Name] {
+				drifts = append(drifts, &ProtoDrift{
+					Kind:              ProtoDriftExtra,
+					Target:            ProtoDriftRpc,
+					Service:           service.Name,
+					RpcMethod:         method.Name,
+					Detail:            "rpc " + service.Name + "." + method.Name + " is not declared in the proto",
+					ProtoPosition:     Position{Path: protoFile.Path, Line: protoService.Line},
+					GeneratedPosition: Position{Path: grpcFile.srcPath, Line: method.Line},
+				})
+			}
+		}
+	}
+
+	for _, service := range grpcFile.services {
+		if !protoServices[service.Name] {
+			drifts = append(drifts, &ProtoDrift{
+				Kind:              ProtoDriftExtra,
+				Target:            ProtoDriftService,
+				Service:           service.Name,
+				Detail:            "service " + service.Name + " is not declared in the proto",
+				ProtoPosition:     Position{Path: protoFile.Path},
+				GeneratedPosition: Position{Path: grpcFile.srcPath, Line: grpcFile.serviceLines[service.Name]},
+			})
+		}
+	}
+	return drifts
+}
+
+// protoHttpBinding represents one HTTP binding on either side of the comparison
+//
+// protoHttpBinding 表示比较中任一侧的一个 HTTP 绑定
+type protoHttpBinding struct {
+	service    string // Service name // 服务名称
+	rpcMethod  string // RPC method name // RPC 方法名称
+	httpMethod string // HTTP verb // HTTP 方法
+	path       string // Path in the kratos router form // kratos 路由形式的路径
+	line       int    // Line number // 行号
+}
+
+func (b *protoHttpBinding) key() string {
+	return b.service + "." + b.rpcMethod + " " + b.httpMethod + " " + b.path
+}
+
+// compareProtoHttp compares the google.api.http bindings of the .proto file with the _http.pb.go routes
+// A missing and an extra binding of the same RPC and verb are reported together as a changed path
+//
+// compareProtoHttp 比较 .proto 文件中的 google.api.http 绑定与 _http.pb.go 中的路由
+// 同一 RPC 和 HTTP 方法的缺失绑定与多余绑定合并报告为路径变更
+func compareProtoHttp(protoFile *ProtoFile, httpFile *httpPbGoFile) []*ProtoDrift {
+	var protoBindings []*protoHttpBinding
+	for _, service := range protoFile.Services {
+		for _, rpc := range service.Rpcs {
+			for _, rule := range rpc.HttpRules {
+				protoBindings = append(protoBindings, &protoHttpBinding{
+					service:    service.Name,
+					rpcMethod:  rpc.Name,
+					httpMethod: strings.ToUpper(rule.Method),
+					path:       kratosHttpPath(rule.Path),
+					line:       rule.Line,
+				})
+			}
+		}
+	}
+	var generatedBindings []*protoHttpBinding
+	for _, route := range httpFile.routes {
+		generatedBindings = append(generatedBindings, &protoHttpBinding{
+			service:    route.Service,
+			rpcMethod:  route.RpcMethod,
+			httpMethod: strings.ToUpper(route.HttpMethod),
+			path:       route.PathTemplate,
+			line:       route.Line,
+		})
+	}
+
+	protoKeys := map[string]bool{}
+	for _, binding := range protoBindings {
+		protoKeys[binding.key()] = true
+	}
+	generatedKeys := map[string]bool{}
+	for _, binding := range generatedBindings {
+		generatedKeys[binding.key()] = true
+	}
+	var extras []*protoHttpBinding
+	for _, binding := range generatedBindings {
+		if !protoKeys[binding.key()] {
+			extras = append(extras, binding)
+		}
+	}
+
+	drifts := make([]*ProtoDrift, 0)
+	for _, binding := range protoBindings {
+		if generatedKeys[binding.key()] {
+			continue
+		}
+		drift := &ProtoDrift{
+			Kind:              ProtoDriftMissing,
+			Target:            ProtoDriftHttp,
+			Service:           binding.service,
+			RpcMethod:         binding.rpcMethod,
+			Detail:            "binding " + binding.httpMethod + " " + binding.path + " of " + binding.service + "." + binding.rpcMethod + " is not generated",
+			ProtoPosition:     Position{Path: protoFile.Path, Line: binding.line},
+			GeneratedPosition: Position{Path: httpFile.srcPath},
+		}
+		for idx, extra := range extras {
+			if extra.service == binding.service && extra.rpcMethod == binding.rpcMethod && extra.httpMethod == binding.httpMethod {
+				drift.Kind = ProtoDriftChanged
+				drift.Detail = "binding " + binding.httpMethod + " of " + binding.service + "." + binding.rpcMethod + " has path " + binding.path + ", generated " + extra.path
+				drift.GeneratedPosition.Line = extra.line
+				extras = append(extras[:idx], extras[idx+1:]...)
+				break
+			}
+		}
+		drifts = append(drifts, drift)
+	}
+	for _, extra := range extras {
+		drifts = append(drifts, &ProtoDrift{
+			Kind:              ProtoDriftExtra,
+			Target:            ProtoDriftHttp,
+			Service:           extra.service,
+			RpcMethod:         extra.rpcMethod,
+			Detail:            "binding " + extra.httpMethod + " " + extra.path + " of " + extra.service + "." + extra.rpcMethod + " is not declared in the proto",
+			ProtoPosition:     Position{Path: protoFile.Path},
+			GeneratedPosition: Position{Path: httpFile.srcPath, Line: extra.line},
+		})
+	}
+	return drifts
+}
+
+// protoPathVariablePattern matches path variables with a pattern, such as {name=messages/*}
+//
+// protoPathVariablePattern 匹配带模式的路径变量，例如 {name=messages/*}
+var protoPathVariablePattern = regexp.MustCompile(`\{\s*([\w.]+)\s*=\s*([^}]*)\}`)
+
+// kratosHttpPath converts a google.api.http path template to the form registered by protoc-gen-go-http
+// Variables with a pattern become {name:pattern} with * widened to .*
+//
+// kratosHttpPath 将 google.api.http 路径模板转换为 protoc-gen-go-http 注册的形式
+// 带模式的变量转换为 {name:pattern}，其中 * 扩展为 .*
+func kratosHttpPath(path string) string {
+	return protoPathVariablePattern.ReplaceAllStringFunc(path, func(match string) string {
+		parts := protoPathVariablePattern.FindStringSubmatch(match)
+		return "{" + parts[1] + ":" + strings.ReplaceAll(parts[2], "*", ".*") + "}"
+	})
+}
+
+// matchProtoGoType reports whether the proto message type matches the generated Go type
+// Package qualifiers are ignored and nested proto types match the Outer_Inner Go names
+//
+// matchProtoGoType 判断 proto 消息类型是否与生成的 Go 类型一致
+// 忽略包限定符，嵌套 proto 类型与 Outer_Inner 形式的 Go 名称匹配
+func matchProtoGoType(protoType string, goType string) bool {
+	if idx := strings.LastIndex(goType, "."); idx >= 0 {
+		goType = goType[idx+1:]
+	}
+	parts := strings.Split(strings.TrimPrefix(protoType, "."), ".")
+	for idx := range parts {
+		if strings.Join(parts[idx:], "_") == goType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package astkratos_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/orzkratos/astkratos"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/neatjson/neatjsons"
+	"github.com/yyle88/rese"
+	"github.com/yyle88/runpath"
+)
+
+// TestCheckProtoDrift tests that the demo api tree is in sync with its generated code
+//
+// TestCheckProtoDrift 测试演示 api 目录树与其生成代码保持同步
+func TestCheckProtoDrift(t *testing.T) {
+	drifts := astkratos.CheckProtoDrift(demoApiRoot)
+	t.Log(neatjsons.S(drifts))
+	require.Empty(t, drifts)
+}
+
+// TestCheckProtoDrift_Stale tests drift reports after the proto is edited without regenerating
+//
+// TestCheckProtoDrift_Stale 测试修改 proto 但未重新生成代码后的漂移报告
+func TestCheckProtoDrift_Stale(t *testing.T) {
+	root := t.TempDir()
+	sourceRoot := runpath.PARENT.Join("testdata", "demokratos", "api", "helloworld", "v1")
+	for _, name := range []string{"greeter_grpc.pb.go", "greeter_http.pb.go"} {
+		must.Done(os.WriteFile(filepath.Join(root, name), rese.V1(os.ReadFile(filepath.Join(sourceRoot, name))), 0644))
+	}
+	protoPath := filepath.Join(root, "greeter.proto")
+	must.Done(os.WriteFile(protoPath, []byte(`syntax = "proto3";
+
+package helloworld.v1;
+
+service Greeter {
+  rpc SayHello (HelloRequest) returns (stream HelloReply) {
+    option (google.api.http) = {
+      get: "/v2/helloworld/{name}"
+    };
+  }
+  rpc SayGoodbye (HelloRequest) returns (HelloReply);
+}
+
+service Farewell {
+  rpc Wave (HelloRequest) returns (HelloReply);
+}
+`), 0644))
+
+	drifts := astkratos.CheckProtoDrift(root)
+	t.Log(neatjsons.S(drifts))
+
+	grpcPath := filepath.Join(root, "greeter_grpc.pb.go")
+	httpPath := filepath.Join(root, "greeter_http.pb.go")
+	require.Equal(t, []*astkratos.ProtoDrift{
+		{
+			Kind:              astkratos.ProtoDriftChanged,
+			Target:            astkratos.ProtoDriftRpc,
+			Service:           "Greeter",
+			RpcMethod:         "SayHello",
+			Detail:            "rpc Greeter.SayHello has streaming kind server, generated unary",
+			ProtoPosition:     astkratos.Position{Path: protoPath, Line: 6},
+			GeneratedPosition: astkratos.Position{Path: grpcPath, Line: 60},
+		},
+		{
+			Kind:              astkratos.ProtoDriftMissing,
+			Target:            astkratos.ProtoDriftRpc,
+			Service:           "Greeter",
+			RpcMethod:         "SayGoodbye",
+			Detail:            "rpc Greeter.SayGoodbye is not generated",
+			ProtoPosition:     astkratos.Position{Path: protoPath, Line: 11},
+			GeneratedPosition: astkratos.Position{Path: grpcPath, Line: 58},
+		},
+		{
+			Kind:              astkratos.ProtoDriftMissing,
+			Target:            astkratos.ProtoDriftService,
+			Service:           "Farewell",
+			Detail:            "service Farewell is not generated",
+			ProtoPosition:     astkratos.Position{Path: protoPath, Line: 14},
+			GeneratedPosition: astkratos.Position{Path: grpcPath},
+		},
+		{
+			Kind:              astkratos.ProtoDriftChanged,
+			Target:            astkratos.ProtoDriftHttp,
+			Service:           "Greeter",
+			RpcMethod:         "SayHello",
+			Detail:            "binding GET of Greeter.SayHello has path /v2/helloworld/{name}, generated /helloworld/{name}",
+			ProtoPosition:     astkratos.Position{Path: protoPath, Line: 7},
+			GeneratedPosition: astkratos.Position{Path: httpPath, Line: 31},
+		},
+		{
+			Kind:              astkratos.ProtoDriftExtra,
+			Target:            astkratos.ProtoDriftHttp,
+			Service:           "Greeter",
+			RpcMethod:         "SayHello",
+			Detail:            "binding POST /helloworld of Greeter.SayHello is not declared in the proto",
+			ProtoPosition:     astkratos.Position{Path: protoPath},
+			GeneratedPosition: astkratos.Position{Path: httpPath, Line: 32},
+		},
+	}, drifts)
+	require.Equal(t, protoPath+":6: changed rpc: rpc Greeter.SayHello has streaming kind server, generated unary", drifts[0].String())
+}
+
+// TestCheckProtoDrift_MissingFile tests drift reports when the generated files do not exist
+//
+// TestCheckProtoDrift_MissingFile 测试生成文件不存在时的漂移报告
+func TestCheckProtoDrift_MissingFile(t *testing.T) {
+	root := t.TempDir()
+	protoPath := filepath.Join(root, "greeter.proto")
+	must.Done(os.WriteFile(protoPath, rese.V1(os.ReadFile(runpath.PARENT.Join("testdata", "demokratos", "api", "helloworld", "v1", "greeter.proto"))), 0644))
+
+	drifts := astkratos.CheckProtoDrift(root)
+	require.Len(t, drifts, 2)
+	require.Equal(t, "generated file greeter_grpc.pb.go not found", drifts[0].Detail)
+	require.Equal(t, astkratos.Position{Path: protoPath, Line: 13}, drifts[0].ProtoPosition)
+	require.Equal(t, "generated file greeter_http.pb.go not found", drifts[1].Detail)
+	require.Equal(t, astkratos.Position{Path: protoPath, Line: 16}, drifts[1].ProtoPosition)
+}
+
+// TestCheckProtoDrift_Unread tests that a .proto file the parser cannot read leaves the generated listings intact
+//
+// TestCheckProtoDrift_Unread 测试解析器无法读取的 .proto 文件不影响生成代码的列表
+func TestCheckProtoDrift_Unread(t *testing.T) {
+	root := t.TempDir()
+	must.Done(os.WriteFile(filepath.Join(root, "echo_grpc.pb.go"), rese.V1(os.ReadFile(filepath.Join(demoApiRoot, "echo", "v1", "echo_grpc.pb.go"))), 0644))
+	protoPath := filepath.Join(root, "weird.proto")
+	must.Done(os.WriteFile(protoPath, []byte(`syntax = "proto2";
+
+message Weird {
+  optional group Result = 1 {
+    required string url = 2;
+  }
+}
+`), 0644))
+
+	require.Len(t, astkratos.ListGrpcClients(root), 1)
+	require.Len(t, astkratos.ListGrpcServers(root), 1)
+	require.Equal(t, []string{"Echo"}, collectNames(astkratos.ListGrpcServices(root)))
+
+	drifts := astkratos.CheckProtoDrift(root)
+	require.Len(t, drifts, 1)
+	require.Equal(t, astkratos.ProtoDriftUnread, drifts[0].Kind)
+	require.Equal(t, astkratos.Position{Path: protoPath}, drifts[0].ProtoPosition)
+	require.Contains(t, drifts[0].Detail, protoPath+":4:")
+
+	_, err := astkratos.ListProtoFilesE(root)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), protoPath)
+}
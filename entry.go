@@ -0,0 +1,423 @@
+// Package astkratos error-returning entry points: Variants of every analysis function without panics
+// Each XxxE function returns the error of the failing IO or parse step instead of panicking
+// Errors name the file or directory at fault, so that a long-running host can report and go on
+// The panic-style functions of astkratos.go are thin wrappers over these variants
+//
+// astkratos 返回错误的入口：不会 panic 的各分析函数变体
+// 每个 XxxE 函数返回失败的 IO 或解析步骤的错误，而不是 panic
+// 错误中会指明出错的文件或目录，使长期运行的宿主程序能够报告并继续运行
+// astkratos.go 中 panic 风格的函数是这些变体的轻量包装
+package astkratos
+
+import (
+	"context"
+	"go/ast"
+	"os"
+	"path/filepath"
+
+	"github.com/yyle88/erero"
+	"github.com/yyle88/syntaxgo/syntaxgo_ast"
+	"github.com/yyle88/syntaxgo/syntaxgo_astnode"
+	"github.com/yyle88/syntaxgo/syntaxgo_search"
+	"github.com/yyle88/zaplog"
+)
+
+// projectApiRoot returns the api directory of the project, failing when it does not exist
+//
+// projectApiRoot 返回项目的 api 目录，不存在时返回错误
+func projectApiRoot(projectRoot string) (string, error) {
+	return checkApiRoot(filepath.Join(projectRoot, "api"))
+}
+
+// projectImportPath returns the import path of the project root, which is below the module path when the app is nested
+//
+// projectImportPath 返回项目根目录的导入路径，应用嵌套时位于模块路径之下
+func projectImportPath(projectRoot string) (string, error) {
+	module, err := resolveProjectModule(projectRoot)
+	if err != nil {
+		return "", erero.Wro(err)
+	}
+	return module.importPath, nil
+}
+
+// ListGrpcClientsE lists gRPC client types in the specified root path, returning errors
+//
+// ListGrpcClientsE 列出指定根目录下的 gRPC 客户端类型，返回错误
+func ListGrpcClientsE(root string) ([]*GrpcTypeDefinition, error) {
+	apiScan, err := scanApiFiles(root, scanGrpcFiles)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return apiScan.listClients(), nil
+}
+
+// ListGrpcServersE lists gRPC server types in the specified root path, returning errors
+//
+// ListGrpcServersE 列出指定根目录下的 gRPC 服务器类型，返回错误
+func ListGrpcServersE(root string) ([]*GrpcTypeDefinition, error) {
+	apiScan, err := scanApiFiles(root, scanGrpcFiles)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return apiScan.listServers(), nil
+}
+
+// ListGrpcUnimplementedServersE lists unimplemented gRPC server types in the specified root path, returning errors
+//
+// ListGrpcUnimplementedServersE 列出指定根目录下的未实现 gRPC 服务器类型，返回错误
+func ListGrpcUnimplementedServersE(root string) ([]*GrpcTypeDefinition, error) {
+	apiScan, err := scanApiFiles(root, scanGrpcFiles)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return apiScan.listUnimplementedServers(), nil
+}
+
+// ListGrpcServicesE lists gRPC services in the specified root path, returning errors
+//
+// ListGrpcServicesE 列出指定根目录下的 gRPC 服务，返回错误
+func ListGrpcServicesE(root string) ([]*GrpcTypeDefinition, error) {
+	apiScan, err := scanApiFiles(root, scanGrpcFiles)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return apiScan.listServices(), nil
+}
+
+// ListGrpcServiceDescriptorsE lists decoded grpc.ServiceDesc variables in the specified root path, returning errors
+//
+// ListGrpcServiceDescriptorsE 列出指定根目录下解码后的 grpc.ServiceDesc 变量，返回错误
+func ListGrpcServiceDescriptorsE(root string) ([]*ServiceDescriptor, error) {
+	apiScan, err := scanApiFiles(root, scanGrpcFiles)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return apiScan.listServiceDescriptors(), nil
+}
+
+// ListHttpRoutesE lists HTTP routes of the generated _http.pb.go files in the specified root path, returning errors
+//
+// ListHttpRoutesE 列出指定根目录下生成的 _http.pb.go 文件中的 HTTP 路由，返回错误
+func ListHttpRoutesE(root string) ([]*HttpRouteDefinition, error) {
+	apiScan, err := scanApiFiles(root, scanHttpFiles)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return apiScan.listHttpRoutes()
+}
+
+// ListErrorReasonsE lists Kratos error reasons of the _errors.pb.go files in the specified root path, returning errors
+//
+// ListErrorReasonsE 列出指定根目录下 _errors.pb.go 文件中的 Kratos 错误原因，返回错误
+func ListErrorReasonsE(root string) ([]*ErrorReasonDefinition, error) {
+	apiScan, err := scanApiFiles(root, scanErrorsFiles|scanEnumConsts)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return apiScan.listErrorReasons()
+}
+
+// ListProtoFilesE lists the parsed .proto files in the specified root path, returning errors
+//
+// ListProtoFilesE 列出指定根目录下解析后的 .proto 文件，返回错误
+func ListProtoFilesE(root string) ([]*ProtoFile, error) {
+	apiScan, err := scanApiFiles(root, scanProtoFiles)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return apiScan.listProtoFiles()
+}
+
+// CheckProtoDriftE compares the .proto files in the specified root path with their generated Go code, returning errors
+//
+// CheckProtoDriftE 比较指定根目录下的 .proto 文件与其生成的 Go 代码，返回错误
+func CheckProtoDriftE(root string) ([]*ProtoDrift, error) {
+	apiScan, err := scanApiFiles(root, scanProtoFiles|scanGrpcFiles|scanHttpFiles)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return apiScan.checkProtoDrifts(), nil
+}
+
+// ListGeneratedFilesE lists the headers of the generated .pb.go files in the specified root path, returning errors
+//
+// ListGeneratedFilesE 列出指定根目录下生成的 .pb.go 文件头部信息，返回错误
+func ListGeneratedFilesE(root string) ([]*GeneratedFileInfo, error) {
+	apiScan, err := scanApiFiles(root, scanHeaders)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return apiScan.listGeneratedFiles(), nil
+}
+
+// CheckGeneratedFilesE reports mixed generator versions and missing source protos in the specified root path, returning errors
+//
+// CheckGeneratedFilesE 报告指定根目录下不一致的生成器版本和缺失的源 proto，返回错误
+func CheckGeneratedFilesE(root string) ([]*GeneratedIssue, error) {
+	apiScan, err := scanApiFiles(root, scanHeaders)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return apiScan.checkGeneratedFiles(), nil
+}
+
+// HasGrpcClientsE checks if gRPC clients exist in the specified root path, returning errors
+//
+// HasGrpcClientsE 检查指定根目录下是否存在 gRPC 客户端，返回错误
+func HasGrpcClientsE(root string) (bool, error) {
+	definitions, err := ListGrpcClientsE(root)
+	if err != nil {
+		return false, erero.Wro(err)
+	}
+	return len(definitions) > 0, nil
+}
+
+// HasGrpcServersE checks if gRPC servers exist in the specified root path, returning errors
+//
+// HasGrpcServersE 检查指定根目录下是否存在 gRPC 服务器，返回错误
+func HasGrpcServersE(root string) (bool, error) {
+	definitions, err := ListGrpcServersE(root)
+	if err != nil {
+		return false, erero.Wro(err)
+	}
+	return len(definitions) > 0, nil
+}
+
+// CountGrpcServicesE returns the count of gRPC services in the specified root path, returning errors
+//
+// CountGrpcServicesE 返回指定根目录下 gRPC 服务的总数，返回错误
+func CountGrpcServicesE(root string) (int, error) {
+	definitions, err := ListGrpcServicesE(root)
+	if err != nil {
+		return 0, erero.Wro(err)
+	}
+	return len(definitions), nil
+}
+
+// ListServiceImplementationsE maps the gRPC services of the project to the structs in internal/service, returning errors
+//
+// ListServiceImplementationsE 将项目中的 gRPC 服务映射到 internal/service 中的结构体，返回错误
+func ListServiceImplementationsE(projectRoot string) ([]*ServiceImplementation, error) {
+	apiRoot, err := projectApiRoot(projectRoot)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	services, err := ListGrpcServicesE(apiRoot)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return analyzeServiceImplementations(projectRoot, filepath.Join(projectRoot, "internal", "service"), services)
+}
+
+// ListServiceCoverageE reports the RPCs of each service implementation falling back to the Unimplemented stub, returning errors
+//
+// ListServiceCoverageE 报告每个服务实现中回退到 Unimplemented 存根的 RPC，返回错误
+func ListServiceCoverageE(projectRoot string) ([]*ServiceCoverage, error) {
+	apiRoot, err := projectApiRoot(projectRoot)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	services, err := ListGrpcServicesE(apiRoot)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	implementations, err := analyzeServiceImplementations(projectRoot, filepath.Join(projectRoot, "internal", "service"), services)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return newServiceCoverages(services, implementations), nil
+}
+
+// ListServiceExposuresE reports whether each gRPC service is registered in internal/server, returning errors
+//
+// ListServiceExposuresE 报告每个 gRPC 服务是否在 internal/server 中注册，返回错误
+func ListServiceExposuresE(projectRoot string) ([]*ServiceExposure, error) {
+	apiRoot, err := projectApiRoot(projectRoot)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	apiScan, err := scanApiFiles(apiRoot, scanGrpcFiles|scanHttpFiles)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	services := apiScan.listServices()
+	registrations, err := analyzeServiceRegistrations(projectRoot, filepath.Join(projectRoot, "internal", "server"), services, apiScan.httpFiles)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return newServiceExposures(services, registrations), nil
+}
+
+// GetWireGraphE extracts the wire ProviderSets and injectors of the project, returning errors
+//
+// GetWireGraphE 提取项目中的 wire ProviderSet 和注入器，返回错误
+func GetWireGraphE(projectRoot string) (*WireGraph, error) {
+	importPath, err := projectImportPath(projectRoot)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return analyzeWireGraph(projectRoot, importPath)
+}
+
+// GetDependencyGraphE builds the constructor graph of the layers, returning errors
+//
+// GetDependencyGraphE 构建各层的构造函数图，返回错误
+func GetDependencyGraphE(projectRoot string) (*DependencyGraph, error) {
+	importPath, err := projectImportPath(projectRoot)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return analyzeDependencyGraph(projectRoot, importPath)
+}
+
+// ListRepoImplementationsE links the repo interfaces of internal/biz to the structs of internal/data, returning errors
+//
+// ListRepoImplementationsE 将 internal/biz 的仓储接口关联到 internal/data 的结构体，返回错误
+func ListRepoImplementationsE(projectRoot string) ([]*RepoImplementation, error) {
+	importPath, err := projectImportPath(projectRoot)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return analyzeRepoImplementations(projectRoot, importPath)
+}
+
+// GetConfigSchemaE extracts the configuration tree from internal/conf, returning errors
+// Returns nil without error when the project has no internal/conf
+//
+// GetConfigSchemaE 从 internal/conf 提取配置树，返回错误
+// 项目没有 internal/conf 时返回 nil 且没有错误
+func GetConfigSchemaE(projectRoot string) (*ConfigSchema, error) {
+	return analyzeConfigSchema(filepath.Join(projectRoot, "internal", "conf"))
+}
+
+// CheckConfigFilesE checks the YAML files in configs against the configuration schema, returning errors
+//
+// CheckConfigFilesE 根据配置结构检查 configs 中的 YAML 文件，返回错误
+func CheckConfigFilesE(projectRoot string) ([]*ConfigIssue, error) {
+	schema, err := GetConfigSchemaE(projectRoot)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return checkConfigFiles(filepath.Join(projectRoot, "configs"), schema)
+}
+
+// CheckLayeringE checks the imports of the layers against the default Kratos layering policy, returning errors
+//
+// CheckLayeringE 根据默认的 Kratos 分层策略检查各层的导入，返回错误
+func CheckLayeringE(projectRoot string) ([]*LayerViolation, error) {
+	return CheckLayeringWithPolicyE(projectRoot, DefaultLayeringPolicy())
+}
+
+// CheckLayeringWithPolicyE checks the imports of the layers against a custom layering policy, returning errors
+//
+// CheckLayeringWithPolicyE 根据自定义分层策略检查各层的导入，返回错误
+func CheckLayeringWithPolicyE(projectRoot string, policy *LayeringPolicy) ([]*LayerViolation, error) {
+	importPath, err := projectImportPath(projectRoot)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return checkLayering(projectRoot, importPath, policy)
+}
+
+// CheckKratosUpgradeE builds the per-file checklist of usages to revisit before bumping Kratos, returning errors
+//
+// CheckKratosUpgradeE 构建升级 Kratos 前需要重新检查的用法的逐文件清单，返回错误
+func CheckKratosUpgradeE(projectRoot string) (*UpgradeChecklist, error) {
+	moduleInfo, err := GetModuleInfo(projectRoot)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	apiRoot, err := projectApiRoot(projectRoot)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	services, err := ListGrpcServicesE(apiRoot)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	implementations, err := analyzeServiceImplementations(projectRoot, filepath.Join(projectRoot, "internal", "service"), services)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return checkKratosUpgrade(projectRoot, []string{apiRoot}, moduleInfo, implementations)
+}
+
+// CheckWireStalenessE compares wire_gen.go with wire.go and the ProviderSets it references, returning errors
+//
+// CheckWireStalenessE 比较 wire_gen.go 与 wire.go 及其引用的 ProviderSet，返回错误
+func CheckWireStalenessE(projectRoot string) ([]*WireStaleness, error) {
+	importPath, err := projectImportPath(projectRoot)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	wireProject, err := loadWireProject(projectRoot, importPath)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return wireProject.checkStaleness(), nil
+}
+
+// GetStructsMapE gets struct definitions in the specified file and returns them as a map, returning errors
+//
+// GetStructsMapE 获取指定文件中的结构体定义并返回映射表，返回错误
+func GetStructsMapE(path string) (map[string]*StructDefinition, error) {
+	if debugModeOpen {
+		zaplog.SUG.Debugln("parsing Go struct definitions from:", path)
+	}
+
+	// Read the entire source code of the file
+	// 读取文件的完整源代码
+	fileSource, err := os.ReadFile(path)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	// Parse the source code into an AST bundle
+	// 将源代码解析为 AST 包
+	astBundle, err := syntaxgo_ast.NewAstBundleV1(fileSource)
+	if err != nil {
+		return nil, erero.Wrapf(err, "parse %s", path)
+	}
+	astFile, _ := astBundle.GetBundle()
+	structMap := newStructDefinitions(fileSource, astFile)
+
+	if debugModeOpen {
+		zaplog.SUG.Debugln("struct parsing completed, discovered", len(structMap), "definitions")
+	}
+	return structMap, nil
+}
+
+// newStructDefinitions maps the struct types of the parsed file to their definitions by name
+//
+// newStructDefinitions 将已解析文件中的结构体类型按名称映射到其定义
+func newStructDefinitions(fileSource []byte, astFile *ast.File) map[string]*StructDefinition {
+	structMap := map[string]*StructDefinition{}
+	for structName, structType := range syntaxgo_search.MapStructTypesByName(astFile) {
+		// Get the code snippet defining the struct
+		// 获取定义结构体的代码片段
+		structCode := syntaxgo_astnode.GetText(fileSource, structType)
+		if debugModeOpen {
+			zaplog.SUG.Debugln("extracted struct:", structName, "with source:", structCode)
+		}
+		structMap[structName] = &StructDefinition{
+			Name:       structName,
+			Type:       structType,
+			FileSource: fileSource,
+			StructCode: structCode,
+		}
+	}
+	return structMap
+}
+
+// AnalyzeWorkspaceE finds every Kratos app under the root and analyzes each of them, returning errors
+//
+// AnalyzeWorkspaceE 查找根目录下的每个 Kratos 应用并逐个分析，返回错误
+func AnalyzeWorkspaceE(root string) (*WorkspaceReport, error) {
+	return analyzeWorkspace(root)
+}
+
+// AnalyzeProjectE performs comprehensive Kratos project analysis with the default analyzer, returning errors
+//
+// AnalyzeProjectE 使用默认分析器执行 Kratos 项目的全面分析，返回错误
+func AnalyzeProjectE(projectRoot string) (*ProjectReport, error) {
+	return NewAnalyzer().Analyze(context.Background(), projectRoot)
+}
@@ -0,0 +1,82 @@
+package astkratos_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/orzkratos/astkratos"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/runpath"
+)
+
+// TestAnalyzeProjectE tests the error-returning analysis of the demo project
+//
+// TestAnalyzeProjectE 测试演示项目的返回错误的分析
+func TestAnalyzeProjectE(t *testing.T) {
+	report, err := astkratos.AnalyzeProjectE(runpath.PARENT.Join("testdata", "demokratos"))
+	require.NoError(t, err)
+	require.Equal(t, "demokratos", report.ModuleInfo.Module.Path)
+	require.Len(t, report.Services, 3)
+}
+
+// TestAnalyzeProjectE_Failures tests that a missing go.mod or api root comes back as an error naming the path
+//
+// TestAnalyzeProjectE_Failures 测试缺失 go.mod 或 api 根目录时返回指明路径的错误
+func TestAnalyzeProjectE_Failures(t *testing.T) {
+	root := t.TempDir()
+	must.Done(os.WriteFile(filepath.Join(root, "go.mod"), []byte("module shop\n\ngo 1.22\n"), 0644))
+
+	_, err := astkratos.AnalyzeProjectE(root)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), filepath.Join(root, "api"))
+
+	must.Done(os.WriteFile(filepath.Join(root, "go.mod"), []byte("module shop\n\ngo 1.22\n\nrequire (\n"), 0644))
+	_, err = astkratos.GetWireGraphE(root)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), filepath.Join(root, "go.mod"))
+}
+
+// TestListGrpcClientsE_Failures tests that broken generated and proto files are reported with their paths
+//
+// TestListGrpcClientsE_Failures 测试损坏的生成文件和 proto 文件会连同路径一起报告
+func TestListGrpcClientsE_Failures(t *testing.T) {
+	root := t.TempDir()
+	grpcPath := filepath.Join(root, "shop_grpc.pb.go")
+	must.Done(os.WriteFile(grpcPath, []byte("package v1\n\ntype ShopClient interface {\n"), 0644))
+
+	_, err := astkratos.ListGrpcClientsE(root)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), grpcPath)
+
+	must.Done(os.Remove(grpcPath))
+	protoPath := filepath.Join(root, "shop.proto")
+	must.Done(os.WriteFile(protoPath, []byte("syntax = \"proto3\";\n\nservice Shop {\n"), 0644))
+
+	_, err = astkratos.ListProtoFilesE(root)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), protoPath)
+}
+
+// TestGetStructsMapE tests struct extraction and the errors of missing and broken files
+//
+// TestGetStructsMapE 测试结构体提取以及文件缺失和损坏时的错误
+func TestGetStructsMapE(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "shop.go")
+	must.Done(os.WriteFile(path, []byte("package shop\n\ntype Shop struct {\n\tName string\n}\n"), 0644))
+
+	structMap, err := astkratos.GetStructsMapE(path)
+	require.NoError(t, err)
+	require.Contains(t, structMap, "Shop")
+
+	_, err = astkratos.GetStructsMapE(filepath.Join(root, "missing.go"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), filepath.Join(root, "missing.go"))
+
+	must.Done(os.WriteFile(path, []byte("package shop\n\ntype Shop struct {\n"), 0644))
+	_, err = astkratos.GetStructsMapE(path)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), path)
+}
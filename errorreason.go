@@ -0,0 +1,196 @@
+// Package astkratos error reason discovery: Kratos error reasons from generated _errors.pb.go files
+// Reads the IsXxx and ErrorXxx helpers that protoc-gen-go-errors generates from ErrorReason enums
+// Resolves the enum value, HTTP status code and both helper function names of each reason
+// Takes the enum type from the constant declared by protoc-gen-go next to the file, read by the same api scan
+//
+// astkratos 错误原因发现：从生成的 _errors.pb.go 文件中提取 Kratos 错误原因
+// 读取 protoc-gen-go-errors 根据 ErrorReason 枚举生成的 IsXxx 和 ErrorXxx 辅助函数
+// 解析每个错误原因的枚举值、HTTP 状态码和两个辅助函数名称
+// 从 protoc-gen-go 在该文件旁边声明的常量中获取枚举类型，由同一次 api 扫描读取
+package astkratos
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/yyle88/erero"
+)
+
+// ErrorReasonDefinition represents an error reason with its generated helper functions
+//
+// ErrorReasonDefinition 表示错误原因及其生成的辅助函数
+type ErrorReasonDefinition struct {
+	Enum      string // Enum type name, such as ErrorReason // 枚举类型名称，例如 ErrorReason
+	Value     string // Enum value name, such as USER_NOT_FOUND // 枚举值名称，例如 USER_NOT_FOUND
+	Code      int    // HTTP status code // HTTP 状态码
+	IsFunc    string // Checking function name, such as IsUserNotFound // 判断函数名称，例如 IsUserNotFound
+	ErrorFunc string // Constructor function name, such as ErrorUserNotFound // 构造函数名称，例如 ErrorUserNotFound
+	Package   string // Package name where the helpers are defined // 辅助函数定义所在的包名
+	SrcPath   string // Source file path where the helpers are defined // 辅助函数定义所在的源文件路径
+}
+
+// errorsPbGoFile holds the error reasons extracted from one _errors.pb.go file
+//
+// errorsPbGoFile 保存从单个 _errors.pb.go 文件提取的错误原因
+type errorsPbGoFile struct {
+	srcPath    string                   // Absolute path of the file // 文件的绝对路径
+	reasons    []*ErrorReasonDefinition // Error reasons in declaration order // 按声明顺序排列的错误原因
+	enumValues []string                 // Go names of the enum values of the reasons, such as ErrorReason_USER_NOT_FOUND // 各错误原因枚举值的 Go 名称，例如 ErrorReason_USER_NOT_FOUND
+}
+
+// analyzeErrorsPbGoFile parses the _errors.pb.go file and pairs the IsXxx and ErrorXxx helpers by enum value
+// The enum name is split at the first underscore until resolveEnumTypes sees the enum constants
+//
+// analyzeErrorsPbGoFile 解析 _errors.pb.go 文件并按枚举值配对 IsXxx 和 ErrorXxx 辅助函数
+// 在 resolveEnumTypes 看到枚举常量之前，枚举名称在第一个下划线处拆分
+func analyzeErrorsPbGoFile(path string) (*errorsPbGoFile, error) {
+	goFile, err := parseGoFile(path)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	astFile := goFile.astFile
+
+	result := &errorsPbGoFile{srcPath: goFile.srcPath, reasons: make([]*ErrorReasonDefinition, 0)}
+	reasonMap := map[string]*ErrorReasonDefinition{}
+	getReason := func(enumValue string) *ErrorReasonDefinition {
+		if reason, ok := reasonMap[enumValue]; ok {
+			return reason
+		}
+		enumName, valueName, _ := strings.Cut(enumValue, "_")
+		reason := &ErrorReasonDefinition{
+			Enum:    enumName,
+			Value:   valueName,
+			Package: astFile.Name.Name,
+			SrcPath: goFile.srcPath,
+		}
+		reasonMap[enumValue] = reason
+		result.reasons = append(result.reasons, reason)
+		result.enumValues = append(result.enumValues, enumValue)
+		return reason
+	}
+
+	for _, decl := range astFile.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Recv != nil || funcDecl.Body == nil {
+			continue
+		}
+		name := funcDecl.Name.Name
+		switch {
+		case strings.HasPrefix(name, "Is"):
+			// return e.Reason == ErrorReason_USER_NOT_FOUND.String() && e.Code == 404
+			var enumValue string
+			var code int
+			ast.Inspect(funcDecl.Body, func(node ast.Node) bool {
+				binaryExpr, ok := node.(*ast.BinaryExpr)
+				if !ok || binaryExpr.Op != token.EQL {
+					return true
+				}
+				switch selectorName(binaryExpr.X) {
+				case "Reason":
+					enumValue = enumStringReceiver(binaryExpr.Y)
+				case "Code":
+					code = intLitValue(binaryExpr.Y)
+				}
+				return true
+			})
+			if enumValue != "" {
+				reason := getReason(enumValue)
+				reason.IsFunc = name
+				reason.Code = code
+			}
+		case strings.HasPrefix(name, "Error"):
+			// return errors.New(404, ErrorReason_USER_NOT_FOUND.String(), fmt.Sprintf(format, args...))
+			ast.Inspect(funcDecl.Body, func(node ast.Node) bool {
+				callExpr, ok := node.(*ast.CallExpr)
+				if !ok || selectorName(callExpr.Fun) != "New" || len(callExpr.Args) < 2 {
+					return true
+				}
+				if enumValue := enumStringReceiver(callExpr.Args[1]); enumValue != "" {
+					reason := getReason(enumValue)
+					reason.ErrorFunc = name
+					reason.Code = intLitValue(callExpr.Args[0])
+				}
+				return false
+			})
+		}
+	}
+	return result, nil
+}
+
+// resolveEnumTypes takes the enum names of the reasons from the typed constants of the package
+// The Go name of an enum value is Enum_VALUE, and the enum name may hold underscores itself
+//
+// resolveEnumTypes 从包中带类型的常量获取各错误原因的枚举名称
+// 枚举值的 Go 名称形如 Enum_VALUE，枚举名称本身也可能包含下划线
+func (f *errorsPbGoFile) resolveEnumTypes(enumTypes map[string]string) {
+	for idx, reason := range f.reasons {
+		enumValue := f.enumValues[idx]
+		if enumType, ok := enumTypes[enumValue]; ok && strings.HasPrefix(enumValue, enumType+"_") {
+			reason.Enum, reason.Value = enumType, strings.TrimPrefix(enumValue, enumType+"_")
+		}
+	}
+}
+
+// analyzeEnumPbGoFile maps the typed constants of a .pb.go file to their type names
+// The constant ErrorReason_USER_NOT_FOUND ErrorReason = 1 maps ErrorReason_USER_NOT_FOUND to ErrorReason
+//
+// analyzeEnumPbGoFile 将 .pb.go 文件中带类型的常量映射到其类型名称
+// 常量 ErrorReason_USER_NOT_FOUND ErrorReason = 1 将 ErrorReason_USER_NOT_FOUND 映射到 ErrorReason
+func analyzeEnumPbGoFile(path string) (map[string]string, error) {
+	goFile, err := parseGoFile(path)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	enumTypes := map[string]string{}
+	for _, decl := range goFile.astFile.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			typeIdent, ok := valueSpec.Type.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			for _, name := range valueSpec.Names {
+				enumTypes[name.Name] = typeIdent.Name
+			}
+		}
+	}
+	return enumTypes, nil
+}
+
+// enumStringReceiver reads ErrorReason_USER_NOT_FOUND from the ErrorReason_USER_NOT_FOUND.String() call
+//
+// enumStringReceiver 从 ErrorReason_USER_NOT_FOUND.String() 调用中读取 ErrorReason_USER_NOT_FOUND
+func enumStringReceiver(expr ast.Expr) string {
+	callExpr, ok := expr.(*ast.CallExpr)
+	if !ok || selectorName(callExpr.Fun) != "String" {
+		return ""
+	}
+	if ident, ok := callExpr.Fun.(*ast.SelectorExpr).X.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// intLitValue returns the value of an integer literal, zero when not an integer literal
+//
+// intLitValue 返回整数字面量的值，非整数字面量时返回零
+func intLitValue(expr ast.Expr) int {
+	basicLit, ok := expr.(*ast.BasicLit)
+	if !ok || basicLit.Kind != token.INT {
+		return 0
+	}
+	value, err := strconv.Atoi(basicLit.Value)
+	if err != nil {
+		return 0
+	}
+	return value
+}
@@ -0,0 +1,123 @@
+package astkratos_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/orzkratos/astkratos"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/neatjson/neatjsons"
+	"github.com/yyle88/rese"
+	"github.com/yyle88/runpath"
+)
+
+// TestListErrorReasons tests error reason discovery from IsXxx and ErrorXxx helpers
+//
+// TestListErrorReasons 测试从 IsXxx 和 ErrorXxx 辅助函数中发现错误原因
+func TestListErrorReasons(t *testing.T) {
+	reasons := astkratos.ListErrorReasons(demoApiRoot)
+	t.Log(neatjsons.S(reasons))
+
+	srcPath := runpath.PARENT.Join("testdata", "demokratos", "api", "helloworld", "v1", "error_reason_errors.pb.go")
+	require.Equal(t, []*astkratos.ErrorReasonDefinition{
+		{
+			Enum:      "ErrorReason",
+			Value:     "GREETER_UNSPECIFIED",
+			Code:      500,
+			IsFunc:    "IsGreeterUnspecified",
+			ErrorFunc: "ErrorGreeterUnspecified",
+			Package:   "v1",
+			SrcPath:   srcPath,
+		},
+		{
+			Enum:      "ErrorReason",
+			Value:     "USER_NOT_FOUND",
+			Code:      404,
+			IsFunc:    "IsUserNotFound",
+			ErrorFunc: "ErrorUserNotFound",
+			Package:   "v1",
+			SrcPath:   srcPath,
+		},
+	}, reasons)
+}
+
+// TestListErrorReasons_UnderscoreEnum tests that an enum name holding an underscore is taken from its type declaration
+//
+// TestListErrorReasons_UnderscoreEnum 测试包含下划线的枚举名称取自其类型声明
+func TestListErrorReasons_UnderscoreEnum(t *testing.T) {
+	root := t.TempDir()
+	must.Done(os.WriteFile(filepath.Join(root, "reason.pb.go"), []byte(`// Code generated by protoc-gen-go. DO NOT EDIT.
+
+package v1
+
+type Shop_Reason int32
+
+const (
+	Shop_Reason_OUT_OF_STOCK Shop_Reason = 0
+)
+
+func (x Shop_Reason) String() string {
+	return "OUT_OF_STOCK"
+}
+`), 0644))
+	srcPath := filepath.Join(root, "reason_errors.pb.go")
+	must.Done(os.WriteFile(srcPath, []byte(`// Code generated by protoc-gen-go-errors. DO NOT EDIT.
+
+package v1
+
+import (
+	fmt "fmt"
+	errors "github.com/go-kratos/kratos/v2/errors"
+)
+
+func IsOutOfStock(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == Shop_Reason_OUT_OF_STOCK.String() && e.Code == 409
+}
+
+func ErrorOutOfStock(format string, args ...interface{}) *errors.Error {
+	return errors.New(409, Shop_Reason_OUT_OF_STOCK.String(), fmt.Sprintf(format, args...))
+}
+`), 0644))
+
+	reasons := astkratos.ListErrorReasons(root)
+	t.Log(neatjsons.S(reasons))
+	require.Equal(t, []*astkratos.ErrorReasonDefinition{
+		{
+			Enum:      "Shop_Reason",
+			Value:     "OUT_OF_STOCK",
+			Code:      409,
+			IsFunc:    "IsOutOfStock",
+			ErrorFunc: "ErrorOutOfStock",
+			Package:   "v1",
+			SrcPath:   srcPath,
+		},
+	}, reasons)
+}
+
+// TestListErrorReasons_UnreadEnum tests that a broken .pb.go file next to the error reasons fails the listing
+//
+// TestListErrorReasons_UnreadEnum 测试错误原因旁边损坏的 .pb.go 文件会使列表失败
+func TestListErrorReasons_UnreadEnum(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"shop", "other"} {
+		must.Done(os.MkdirAll(filepath.Join(root, dir), 0755))
+	}
+	must.Done(os.WriteFile(filepath.Join(root, "shop", "error_reason_errors.pb.go"), rese.V1(os.ReadFile(filepath.Join(demoApiRoot, "helloworld", "v1", "error_reason_errors.pb.go"))), 0644))
+	must.Done(os.WriteFile(filepath.Join(root, "other", "other.pb.go"), []byte("package other\n\nconst (\n"), 0644))
+
+	reasons, err := astkratos.ListErrorReasonsE(root)
+	require.NoError(t, err)
+	require.Len(t, reasons, 2)
+	require.Equal(t, "ErrorReason", reasons[0].Enum)
+
+	enumPath := filepath.Join(root, "shop", "error_reason.pb.go")
+	must.Done(os.WriteFile(enumPath, []byte("package v1\n\nconst (\n"), 0644))
+	_, err = astkratos.ListErrorReasonsE(root)
+	require.ErrorContains(t, err, enumPath)
+}
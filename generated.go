@@ -0,0 +1,266 @@
+// Package astkratos generated files: Generator versions recorded in the headers of .pb.go files
+// Reads the "Code generated by", "versions:" and "source:" lines that protoc plugins write
+// Reports generators used at mixed versions across the api tree, pointing at the odd files
+// Reports generated files whose source .proto no longer exists next to them or under the api root
+//
+// astkratos 生成文件：.pb.go 文件头部记录的生成器版本
+// 读取 protoc 插件写入的 "Code generated by"、"versions:" 和 "source:" 行
+// 报告在 api 目录树中以不同版本使用的生成器，并指向版本不一致的文件
+// 报告源 .proto 已不在其旁边或 api 根目录下的生成文件
+package astkratos
+
+import (
+	"bufio"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/yyle88/erero"
+	"github.com/yyle88/rese"
+	"golang.org/x/mod/semver"
+)
+
+// generatedByPattern matches the standard generated code comment and captures the generator
+//
+// generatedByPattern 匹配标准的生成代码注释并捕获生成器名称
+var generatedByPattern = regexp.MustCompile(`^// Code generated by (\S+?)\.? DO NOT EDIT\.$`)
+
+// GeneratorVersion represents one entry of the versions block, such as protoc-gen-go-grpc v1.5.1
+//
+// GeneratorVersion 表示 versions 块中的一个条目，例如 protoc-gen-go-grpc v1.5.1
+type GeneratorVersion struct {
+	Name    string // Generator name, such as protoc // 生成器名称，例如 protoc
+	Version string // Version as written, such as v5.29.3 or (unknown) // 书写的版本，例如 v5.29.3 或 (unknown)
+	Line    int    // Line in the generated file // 在生成文件中的行号
+}
+
+// GeneratedFileInfo represents the header of a generated .pb.go file
+//
+// GeneratedFileInfo 表示生成的 .pb.go 文件的头部信息
+type GeneratedFileInfo struct {
+	Path       string              // Absolute path of the file // 文件的绝对路径
+	Generator  string              // Generator from the Code generated comment, such as protoc-gen-go-grpc // 来自 Code generated 注释的生成器，例如 protoc-gen-go-grpc
+	Versions   []*GeneratorVersion // Entries of the versions block, empty when absent // versions 块中的条目，不存在时为空
+	Source     string              // Source .proto as written, such as helloworld/v1/greeter.proto // 书写的源 .proto，例如 helloworld/v1/greeter.proto
+	SourceLine int                 // Line of the source comment, 0 when absent // source 注释的行号，不存在时为 0
+	SourcePath string              // Resolved source .proto path, blank when not found // 解析后的源 .proto 路径，未找到时为空
+}
+
+// GetVersion returns the version of the named generator, blank when the header does not list it
+//
+// GetVersion 返回指定生成器的版本，头部未列出时返回空
+func (g *GeneratedFileInfo) GetVersion(name string) string {
+	for _, item := range g.Versions {
+		if item.Name == name {
+			return item.Version
+		}
+	}
+	return ""
+}
+
+// GeneratedIssueKind represents the kind of inconsistency found in generated files
+//
+// GeneratedIssueKind 表示在生成文件中发现的不一致类型
+type GeneratedIssueKind string
+
+const (
+	GeneratedIssueMixedVersion  GeneratedIssueKind = "mixed_version"  // Generator version differs from the one most files use // 生成器版本与多数文件使用的版本不同
+	GeneratedIssueMissingSource GeneratedIssueKind = "missing_source" // Source .proto no longer exists // 源 .proto 已不存在
+	GeneratedIssueUnreadHeader  GeneratedIssueKind = "unread_header"  // Header could not be read, so the file was not checked // 头部无法读取，因此未检查该文件
+)
+
+// GeneratedIssue represents one inconsistency of a generated file
+//
+// GeneratedIssue 表示生成文件的一处不一致
+type GeneratedIssue struct {
+	Kind      GeneratedIssueKind // Issue kind // 问题类型
+	Generator string             // Generator concerned, such as protoc // 相关的生成器，例如 protoc
+	Detail    string             // Human readable description // 可读的描述
+	Position  Position           // Position in the generated file // 在生成文件中的位置
+}
+
+// parseGeneratedFileHeader reads the comment lines above the package clause of the generated file
+// Returns nil when the file lacks the Code generated comment
+//
+// parseGeneratedFileHeader 读取生成文件中 package 子句之上的注释行
+// 文件缺少 Code generated 注释时返回 nil
+func parseGeneratedFileHeader(path string, apiRoot string) (*GeneratedFileInfo, error) {
+	srcPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	file, err := os.Open(srcPath)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	defer rese.F0(file.Close)
+
+	info := &GeneratedFileInfo{Path: srcPath, Versions: make([]*GeneratorVersion, 0)}
+	inVersions := false
+	scan := bufio.NewScanner(file)
+	for line := 1; scan.Scan(); line++ {
+		text := strings.TrimSpace(scan.Text())
+		if strings.HasPrefix(text, "package ") {
+			break
+		}
+		if matches := generatedByPattern.FindStringSubmatch(text); matches != nil {
+			info.Generator = matches[1]
+			continue
+		}
+		content, ok := strings.CutPrefix(text, "//")
+		if !ok {
+			inVersions = false
+			continue
+		}
+		content = strings.TrimSpace(content)
+		switch {
+		case content == "versions:":
+			inVersions = true
+		case strings.HasPrefix(content, "source:"):
+			inVersions = false
+			info.Source = strings.TrimSpace(strings.TrimPrefix(content, "source:"))
+			info.SourceLine = line
+		case inVersions:
+			// Entries are "- protoc-gen-go-grpc v1.5.1" in plugin headers and "protoc-gen-go v1.36.5" in protoc-gen-go
+			// 插件头部中的条目为 "- protoc-gen-go-grpc v1.5.1"，protoc-gen-go 中为 "protoc-gen-go v1.36.5"
+			if parts := strings.Fields(strings.TrimPrefix(content, "- ")); len(parts) == 2 {
+				info.Versions = append(info.Versions, &GeneratorVersion{Name: parts[0], Version: parts[1], Line: line})
+			} else {
+				inVersions = false
+			}
+		}
+	}
+	if err := scan.Err(); err != nil {
+		return nil, erero.Wrapf(err, "read %s", srcPath)
+	}
+	if info.Generator == "" {
+		return nil, nil
+	}
+	if info.Source != "" {
+		info.SourcePath = resolveGeneratedSource(srcPath, info.Source, apiRoot)
+	}
+	return info, nil
+}
+
+// resolveGeneratedSource finds the source .proto of the generated file
+// Tries the basename next to the file, as paths=source_relative puts it there,
+// then the source path joined with each directory from the file up to the api root
+//
+// resolveGeneratedSource 查找生成文件的源 .proto
+// 先尝试文件旁边的同名文件（paths=source_relative 会将生成文件放在那里），
+// 再尝试将源路径与从文件所在目录到 api 根目录的每个目录拼接
+func resolveGeneratedSource(path string, source string, apiRoot string) string {
+	candidates := []string{filepath.Join(filepath.Dir(path), filepath.Base(source))}
+	if apiRoot, err := filepath.Abs(apiRoot); err == nil {
+		for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+			if relDir, err := filepath.Rel(apiRoot, dir); err != nil || strings.HasPrefix(relDir, "..") {
+				break
+			}
+			candidates = append(candidates, filepath.Join(dir, filepath.FromSlash(source)))
+			if dir == apiRoot {
+				break
+			}
+		}
+	}
+	for _, candidate := range candidates {
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// listGeneratedFiles returns the headers of the scanned generated files
+//
+// listGeneratedFiles 返回已扫描生成文件的头部信息
+func (r *apiScanResult) listGeneratedFiles() []*GeneratedFileInfo {
+	generatedFiles := make([]*GeneratedFileInfo, 0)
+	generatedFiles = append(generatedFiles, r.generatedFiles...)
+	return generatedFiles
+}
+
+// checkGeneratedFiles reports mixed generator versions and generated files missing their source .proto
+// The version most files use counts as the expected one, ties going to the newest version
+// The files whose header could not be read come first
+//
+// checkGeneratedFiles 报告不一致的生成器版本以及缺少源 .proto 的生成文件
+// 多数文件使用的版本视为期望版本，数量相同时取最新版本
+// 无法读取头部的文件排在最前面
+func (r *apiScanResult) checkGeneratedFiles() []*GeneratedIssue {
+	issues := make([]*GeneratedIssue, 0)
+	for _, failure := range r.headerFailures {
+		issues = append(issues, &GeneratedIssue{
+			Kind:     GeneratedIssueUnreadHeader,
+			Detail:   failure.err.Error(),
+			Position: Position{Path: failure.path},
+		})
+	}
+
+	var names []string
+	counts := map[string]map[string]int{}
+	for _, generatedFile := range r.generatedFiles {
+		for _, item := range generatedFile.Versions {
+			if counts[item.Name] == nil {
+				counts[item.Name] = map[string]int{}
+				names = append(names, item.Name)
+			}
+			counts[item.Name][item.Version]++
+		}
+	}
+	expected := map[string]string{}
+	for _, name := range names {
+		if len(counts[name]) < 2 {
+			continue
+		}
+		versions := slices.Sorted(maps.Keys(counts[name]))
+		best := versions[0]
+		for _, version := range versions[1:] {
+			if count := counts[name][version]; count > counts[name][best] || (count == counts[name][best] && isNewerGeneratorVersion(version, best)) {
+				best = version
+			}
+		}
+		expected[name] = best
+	}
+
+	for _, generatedFile := range r.generatedFiles {
+		for _, item := range generatedFile.Versions {
+			version, ok := expected[item.Name]
+			if !ok || item.Version == version {
+				continue
+			}
+			issues = append(issues, &GeneratedIssue{
+				Kind:      GeneratedIssueMixedVersion,
+				Generator: item.Name,
+				Detail:    fmt.Sprintf("%s %s differs from %s used by %d files", item.Name, item.Version, version, counts[item.Name][version]),
+				Position:  Position{Path: generatedFile.Path, Line: item.Line},
+			})
+		}
+		if generatedFile.Source != "" && generatedFile.SourcePath == "" {
+			issues = append(issues, &GeneratedIssue{
+				Kind:      GeneratedIssueMissingSource,
+				Generator: generatedFile.Generator,
+				Detail:    "source " + generatedFile.Source + " of " + filepath.Base(generatedFile.Path) + " not found",
+				Position:  Position{Path: generatedFile.Path, Line: generatedFile.SourceLine},
+			})
+		}
+	}
+	return issues
+}
+
+// isNewerGeneratorVersion reports whether the version wins a tie against the current best
+// Only semantic versions are compared, one beats a non-semver value such as (unknown),
+// and two non-semver values keep the current best, which is the first in sorted order
+//
+// isNewerGeneratorVersion 判断该版本在数量相同时是否胜过当前最佳版本
+// 只比较语义化版本，语义化版本胜过 (unknown) 等非语义化的值，
+// 两个非语义化的值保留当前最佳版本，即排序后的第一个
+func isNewerGeneratorVersion(version string, best string) bool {
+	if !semver.IsValid(version) {
+		return false
+	}
+	return !semver.IsValid(best) || semver.Compare(version, best) > 0
+}
@@ -0,0 +1,151 @@
+package astkratos_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/orzkratos/astkratos"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/neatjson/neatjsons"
+	"github.com/yyle88/rese"
+)
+
+// TestListGeneratedFiles tests header extraction from the generated files of the demo api tree
+//
+// TestListGeneratedFiles 测试从演示 api 目录树的生成文件中提取头部信息
+func TestListGeneratedFiles(t *testing.T) {
+	generatedFiles := astkratos.ListGeneratedFiles(demoApiRoot)
+	t.Log(neatjsons.S(generatedFiles))
+	require.Len(t, generatedFiles, 5)
+
+	generatedMap := map[string]*astkratos.GeneratedFileInfo{}
+	for _, generatedFile := range generatedFiles {
+		generatedMap[filepath.Base(generatedFile.Path)] = generatedFile
+	}
+
+	greeterGrpc := generatedMap["greeter_grpc.pb.go"]
+	require.NotNil(t, greeterGrpc)
+	require.Equal(t, "protoc-gen-go-grpc", greeterGrpc.Generator)
+	require.Equal(t, "v1.5.1", greeterGrpc.GetVersion("protoc-gen-go-grpc"))
+	require.Equal(t, "v5.29.3", greeterGrpc.GetVersion("protoc"))
+	require.Equal(t, 3, greeterGrpc.Versions[0].Line)
+	require.Equal(t, "helloworld/v1/greeter.proto", greeterGrpc.Source)
+	require.Equal(t, 5, greeterGrpc.SourceLine)
+	require.Equal(t, filepath.Join(demoApiRoot, "helloworld", "v1", "greeter.proto"), greeterGrpc.SourcePath)
+
+	errorsFile := generatedMap["error_reason_errors.pb.go"]
+	require.NotNil(t, errorsFile)
+	require.Equal(t, "protoc-gen-go-errors", errorsFile.Generator)
+	require.Empty(t, errorsFile.Versions)
+	require.Empty(t, errorsFile.Source)
+}
+
+// TestCheckGeneratedFiles tests that the legacy stub generated with older tools is reported
+//
+// TestCheckGeneratedFiles 测试使用旧版工具生成的 legacy 存根会被报告
+func TestCheckGeneratedFiles(t *testing.T) {
+	issues := astkratos.CheckGeneratedFiles(demoApiRoot)
+	t.Log(neatjsons.S(issues))
+	require.Len(t, issues, 2)
+
+	legacyPath := filepath.Join(demoApiRoot, "legacy", "v1", "legacy_grpc.pb.go")
+	require.Equal(t, astkratos.GeneratedIssueMixedVersion, issues[0].Kind)
+	require.Equal(t, "protoc-gen-go-grpc", issues[0].Generator)
+	require.Equal(t, astkratos.Position{Path: legacyPath, Line: 3}, issues[0].Position)
+	require.Equal(t, "protoc-gen-go-grpc v1.1.0 differs from v1.5.1 used by 2 files", issues[0].Detail)
+	require.Equal(t, "protoc", issues[1].Generator)
+	require.Equal(t, astkratos.Position{Path: legacyPath, Line: 4}, issues[1].Position)
+}
+
+// TestCheckGeneratedFiles_MissingSource tests generated files whose source proto was deleted
+//
+// TestCheckGeneratedFiles_MissingSource 测试源 proto 已被删除的生成文件
+func TestCheckGeneratedFiles_MissingSource(t *testing.T) {
+	root := t.TempDir()
+	pkgRoot := filepath.Join(root, "shop", "v1")
+	must.Done(os.MkdirAll(pkgRoot, 0755))
+	must.Done(os.WriteFile(filepath.Join(pkgRoot, "shop.proto"), []byte("syntax = \"proto3\";\n\npackage shop.v1;\n"), 0644))
+	must.Done(os.WriteFile(filepath.Join(pkgRoot, "shop.pb.go"), []byte(`// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.5
+// 	protoc        v5.29.3
+// source: shop/v1/shop.proto
+
+package v1
+`), 0644))
+	must.Done(os.WriteFile(filepath.Join(pkgRoot, "order.pb.go"), []byte(`// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.5
+// 	protoc        v5.29.3
+// source: shop/v1/order.proto
+
+package v1
+`), 0644))
+
+	generatedFiles := astkratos.ListGeneratedFiles(root)
+	t.Log(neatjsons.S(generatedFiles))
+	require.Len(t, generatedFiles, 2)
+	for _, generatedFile := range generatedFiles {
+		require.Equal(t, "protoc-gen-go", generatedFile.Generator)
+		require.Equal(t, "v1.36.5", generatedFile.GetVersion("protoc-gen-go"))
+	}
+
+	issues := astkratos.CheckGeneratedFiles(root)
+	t.Log(neatjsons.S(issues))
+	require.Len(t, issues, 1)
+	require.Equal(t, astkratos.GeneratedIssueMissingSource, issues[0].Kind)
+	require.Equal(t, astkratos.Position{Path: filepath.Join(pkgRoot, "order.pb.go"), Line: 5}, issues[0].Position)
+}
+
+// TestCheckGeneratedFiles_UnknownVersion tests that ties leave non-semver versions such as (unknown) out of the comparison
+//
+// TestCheckGeneratedFiles_UnknownVersion 测试数量相同时 (unknown) 等非语义化版本不参与比较
+func TestCheckGeneratedFiles_UnknownVersion(t *testing.T) {
+	root := t.TempDir()
+	for name, versions := range map[string][2]string{
+		"a": {"(devel)", "(unknown)"},
+		"b": {"(devel)", "(unknown)"},
+		"c": {"(unknown)", "v5.29.3"},
+		"d": {"(unknown)", "v5.29.3"},
+	} {
+		must.Done(os.WriteFile(filepath.Join(root, name+".pb.go"), []byte(`// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go `+versions[0]+`
+// 	protoc        `+versions[1]+`
+
+package v1
+`), 0644))
+	}
+
+	issues := astkratos.CheckGeneratedFiles(root)
+	t.Log(neatjsons.S(issues))
+	require.Len(t, issues, 4)
+	require.Equal(t, "protoc (unknown) differs from v5.29.3 used by 2 files", issues[0].Detail)
+	require.Equal(t, astkratos.Position{Path: filepath.Join(root, "a.pb.go"), Line: 4}, issues[0].Position)
+	require.Equal(t, astkratos.Position{Path: filepath.Join(root, "b.pb.go"), Line: 4}, issues[1].Position)
+	require.Equal(t, "protoc-gen-go (unknown) differs from (devel) used by 2 files", issues[2].Detail)
+	require.Equal(t, astkratos.Position{Path: filepath.Join(root, "d.pb.go"), Line: 3}, issues[3].Position)
+}
+
+// TestCheckGeneratedFiles_UnreadHeader tests that an unreadable header is an issue and leaves the listings intact
+//
+// TestCheckGeneratedFiles_UnreadHeader 测试无法读取的头部作为问题报告且不影响列表
+func TestCheckGeneratedFiles_UnreadHeader(t *testing.T) {
+	root := t.TempDir()
+	grpcPath := filepath.Join(root, "echo_grpc.pb.go")
+	source := rese.V1(os.ReadFile(filepath.Join(demoApiRoot, "echo", "v1", "echo_grpc.pb.go")))
+	must.Done(os.WriteFile(grpcPath, append([]byte("// "+strings.Repeat("x", 70000)+"\n"), source...), 0644))
+
+	require.Equal(t, []string{"Echo"}, collectNames(astkratos.ListGrpcServices(root)))
+	require.Empty(t, astkratos.ListGeneratedFiles(root))
+
+	issues := astkratos.CheckGeneratedFiles(root)
+	t.Log(neatjsons.S(issues))
+	require.Len(t, issues, 1)
+	require.Equal(t, astkratos.GeneratedIssueUnreadHeader, issues[0].Kind)
+	require.Equal(t, astkratos.Position{Path: grpcPath}, issues[0].Position)
+	require.Contains(t, issues[0].Detail, grpcPath)
+}
@@ -14,6 +14,9 @@ require (
 	github.com/yyle88/syntaxgo v0.0.54
 	github.com/yyle88/tern v0.0.9
 	github.com/yyle88/zaplog v0.0.27
+	go.uber.org/zap v1.27.1
+	golang.org/x/mod v0.30.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -26,8 +29,6 @@ require (
 	github.com/yyle88/printgo v1.0.6 // indirect
 	github.com/yyle88/sure v0.0.42 // indirect
 	go.uber.org/multierr v1.11.0 // indirect
-	go.uber.org/zap v1.27.1 // indirect
 	golang.org/x/exp v0.0.0-20251125195548-87e1e737ad39 // indirect
 	golang.org/x/tools v0.39.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,238 @@
+// Package astkratos Go file parsing: Shared helpers that parse Go source files into ASTs
+// Resolves the absolute path and keeps the file set so that positions can be reported
+// Loads the non-test files of a directory tree grouped by package directory
+//
+// astkratos Go 文件解析：将 Go 源文件解析为 AST 的共享辅助函数
+// 解析绝对路径并保留文件集，以便报告位置信息
+// 按包目录分组加载目录树中的非测试文件
+package astkratos
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/yyle88/erero"
+)
+
+// parsedGoFile holds a parsed Go source file
+//
+// parsedGoFile 保存已解析的 Go 源文件
+type parsedGoFile struct {
+	srcPath string         // Absolute source file path // 源文件绝对路径
+	source  []byte         // Source code of the file // 文件源码
+	fset    *token.FileSet // File set used in parsing // 解析使用的文件集
+	astFile *ast.File      // Parsed AST with comments // 带注释的 AST
+}
+
+// parseGoFile reads and parses the Go source file at the path
+//
+// parseGoFile 读取并解析指定路径的 Go 源文件
+func parseGoFile(path string) (*parsedGoFile, error) {
+	srcPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	source, err := os.ReadFile(srcPath)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	fset := token.NewFileSet()
+	astFile, err := parser.ParseFile(fset, srcPath, source, parser.ParseComments)
+	if err != nil {
+		return nil, erero.Wrapf(err, "parse %s", srcPath)
+	}
+	return &parsedGoFile{
+		srcPath: srcPath,
+		source:  source,
+		fset:    fset,
+		astFile: astFile,
+	}, nil
+}
+
+// goPackage holds the parsed non-test Go files of one directory
+//
+// goPackage 保存单个目录中已解析的非测试 Go 文件
+type goPackage struct {
+	dir   string          // Absolute directory path // 目录绝对路径
+	name  string          // Package name // 包名
+	files []*parsedGoFile // Parsed files in walk order // 按遍历顺序解析的文件
+}
+
+// loadGoPackages parses the non-test Go files under the root and groups them by directory
+// Returns an empty list when the root does not exist, and the first parse failure in walk order
+//
+// loadGoPackages 解析根目录下的非测试 Go 文件并按目录分组
+// 根目录不存在时返回空列表，并返回按遍历顺序的第一个解析失败
+func loadGoPackages(root string) ([]*goPackage, error) {
+	packages, failures, err := walkGoPackages(root)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	if len(failures) > 0 {
+		return nil, erero.Wro(failures[0].err)
+	}
+	return packages, nil
+}
+
+// walkGoPackages parses the non-test Go files under the root and groups them by directory
+// Skips vendored, test data and hidden directories, which hold no code of the project
+// Keeps the files failing to parse as failures instead of aborting the walk
+//
+// walkGoPackages 解析根目录下的非测试 Go 文件并按目录分组
+// 跳过 vendor、测试数据和隐藏目录，这些目录中没有项目自身的代码
+// 将解析失败的文件保存为失败记录，而不是中止遍历
+func walkGoPackages(root string) ([]*goPackage, []*scanFailure, error) {
+	packages := make([]*goPackage, 0)
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return packages, nil, nil
+	}
+	var failures []*scanFailure
+	packageMap := map[string]*goPackage{}
+	if err := filepath.WalkDir(root, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return erero.Wro(err)
+		}
+		if entry.IsDir() {
+			if path != root && isSkippedSourceDir(entry.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		goFile, err := parseGoFile(path)
+		if err != nil {
+			failures = append(failures, &scanFailure{path: path, err: err})
+			return nil
+		}
+		dir := filepath.Dir(goFile.srcPath)
+		pkg, ok := packageMap[dir]
+		if !ok {
+			pkg = &goPackage{dir: dir, name: goFile.astFile.Name.Name}
+			packageMap[dir] = pkg
+			packages = append(packages, pkg)
+		}
+		pkg.files = append(pkg.files, goFile)
+		return nil
+	}); err != nil {
+		return nil, nil, erero.Wro(err)
+	}
+	return packages, failures, nil
+}
+
+// isSkippedSourceDir reports whether the directory is left out of the Go source walk
+// The go tool ignores testdata and hidden directories, and vendored code is not the project's own
+//
+// isSkippedSourceDir 判断目录是否被排除在 Go 源文件遍历之外
+// go 工具会忽略 testdata 和隐藏目录，vendor 中的代码也不属于项目自身
+func isSkippedSourceDir(name string) bool {
+	return strings.HasPrefix(name, ".") || name == "testdata" || workspaceSkipDirs[name]
+}
+
+// importPaths maps the local names of the imports in the file to their import paths
+// Imports without an explicit name use the last path element
+//
+// importPaths 将文件中导入的本地名称映射到导入路径
+// 没有显式名称的导入使用路径的最后一个元素
+func importPaths(astFile *ast.File) map[string]string {
+	paths := map[string]string{}
+	for _, importSpec := range astFile.Imports {
+		path := strings.Trim(importSpec.Path.Value, "\"`")
+		name := path[strings.LastIndex(path, "/")+1:]
+		if importSpec.Name != nil {
+			name = importSpec.Name.Name
+		}
+		paths[name] = path
+	}
+	return paths
+}
+
+// importPackageName guesses the package name of an import path from its last element
+// Major version suffixes such as /v2 and .v3 are skipped, /v1 is a real package name
+//
+// importPackageName 根据导入路径的最后一个元素推测包名
+// 跳过 /v2 和 .v3 等主版本后缀，/v1 是真实的包名
+func importPackageName(importPath string) string {
+	elements := strings.Split(importPath, "/")
+	name := elements[len(elements)-1]
+	if len(elements) > 1 && majorVersionPattern.MatchString(name) {
+		name = elements[len(elements)-2]
+	}
+	if idx := strings.LastIndex(name, ".v"); idx > 0 && gopkgVersionPattern.MatchString(name[idx+1:]) {
+		name = name[:idx]
+	}
+	return name
+}
+
+// majorVersionPattern matches major version elements such as v2, which start at v2
+//
+// majorVersionPattern 匹配 v2 等主版本元素，从 v2 开始
+var majorVersionPattern = regexp.MustCompile(`^v([2-9]|[1-9][0-9]+)$`)
+
+// gopkgVersionPattern matches gopkg.in version suffixes such as v3
+//
+// gopkgVersionPattern 匹配 gopkg.in 的版本后缀，例如 v3
+var gopkgVersionPattern = regexp.MustCompile(`^v[0-9]+$`)
+
+// qualifiedTypeString renders the type expression with every named type qualified by its package name
+// Local types get the package name of the file and imported types the name of the imported package
+// So *GreeterUsecase in package biz and *biz.GreeterUsecase elsewhere both become *biz.GreeterUsecase
+//
+// qualifiedTypeString 渲染类型表达式，每个命名类型都带有包名限定
+// 本地类型使用文件的包名，导入类型使用被导入包的包名
+// 因此 biz 包中的 *GreeterUsecase 和其他包中的 *biz.GreeterUsecase 都会变为 *biz.GreeterUsecase
+func qualifiedTypeString(expr ast.Expr, pkgName string, imports map[string]string) string {
+	switch x := expr.(type) {
+	case *ast.Ident:
+		if types.Universe.Lookup(x.Name) != nil {
+			return x.Name
+		}
+		return pkgName + "." + x.Name
+	case *ast.StarExpr:
+		return "*" + qualifiedTypeString(x.X, pkgName, imports)
+	case *ast.SelectorExpr:
+		if ident, ok := x.X.(*ast.Ident); ok {
+			if importPath, ok := imports[ident.Name]; ok {
+				return importPackageName(importPath) + "." + x.Sel.Name
+			}
+		}
+		return types.ExprString(x)
+	case *ast.ArrayType:
+		if x.Len == nil {
+			return "[]" + qualifiedTypeString(x.Elt, pkgName, imports)
+		}
+		return "[" + types.ExprString(x.Len) + "]" + qualifiedTypeString(x.Elt, pkgName, imports)
+	case *ast.MapType:
+		return "map[" + qualifiedTypeString(x.Key, pkgName, imports) + "]" + qualifiedTypeString(x.Value, pkgName, imports)
+	case *ast.Ellipsis:
+		return "..." + qualifiedTypeString(x.Elt, pkgName, imports)
+	case *ast.ParenExpr:
+		return qualifiedTypeString(x.X, pkgName, imports)
+	default:
+		return types.ExprString(expr)
+	}
+}
+
+// qualifiedFieldTypes lists the qualified type of each parameter or result, repeated for grouped names
+//
+// qualifiedFieldTypes 列出每个参数或返回值的限定类型，多个名称共享类型时重复列出
+func qualifiedFieldTypes(fieldList *ast.FieldList, pkgName string, imports map[string]string) []string {
+	fieldTypes := make([]string, 0)
+	if fieldList == nil {
+		return fieldTypes
+	}
+	for _, field := range fieldList.List {
+		fieldType := qualifiedTypeString(field.Type, pkgName, imports)
+		for count := max(len(field.Names), 1); count > 0; count-- {
+			fieldTypes = append(fieldTypes, fieldType)
+		}
+	}
+	return fieldTypes
+}
@@ -0,0 +1,37 @@
+package astkratos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+	"github.com/yyle88/runpath"
+)
+
+// TestParseGoFile tests parsing of a Go source file with absolute path resolution
+//
+// TestParseGoFile 测试 Go 源文件解析及绝对路径解析
+func TestParseGoFile(t *testing.T) {
+	goFile := rese.P1(parseGoFile(runpath.Path()))
+	require.Equal(t, runpath.Path(), goFile.srcPath)
+	require.Equal(t, "astkratos", goFile.astFile.Name.Name)
+	require.NotEmpty(t, goFile.source)
+}
+
+// TestParseGoFileMissing tests that a missing file returns an error
+//
+// TestParseGoFileMissing 测试缺失文件时返回错误
+func TestParseGoFileMissing(t *testing.T) {
+	_, err := parseGoFile(runpath.PARENT.Join("testdata", "missing.go"))
+	require.Error(t, err)
+}
+
+// TestImportPackageName tests package name guessing with major version suffixes
+//
+// TestImportPackageName 测试带主版本后缀的包名推测
+func TestImportPackageName(t *testing.T) {
+	require.Equal(t, "log", importPackageName("github.com/go-kratos/kratos/v2/log"))
+	require.Equal(t, "kratos", importPackageName("github.com/go-kratos/kratos/v2"))
+	require.Equal(t, "yaml", importPackageName("gopkg.in/yaml.v3"))
+	require.Equal(t, "v1", importPackageName("demokratos/api/helloworld/v1"))
+}
@@ -0,0 +1,225 @@
+// Package astkratos go.work support: Workspace-aware module information
+// Parses go.work natively, including its use, replace, godebug and toolchain directives
+// Loads the ModuleInfo of every module in use and resolves cross-module requires to local paths
+// Lets monorepos built on go.work be analyzed as a whole instead of module by module
+//
+// astkratos go.work 支持：感知工作区的模块信息
+// 原生解析 go.work，包括其中的 use、replace、godebug 和 toolchain 指令
+// 加载每个被使用模块的 ModuleInfo，并将跨模块的依赖解析为本地路径
+// 使基于 go.work 的单仓库能够作为整体分析，而不是逐个模块分析
+package astkratos
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/yyle88/erero"
+	"github.com/yyle88/osexec"
+	"golang.org/x/mod/modfile"
+)
+
+// WorkUse represents a use directive of go.work
+//
+// WorkUse 表示 go.work 中的 use 指令
+type WorkUse struct {
+	DiskPath   string `json:"DiskPath"`       // Module directory as written, such as ./app/shop // 源码中的模块目录，例如 ./app/shop
+	ModulePath string `json:"ModulePath"`     // Module path when given // 给出时的模块路径
+	Line       int    `json:"Line,omitempty"` // Line in go.work, native parsing only // 在 go.work 中的行号，仅原生解析时提供
+}
+
+// ModuleVersion represents a module path with an optional version
+//
+// ModuleVersion 表示带有可选版本的模块路径
+type ModuleVersion struct {
+	Path    string `json:"Path"`    // Module path or local directory // 模块路径或本地目录
+	Version string `json:"Version"` // Version, blank for local directories // 版本，本地目录时为空
+}
+
+// WorkReplace represents a replace directive of go.work
+//
+// WorkReplace 表示 go.work 中的 replace 指令
+type WorkReplace struct {
+	Old  ModuleVersion `json:"Old"`            // Replaced module // 被替换的模块
+	New  ModuleVersion `json:"New"`            // Replacement module or local directory // 替换的模块或本地目录
+	Line int           `json:"Line,omitempty"` // Line in go.work, native parsing only // 在 go.work 中的行号，仅原生解析时提供
+}
+
+// WorkInfo represents the directives of a go.work file
+//
+// WorkInfo 表示 go.work 文件中的指令
+type WorkInfo struct {
+	Go        string         `json:"Go"`        // Go version // Go 版本
+	Toolchain string         `json:"Toolchain"` // Toolchain version if specified // 指定的工具链版本
+	Godebug   []*Godebug     `json:"Godebug"`   // Workspace-wide godebug settings // 工作区范围的 godebug 设置
+	Use       []*WorkUse     `json:"Use"`       // Modules in use // 被使用的模块
+	Replace   []*WorkReplace `json:"Replace"`   // Workspace-wide replacements // 工作区范围的替换
+}
+
+// LocalRequire represents a require that resolves to a directory on disk
+//
+// LocalRequire 表示解析到磁盘目录的依赖
+type LocalRequire struct {
+	Path    string `json:"Path"`    // Required module path // 依赖模块路径
+	Version string `json:"Version"` // Required version // 所需版本
+	Dir     string `json:"Dir"`     // Absolute directory of the module // 模块的绝对目录
+	Via     string `json:"Via"`     // Resolved through use or replace // 通过 use 或 replace 解析
+}
+
+// WorkModule represents a module in use with its requires resolved against the workspace
+//
+// WorkModule 表示被使用的模块及其在工作区中解析后的依赖
+type WorkModule struct {
+	Dir           string          `json:"Dir"`           // Absolute module directory // 模块的绝对目录
+	ModuleInfo    *ModuleInfo     `json:"ModuleInfo"`    // Information from go.mod // 来自 go.mod 的信息
+	LocalRequires []*LocalRequire `json:"LocalRequires"` // Requires served by local directories // 由本地目录提供的依赖
+}
+
+// WorkspaceModules holds go.work and every module in use
+//
+// WorkspaceModules 保存 go.work 以及每个被使用的模块
+type WorkspaceModules struct {
+	Root     string        `json:"Root"`     // Directory holding go.work // go.work 所在目录
+	WorkInfo *WorkInfo     `json:"WorkInfo"` // Directives of go.work // go.work 中的指令
+	Modules  []*WorkModule `json:"Modules"`  // Modules in use order // 按 use 顺序排列的模块
+}
+
+// GetWorkInfo reads the go.work governing the directory
+// Parses the nearest go.work at or above the directory natively, so no Go toolchain is needed
+//
+// GetWorkInfo 读取管理该目录的 go.work
+// 原生解析该目录或其上层中最近的 go.work，因此不需要 Go 工具链
+func GetWorkInfo(workRoot string) (*WorkInfo, error) {
+	absPath, err := filepath.Abs(workRoot)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	for dir := absPath; ; dir = filepath.Dir(dir) {
+		if info, err := os.Stat(filepath.Join(dir, "go.work")); err == nil && !info.IsDir() {
+			return ParseWorkFile(filepath.Join(dir, "go.work"))
+		}
+		if filepath.Dir(dir) == dir {
+			return nil, erero.Errorf("no go.work found at or above %s", absPath)
+		}
+	}
+}
+
+// GetWorkInfoWithGoCommand reads the go.work governing the directory through go work edit -json
+// Keeps the behavior of the go command, such as honoring GOWORK, at the cost of needing a toolchain
+//
+// GetWorkInfoWithGoCommand 通过 go work edit -json 读取管理该目录的 go.work
+// 保留 go 命令的行为（例如遵循 GOWORK），代价是需要 Go 工具链
+func GetWorkInfoWithGoCommand(workRoot string) (*WorkInfo, error) {
+	output, err := osexec.ExecInPath(workRoot, "go", "work", "edit", "-json")
+	if err != nil {
+		return nil, erero.Wrapf(err, "read go.work in %s", workRoot)
+	}
+	var workInfo WorkInfo
+	if err := json.Unmarshal(output, &workInfo); err != nil {
+		return nil, erero.Wro(err)
+	}
+	return &workInfo, nil
+}
+
+// ParseWorkFile parses the go.work file at the path without running the go command
+//
+// ParseWorkFile 在不运行 go 命令的情况下解析指定路径的 go.work 文件
+func ParseWorkFile(path string) (*WorkInfo, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	file, err := modfile.ParseWork(path, content, nil)
+	if err != nil {
+		return nil, erero.Wrapf(err, "parse %s", path)
+	}
+
+	workInfo := &WorkInfo{
+		Godebug: make([]*Godebug, 0, len(file.Godebug)),
+		Use:     make([]*WorkUse, 0, len(file.Use)),
+		Replace: make([]*WorkReplace, 0, len(file.Replace)),
+	}
+	if file.Go != nil {
+		workInfo.Go = file.Go.Version
+	}
+	if file.Toolchain != nil {
+		workInfo.Toolchain = file.Toolchain.Name
+	}
+	for _, item := range file.Godebug {
+		workInfo.Godebug = append(workInfo.Godebug, &Godebug{Key: item.Key, Value: item.Value, Line: item.Syntax.Start.Line, Comments: modLineComments(item.Syntax)})
+	}
+	for _, item := range file.Use {
+		workInfo.Use = append(workInfo.Use, &WorkUse{DiskPath: item.Path, ModulePath: item.ModulePath, Line: item.Syntax.Start.Line})
+	}
+	for _, item := range file.Replace {
+		workInfo.Replace = append(workInfo.Replace, &WorkReplace{
+			Old:  ModuleVersion{Path: item.Old.Path, Version: item.Old.Version},
+			New:  ModuleVersion{Path: item.New.Path, Version: item.New.Version},
+			Line: item.Syntax.Start.Line,
+		})
+	}
+	return workInfo, nil
+}
+
+// GetWorkspaceModules loads every module in use of the go.work in the root directory
+// Requires of a module in use resolve to its directory, the local replacements of go.work resolve to theirs
+//
+// GetWorkspaceModules 加载根目录中 go.work 所使用的每个模块
+// 对被使用模块的依赖解析到其目录，go.work 中的本地替换解析到替换目录
+func GetWorkspaceModules(workRoot string) (*WorkspaceModules, error) {
+	workRoot, err := filepath.Abs(workRoot)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	workInfo, err := GetWorkInfo(workRoot)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+
+	workspace := &WorkspaceModules{
+		Root:     workRoot,
+		WorkInfo: workInfo,
+		Modules:  make([]*WorkModule, 0, len(workInfo.Use)),
+	}
+	moduleDirs := map[string]string{}
+	for _, use := range workInfo.Use {
+		dir := resolveWorkPath(workRoot, use.DiskPath)
+		moduleInfo, err := GetModuleInfo(dir)
+		if err != nil {
+			return nil, erero.Wro(err)
+		}
+		moduleDirs[moduleInfo.Module.Path] = dir
+		workspace.Modules = append(workspace.Modules, &WorkModule{
+			Dir:           dir,
+			ModuleInfo:    moduleInfo,
+			LocalRequires: make([]*LocalRequire, 0),
+		})
+	}
+
+	for _, module := range workspace.Modules {
+		for _, require := range module.ModuleInfo.Require {
+			if dir, ok := moduleDirs[require.Path]; ok {
+				module.LocalRequires = append(module.LocalRequires, &LocalRequire{Path: require.Path, Version: require.Version, Dir: dir, Via: "use"})
+				continue
+			}
+			for _, replace := range workInfo.Replace {
+				if replace.Old.Path != require.Path || (replace.Old.Version != "" && replace.Old.Version != require.Version) || replace.New.Version != "" {
+					continue
+				}
+				module.LocalRequires = append(module.LocalRequires, &LocalRequire{Path: require.Path, Version: require.Version, Dir: resolveWorkPath(workRoot, replace.New.Path), Via: "replace"})
+				break
+			}
+		}
+	}
+	return workspace, nil
+}
+
+// resolveWorkPath resolves a directory of go.work against the go.work directory
+//
+// resolveWorkPath 基于 go.work 所在目录解析 go.work 中的目录
+func resolveWorkPath(workRoot string, path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(workRoot, filepath.FromSlash(path))
+}
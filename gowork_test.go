@@ -0,0 +1,98 @@
+package astkratos_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/orzkratos/astkratos"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/neatjson/neatjsons"
+)
+
+// TestGetWorkspaceModules tests go.work directives and requires resolved through use and replace
+//
+// TestGetWorkspaceModules 测试 go.work 指令以及通过 use 和 replace 解析的依赖
+func TestGetWorkspaceModules(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"shop", "user", "common"} {
+		must.Done(os.MkdirAll(filepath.Join(root, name), 0755))
+	}
+	must.Done(os.WriteFile(filepath.Join(root, "shop", "go.mod"), []byte(`module example.com/shop
+
+go 1.25.0
+
+require (
+	example.com/common v1.0.0
+	example.com/user v0.0.0
+	github.com/go-kratos/kratos/v2 v2.8.0
+)
+`), 0644))
+	must.Done(os.WriteFile(filepath.Join(root, "user", "go.mod"), []byte("module example.com/user\n\ngo 1.25.0\n"), 0644))
+	must.Done(os.WriteFile(filepath.Join(root, "common", "go.mod"), []byte("module example.com/common\n\ngo 1.25.0\n"), 0644))
+	must.Done(os.WriteFile(filepath.Join(root, "go.work"), []byte(`go 1.25.0
+
+toolchain go1.25.1
+
+godebug default=go1.21
+
+use (
+	./shop
+	./user
+)
+
+replace example.com/common v1.0.0 => ./common
+`), 0644))
+
+	workspace, err := astkratos.GetWorkspaceModules(root)
+	require.NoError(t, err)
+	t.Log(neatjsons.S(workspace))
+
+	require.Equal(t, "1.25.0", workspace.WorkInfo.Go)
+	require.Equal(t, "go1.25.1", workspace.WorkInfo.Toolchain)
+	require.Len(t, workspace.WorkInfo.Use, 2)
+	require.Equal(t, "./shop", workspace.WorkInfo.Use[0].DiskPath)
+	require.Equal(t, 8, workspace.WorkInfo.Use[0].Line)
+	require.Len(t, workspace.WorkInfo.Godebug, 1)
+	require.Equal(t, "default", workspace.WorkInfo.Godebug[0].Key)
+	require.Equal(t, "go1.21", workspace.WorkInfo.Godebug[0].Value)
+	require.Len(t, workspace.WorkInfo.Replace, 1)
+	require.Equal(t, "example.com/common", workspace.WorkInfo.Replace[0].Old.Path)
+	require.Equal(t, 12, workspace.WorkInfo.Replace[0].Line)
+
+	require.Len(t, workspace.Modules, 2)
+	shop := workspace.Modules[0]
+	require.Equal(t, filepath.Join(root, "shop"), shop.Dir)
+	require.Equal(t, "example.com/shop", shop.ModuleInfo.Module.Path)
+	require.Len(t, shop.LocalRequires, 2)
+	require.Equal(t, &astkratos.LocalRequire{Path: "example.com/common", Version: "v1.0.0", Dir: filepath.Join(root, "common"), Via: "replace"}, shop.LocalRequires[0])
+	require.Equal(t, &astkratos.LocalRequire{Path: "example.com/user", Version: "v0.0.0", Dir: filepath.Join(root, "user"), Via: "use"}, shop.LocalRequires[1])
+	require.Empty(t, workspace.Modules[1].LocalRequires)
+}
+
+// TestGetWorkInfoWithGoCommand tests that native parsing agrees with go work edit -json, also from a module directory
+//
+// TestGetWorkInfoWithGoCommand 测试原生解析与 go work edit -json 的结果一致，在模块目录中也是如此
+func TestGetWorkInfoWithGoCommand(t *testing.T) {
+	root := t.TempDir()
+	must.Done(os.MkdirAll(filepath.Join(root, "shop"), 0755))
+	must.Done(os.WriteFile(filepath.Join(root, "shop", "go.mod"), []byte("module example.com/shop\n\ngo 1.25.0\n"), 0644))
+	must.Done(os.WriteFile(filepath.Join(root, "go.work"), []byte("go 1.25.0\n\nuse ./shop\n\nreplace example.com/common => ../common\n"), 0644))
+
+	nativeInfo, err := astkratos.GetWorkInfo(filepath.Join(root, "shop"))
+	require.NoError(t, err)
+	commandInfo, err := astkratos.GetWorkInfoWithGoCommand(filepath.Join(root, "shop"))
+	require.NoError(t, err)
+
+	require.Equal(t, commandInfo.Go, nativeInfo.Go)
+	require.Len(t, nativeInfo.Use, len(commandInfo.Use))
+	require.Equal(t, commandInfo.Use[0].DiskPath, nativeInfo.Use[0].DiskPath)
+	require.Len(t, nativeInfo.Replace, len(commandInfo.Replace))
+	require.Equal(t, commandInfo.Replace[0].Old, nativeInfo.Replace[0].Old)
+	require.Equal(t, commandInfo.Replace[0].New, nativeInfo.Replace[0].New)
+
+	must.Done(os.Remove(filepath.Join(root, "go.work")))
+	_, err = astkratos.GetWorkInfo(filepath.Join(root, "shop"))
+	require.Error(t, err)
+}
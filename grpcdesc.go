@@ -0,0 +1,152 @@
+// Package astkratos gRPC service descriptor parsing: Decodes grpc.ServiceDesc variables
+// Reads the Xxx_ServiceDesc composite literal of each generated _grpc.pb.go file
+// Provides the fully qualified proto service name, method and stream names and the source proto path
+//
+// astkratos gRPC 服务描述解析：解码 grpc.ServiceDesc 变量
+// 读取每个生成的 _grpc.pb.go 文件中的 Xxx_ServiceDesc 复合字面量
+// 提供完整的 proto 服务名、方法和流名称以及源 proto 文件路径
+package astkratos
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+)
+
+// ServiceDescriptor represents a decoded grpc.ServiceDesc variable
+//
+// ServiceDescriptor 表示解码后的 grpc.ServiceDesc 变量
+type ServiceDescriptor struct {
+	Name        string              // Variable name, such as Greeter_ServiceDesc // 变量名，例如 Greeter_ServiceDesc
+	ServiceName string              // Fully qualified proto service name, such as helloworld.v1.Greeter // 完整的 proto 服务名，例如 helloworld.v1.Greeter
+	HandlerType string              // Server interface type, such as GreeterServer // 服务器接口类型，例如 GreeterServer
+	Methods     []string            // Unary method names // 一元方法名称
+	Streams     []*StreamDescriptor // Streaming method descriptors // 流式方法描述
+	Metadata    string              // Source proto file path, such as helloworld/v1/greeter.proto // 源 proto 文件路径，例如 helloworld/v1/greeter.proto
+	Package     string              // Package name where the variable is defined // 变量定义所在的包名
+	SrcPath     string              // Source file path where the variable is defined // 变量定义所在的源文件路径
+}
+
+// StreamDescriptor represents a grpc.StreamDesc entry of the service descriptor
+//
+// StreamDescriptor 表示服务描述中的 grpc.StreamDesc 条目
+type StreamDescriptor struct {
+	StreamName    string // Streaming method name // 流式方法名称
+	ServerStreams bool   // Server sends a stream // 服务端发送流
+	ClientStreams bool   // Client sends a stream // 客户端发送流
+}
+
+// extractServiceDescriptors decodes the grpc.ServiceDesc variables declared in the file
+//
+// extractServiceDescriptors 解码文件中声明的 grpc.ServiceDesc 变量
+func extractServiceDescriptors(astFile *ast.File, srcPath string) []*ServiceDescriptor {
+	descriptors := make([]*ServiceDescriptor, 0)
+	for _, decl := range astFile.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			valueSpec := spec.(*ast.ValueSpec)
+			for idx, value := range valueSpec.Values {
+				compositeLit, ok := value.(*ast.CompositeLit)
+				if !ok || types.ExprString(compositeLit.Type) != "grpc.ServiceDesc" || idx >= len(valueSpec.Names) {
+					continue
+				}
+				descriptor := &ServiceDescriptor{
+					Name:    valueSpec.Names[idx].Name,
+					Methods: make([]string, 0),
+					Streams: make([]*StreamDescriptor, 0),
+					Package: astFile.Name.Name,
+					SrcPath: srcPath,
+				}
+				for key, value := range compositeLitFields(compositeLit) {
+					switch key {
+					case "ServiceName":
+						descriptor.ServiceName = stringLitValue(value)
+					case "HandlerType":
+						descriptor.HandlerType = handlerTypeName(value)
+					case "Metadata":
+						descriptor.Metadata = stringLitValue(value)
+					case "Methods":
+						for _, element := range compositeLitElements(value) {
+							descriptor.Methods = append(descriptor.Methods, stringLitValue(compositeLitFields(element)["MethodName"]))
+						}
+					case "Streams":
+						for _, element := range compositeLitElements(value) {
+							fields := compositeLitFields(element)
+							descriptor.Streams = append(descriptor.Streams, &StreamDescriptor{
+								StreamName:    stringLitValue(fields["StreamName"]),
+								ServerStreams: types.ExprString(fields["ServerStreams"]) == "true",
+								ClientStreams: types.ExprString(fields["ClientStreams"]) == "true",
+							})
+						}
+					}
+				}
+				descriptors = append(descriptors, descriptor)
+			}
+		}
+	}
+	return descriptors
+}
+
+// compositeLitFields maps the keyed fields of a composite literal
+//
+// compositeLitFields 映射复合字面量中的键值字段
+func compositeLitFields(expr ast.Expr) map[string]ast.Expr {
+	fields := map[string]ast.Expr{}
+	compositeLit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return fields
+	}
+	for _, elt := range compositeLit.Elts {
+		if keyValue, ok := elt.(*ast.KeyValueExpr); ok {
+			if ident, ok := keyValue.Key.(*ast.Ident); ok {
+				fields[ident.Name] = keyValue.Value
+			}
+		}
+	}
+	return fields
+}
+
+// compositeLitElements returns the elements of a slice composite literal
+//
+// compositeLitElements 返回切片复合字面量中的元素
+func compositeLitElements(expr ast.Expr) []ast.Expr {
+	if compositeLit, ok := expr.(*ast.CompositeLit); ok {
+		return compositeLit.Elts
+	}
+	return nil
+}
+
+// stringLitValue returns the unquoted value of a string literal, blank when not a string literal
+//
+// stringLitValue 返回字符串字面量去掉引号后的值，非字符串字面量时返回空字符串
+func stringLitValue(expr ast.Expr) string {
+	basicLit, ok := expr.(*ast.BasicLit)
+	if !ok || basicLit.Kind != token.STRING {
+		return ""
+	}
+	value, err := strconv.Unquote(basicLit.Value)
+	if err != nil {
+		return ""
+	}
+	return value
+}
+
+// handlerTypeName reads GreeterServer from the (*GreeterServer)(nil) expression
+//
+// handlerTypeName 从 (*GreeterServer)(nil) 表达式中读取 GreeterServer
+func handlerTypeName(expr ast.Expr) string {
+	if callExpr, ok := expr.(*ast.CallExpr); ok {
+		expr = callExpr.Fun
+	}
+	if parenExpr, ok := expr.(*ast.ParenExpr); ok {
+		expr = parenExpr.X
+	}
+	if starExpr, ok := expr.(*ast.StarExpr); ok {
+		expr = starExpr.X
+	}
+	return types.ExprString(expr)
+}
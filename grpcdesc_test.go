@@ -0,0 +1,78 @@
+package astkratos_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/orzkratos/astkratos"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/neatjson/neatjsons"
+)
+
+// TestListGrpcServiceDescriptors tests decoding of grpc.ServiceDesc variables
+//
+// TestListGrpcServiceDescriptors 测试 grpc.ServiceDesc 变量的解码
+func TestListGrpcServiceDescriptors(t *testing.T) {
+	descriptors := astkratos.ListGrpcServiceDescriptors(demoApiRoot)
+	t.Log(neatjsons.S(descriptors))
+	require.Len(t, descriptors, 3)
+
+	echo := descriptors[0]
+	require.Equal(t, "Echo_ServiceDesc", echo.Name)
+	require.Equal(t, "echo.v1.Echo", echo.ServiceName)
+	require.Equal(t, "EchoServer", echo.HandlerType)
+	require.Equal(t, []string{"Ping"}, echo.Methods)
+	require.Equal(t, []*astkratos.StreamDescriptor{
+		{StreamName: "Watch", ServerStreams: true},
+		{StreamName: "Collect", ClientStreams: true},
+		{StreamName: "Chat", ServerStreams: true, ClientStreams: true},
+	}, echo.Streams)
+	require.Equal(t, "echo/v1/echo.proto", echo.Metadata)
+	require.Equal(t, "v1", echo.Package)
+
+	greeter := descriptors[1]
+	require.Equal(t, "helloworld.v1.Greeter", greeter.ServiceName)
+	require.Empty(t, greeter.Streams)
+	require.Equal(t, "helloworld/v1/greeter.proto", greeter.Metadata)
+}
+
+// TestListGrpcServicesFullMethodNameFromDescriptor tests full method names derived from the service descriptor
+//
+// TestListGrpcServicesFullMethodNameFromDescriptor 测试从服务描述推导完整方法名
+func TestListGrpcServicesFullMethodNameFromDescriptor(t *testing.T) {
+	root := t.TempDir()
+	source := `package v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+)
+
+type PingerServer interface {
+	Ping(context.Context, *PingRequest) (*PingReply, error)
+	mustEmbedUnimplementedPingerServer()
+}
+
+type UnimplementedPingerServer struct{}
+
+var Pinger_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pinger.v1.Pinger",
+	HandlerType: (*PingerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Ping",
+			Handler:    _Pinger_Ping_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pinger/v1/pinger.proto",
+}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(root, "pinger_grpc.pb.go"), []byte(source), 0644))
+
+	services := astkratos.ListGrpcServices(root)
+	require.Len(t, services, 1)
+	require.Len(t, services[0].Methods, 1)
+	require.Equal(t, "/pinger.v1.Pinger/Ping", services[0].Methods[0].FullMethodName)
+}
@@ -0,0 +1,116 @@
+// Package astkratos gRPC file extraction: AST-based analysis of generated _grpc.pb.go sources
+// Parses each generated file into a Go AST and collects gRPC type definitions from TypeSpec nodes
+// Recognizes client interfaces, server interfaces and Unimplemented server stubs in one pass
+// Independent of code formatting, trailing comments and protoc-gen-go-grpc layout changes
+//
+// astkratos gRPC 文件提取：基于 AST 分析生成的 _grpc.pb.go 源文件
+// 将每个生成文件解析为 Go AST，并从 TypeSpec 节点收集 gRPC 类型定义
+// 一次遍历即可识别客户端接口、服务器接口和 Unimplemented 服务器存根
+// 不受代码格式、行尾注释和 protoc-gen-go-grpc 布局变化的影响
+package astkratos
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"github.com/yyle88/erero"
+)
+
+// grpcPbGoFile holds the gRPC definitions extracted from one _grpc.pb.go file
+//
+// grpcPbGoFile 保存从单个 _grpc.pb.go 文件提取的 gRPC 定义
+type grpcPbGoFile struct {
+	srcPath              string                // Absolute path of the file // 文件的绝对路径
+	clients              []*GrpcTypeDefinition // Client interfaces // 客户端接口
+	servers              []*GrpcTypeDefinition // Server interfaces, Unsafe*Server excluded // 服务器接口，不含 Unsafe*Server
+	unimplementedServers []*GrpcTypeDefinition // Unimplemented*Server stub structs // Unimplemented*Server 存根结构体
+	services             []*GrpcTypeDefinition // Services with RPC methods, derived from the stubs // 从存根推导的服务，包含 RPC 方法
+	serviceDescs         []*ServiceDescriptor  // Decoded grpc.ServiceDesc variables // 解码后的 grpc.ServiceDesc 变量
+	serviceLines         map[string]int        // Line of the XxxServer interface keyed by service name // 以服务名为键的 XxxServer 接口行号
+}
+
+// analyzeGrpcPbGoFile parses the _grpc.pb.go file and collects gRPC definitions from its TypeSpec nodes
+//
+// analyzeGrpcPbGoFile 解析 _grpc.pb.go 文件并从其 TypeSpec 节点收集 gRPC 定义
+func analyzeGrpcPbGoFile(path string) (*grpcPbGoFile, error) {
+	goFile, err := parseGoFile(path)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	srcPath, astFile := goFile.srcPath, goFile.astFile
+	pkgName := astFile.Name.Name
+
+	newDefinition := func(name string) *GrpcTypeDefinition {
+		return &GrpcTypeDefinition{
+			Name:    name,
+			Package: pkgName,
+			SrcPath: srcPath,
+		}
+	}
+
+	result := &grpcPbGoFile{srcPath: srcPath, serviceLines: map[string]int{}}
+	typeSpecs := map[string]*ast.TypeSpec{}
+	for _, decl := range astFile.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			typeSpecs[typeSpec.Name.Name] = typeSpec
+			// Skip type aliases, such as the Xxx_StreamServer aliases of generic streams
+			// 跳过类型别名，例如泛型流的 Xxx_StreamServer 别名
+			if typeSpec.Assign.IsValid() {
+				continue
+			}
+			name := typeSpec.Name.Name
+			switch typeSpec.Type.(type) {
+			case *ast.InterfaceType:
+				switch {
+				case strings.HasSuffix(name, "Client"):
+					result.clients = append(result.clients, newDefinition(name))
+				case strings.HasSuffix(name, "Server") && !strings.HasPrefix(name, "Unsafe"):
+					result.servers = append(result.servers, newDefinition(name))
+				}
+			case *ast.StructType:
+				if strings.HasPrefix(name, "Unimplemented") && strings.HasSuffix(name, "Server") {
+					result.unimplementedServers = append(result.unimplementedServers, newDefinition(name))
+				}
+			}
+		}
+	}
+
+	// Derive services from the stubs and attach the methods of the matching server interfaces
+	// 从存根推导服务，并附加对应服务器接口中的方法
+	result.serviceDescs = extractServiceDescriptors(astFile, srcPath)
+	fullMethodNames := collectFullMethodNames(astFile)
+	for _, unimplement := range result.unimplementedServers {
+		serviceName := strings.TrimSuffix(strings.TrimPrefix(unimplement.Name, "Unimplemented"), "Server")
+		if serviceName == "" {
+			continue
+		}
+		service := newDefinition(serviceName)
+		service.Methods = make([]*GrpcMethodDefinition, 0)
+		if typeSpec, ok := typeSpecs[serviceName+"Server"]; ok {
+			if interfaceType, ok := typeSpec.Type.(*ast.InterfaceType); ok {
+				result.serviceLines[serviceName] = goFile.fset.Position(typeSpec.Pos()).Line
+				service.Methods = extractGrpcMethods(goFile.fset, serviceName, interfaceType, typeSpecs, fullMethodNames)
+			}
+		}
+		// Fill full method names that are not spelled out in the file from the service descriptor
+		// 对文件中未写出的完整方法名，使用服务描述进行补全
+		for _, descriptor := range result.serviceDescs {
+			if descriptor.HandlerType != serviceName+"Server" || descriptor.ServiceName == "" {
+				continue
+			}
+			for _, method := range service.Methods {
+				if method.FullMethodName == "" {
+					method.FullMethodName = "/" + descriptor.ServiceName + "/" + method.Name
+				}
+			}
+		}
+		result.services = append(result.services, service)
+	}
+	return result, nil
+}
@@ -0,0 +1,36 @@
+package astkratos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+	"github.com/yyle88/runpath"
+)
+
+// TestAnalyzeGrpcPbGoFile tests AST extraction of a generated file with a trailing comment on the client type
+//
+// TestAnalyzeGrpcPbGoFile 测试对客户端类型带行尾注释的生成文件进行 AST 提取
+func TestAnalyzeGrpcPbGoFile(t *testing.T) {
+	path := runpath.PARENT.Join("testdata", "demokratos", "api", "legacy", "v1", "legacy_grpc.pb.go")
+	grpcFile := rese.P1(analyzeGrpcPbGoFile(path))
+	require.Len(t, grpcFile.clients, 4)
+	require.Equal(t, "LegacyClient", grpcFile.clients[0].Name)
+	require.Equal(t, "v1", grpcFile.clients[0].Package)
+	require.Equal(t, path, grpcFile.clients[0].SrcPath)
+	require.Len(t, grpcFile.servers, 4)
+	require.Len(t, grpcFile.unimplementedServers, 1)
+	require.Equal(t, "UnimplementedLegacyServer", grpcFile.unimplementedServers[0].Name)
+}
+
+// TestAnalyzeGrpcPbGoFileGenericStreams tests that stream type aliases are not reported as interfaces
+//
+// TestAnalyzeGrpcPbGoFileGenericStreams 测试流类型别名不会被识别为接口
+func TestAnalyzeGrpcPbGoFileGenericStreams(t *testing.T) {
+	path := runpath.PARENT.Join("testdata", "demokratos", "api", "echo", "v1", "echo_grpc.pb.go")
+	grpcFile := rese.P1(analyzeGrpcPbGoFile(path))
+	require.Len(t, grpcFile.clients, 1)
+	require.Equal(t, "EchoClient", grpcFile.clients[0].Name)
+	require.Len(t, grpcFile.servers, 1)
+	require.Equal(t, "EchoServer", grpcFile.servers[0].Name)
+}
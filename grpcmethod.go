@@ -0,0 +1,217 @@
+// Package astkratos gRPC method extraction: RPC method definitions from generated server interfaces
+// Reads request and response message types and the streaming kind of each RPC method
+// Supports both the legacy Xxx_MethodServer stream interfaces and the generic grpc stream types
+// Resolves the full method name from the generated constants and string literals
+//
+// astkratos gRPC 方法提取：从生成的服务器接口中提取 RPC 方法定义
+// 读取每个 RPC 方法的请求和响应消息类型以及流式类型
+// 同时支持旧版 Xxx_MethodServer 流接口和泛型 grpc 流类型
+// 从生成的常量和字符串字面量中解析完整方法名
+package astkratos
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// GrpcStreamingKind represents the streaming kind of an RPC method
+//
+// GrpcStreamingKind 表示 RPC 方法的流式类型
+type GrpcStreamingKind string
+
+const (
+	GrpcStreamingUnary  GrpcStreamingKind = "unary"  // Single request and single response // 单请求单响应
+	GrpcStreamingClient GrpcStreamingKind = "client" // Client sends a stream of requests // 客户端流式请求
+	GrpcStreamingServer GrpcStreamingKind = "server" // Server sends a stream of responses // 服务端流式响应
+	GrpcStreamingBidi   GrpcStreamingKind = "bidi"   // Both sides stream messages // 双向流式
+)
+
+// GrpcMethodDefinition represents an RPC method of a gRPC service
+//
+// GrpcMethodDefinition 表示 gRPC 服务中的 RPC 方法
+type GrpcMethodDefinition struct {
+	Name           string            // RPC method name // RPC 方法名称
+	RequestType    string            // Request message type, such as HelloRequest // 请求消息类型，例如 HelloRequest
+	ResponseType   string            // Response message type, such as HelloReply // 响应消息类型，例如 HelloReply
+	StreamingKind  GrpcStreamingKind // Streaming kind of the method // 方法的流式类型
+	FullMethodName string            // Full method name, such as /helloworld.v1.Greeter/SayHello // 完整方法名，例如 /helloworld.v1.Greeter/SayHello
+	Line           int               // Line number in the XxxServer interface // 在 XxxServer 接口中的行号
+}
+
+// extractGrpcMethods reads the RPC methods from the XxxServer interface of the service
+//
+// extractGrpcMethods 从服务的 XxxServer 接口读取 RPC 方法
+func extractGrpcMethods(fset *token.FileSet, serviceName string, serverInterface *ast.InterfaceType, typeSpecs map[string]*ast.TypeSpec, fullMethodNames map[string]string) []*GrpcMethodDefinition {
+	methods := make([]*GrpcMethodDefinition, 0)
+	for _, field := range serverInterface.Methods.List {
+		funcType, ok := field.Type.(*ast.FuncType)
+		if !ok || len(field.Names) == 0 {
+			continue
+		}
+		// Skip the unexported mustEmbedUnimplementedXxxServer method
+		// 跳过未导出的 mustEmbedUnimplementedXxxServer 方法
+		name := field.Names[0].Name
+		if !token.IsExported(name) {
+			continue
+		}
+		method := newGrpcMethodDefinition(name, funcType, typeSpecs)
+		method.FullMethodName = fullMethodNames[serviceName+"/"+name]
+		method.Line = fset.Position(field.Pos()).Line
+		methods = append(methods, method)
+	}
+	return methods
+}
+
+// newGrpcMethodDefinition classifies the server side method signature
+// Unary: (context.Context, *Req) (*Resp, error)
+// Server streaming: (*Req, stream) error
+// Client and bidi streaming: (stream) error
+//
+// newGrpcMethodDefinition 对服务端方法签名进行分类
+// 一元：(context.Context, *Req) (*Resp, error)
+// 服务端流：(*Req, stream) error
+// 客户端流和双向流：(stream) error
+func newGrpcMethodDefinition(name string, funcType *ast.FuncType, typeSpecs map[string]*ast.TypeSpec) *GrpcMethodDefinition {
+	params := listFieldTypes(funcType.Params)
+	results := listFieldTypes(funcType.Results)
+
+	method := &GrpcMethodDefinition{Name: name}
+	switch {
+	case len(results) == 2 && len(params) == 2:
+		method.StreamingKind = GrpcStreamingUnary
+		method.RequestType = messageTypeName(params[1])
+		method.ResponseType = messageTypeName(results[0])
+	case len(params) == 2:
+		method.StreamingKind = GrpcStreamingServer
+		method.RequestType = messageTypeName(params[0])
+		_, sendType, _ := resolveGrpcStream(params[1], typeSpecs)
+		method.ResponseType = sendType
+	case len(params) == 1:
+		recvType, sendType, kind := resolveGrpcStream(params[0], typeSpecs)
+		method.StreamingKind = kind
+		method.RequestType = recvType
+		method.ResponseType = sendType
+	}
+	return method
+}
+
+// resolveGrpcStream reads the received and sent message types of a server side stream parameter
+// Generic forms carry the types as type arguments, legacy forms declare Send/SendAndClose/Recv methods
+//
+// resolveGrpcStream 读取服务端流参数接收和发送的消息类型
+// 泛型形式通过类型参数携带类型，旧版形式声明 Send/SendAndClose/Recv 方法
+func resolveGrpcStream(expr ast.Expr, typeSpecs map[string]*ast.TypeSpec) (recvType string, sendType string, kind GrpcStreamingKind) {
+	switch x := expr.(type) {
+	case *ast.IndexExpr: // grpc.ServerStreamingServer[Resp]
+		if types.ExprString(x.X) == "grpc.ServerStreamingServer" {
+			return "", messageTypeName(x.Index), GrpcStreamingServer
+		}
+	case *ast.IndexListExpr: // grpc.ClientStreamingServer[Req, Resp] and grpc.BidiStreamingServer[Req, Resp]
+		if len(x.Indices) == 2 {
+			switch types.ExprString(x.X) {
+			case "grpc.ClientStreamingServer":
+				return messageTypeName(x.Indices[0]), messageTypeName(x.Indices[1]), GrpcStreamingClient
+			case "grpc.BidiStreamingServer":
+				return messageTypeName(x.Indices[0]), messageTypeName(x.Indices[1]), GrpcStreamingBidi
+			}
+		}
+	case *ast.Ident: // Xxx_MethodServer, declared as an interface or as an alias of a generic form
+		typeSpec, ok := typeSpecs[x.Name]
+		if !ok {
+			return "", "", ""
+		}
+		if typeSpec.Assign.IsValid() {
+			return resolveGrpcStream(typeSpec.Type, typeSpecs)
+		}
+		interfaceType, ok := typeSpec.Type.(*ast.InterfaceType)
+		if !ok {
+			return "", "", ""
+		}
+		var sendAndClose bool
+		for _, field := range interfaceType.Methods.List {
+			funcType, ok := field.Type.(*ast.FuncType)
+			if !ok || len(field.Names) == 0 {
+				continue
+			}
+			switch field.Names[0].Name {
+			case "Send":
+				if params := listFieldTypes(funcType.Params); len(params) == 1 {
+					sendType = messageTypeName(params[0])
+				}
+			case "SendAndClose":
+				if params := listFieldTypes(funcType.Params); len(params) == 1 {
+					sendType = messageTypeName(params[0])
+					sendAndClose = true
+				}
+			case "Recv":
+				if results := listFieldTypes(funcType.Results); len(results) == 2 {
+					recvType = messageTypeName(results[0])
+				}
+			}
+		}
+		switch {
+		case sendAndClose:
+			kind = GrpcStreamingClient
+		case recvType != "":
+			kind = GrpcStreamingBidi
+		default:
+			kind = GrpcStreamingServer
+		}
+		return recvType, sendType, kind
+	}
+	return "", "", ""
+}
+
+// listFieldTypes expands a field list into one type expression per parameter or result
+//
+// listFieldTypes 将字段列表展开为每个参数或结果对应的类型表达式
+func listFieldTypes(fieldList *ast.FieldList) []ast.Expr {
+	var exprs []ast.Expr
+	if fieldList == nil {
+		return exprs
+	}
+	for _, field := range fieldList.List {
+		for range max(len(field.Names), 1) {
+			exprs = append(exprs, field.Type)
+		}
+	}
+	return exprs
+}
+
+// messageTypeName returns the message type name without the pointer mark
+//
+// messageTypeName 返回去掉指针标记的消息类型名称
+func messageTypeName(expr ast.Expr) string {
+	return strings.TrimPrefix(types.ExprString(expr), "*")
+}
+
+// collectFullMethodNames collects full method names keyed by "Service/Method"
+// Newer generators declare Xxx_Method_FullMethodName constants, older ones inline the string literals
+//
+// collectFullMethodNames 收集以 "Service/Method" 为键的完整方法名
+// 较新的生成器声明 Xxx_Method_FullMethodName 常量，旧版直接内联字符串字面量
+func collectFullMethodNames(astFile *ast.File) map[string]string {
+	fullMethodNames := map[string]string{}
+	ast.Inspect(astFile, func(node ast.Node) bool {
+		basicLit, ok := node.(*ast.BasicLit)
+		if !ok {
+			return true
+		}
+		value := stringLitValue(basicLit)
+		if !strings.HasPrefix(value, "/") {
+			return true
+		}
+		// Match "/package.Service/Method" and key it by the short service name
+		// 匹配 "/package.Service/Method" 并以短服务名作为键
+		parts := strings.Split(value[1:], "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return true
+		}
+		serviceName := parts[0][strings.LastIndex(parts[0], ".")+1:]
+		fullMethodNames[serviceName+"/"+parts[1]] = value
+		return true
+	})
+	return fullMethodNames
+}
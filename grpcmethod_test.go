@@ -0,0 +1,45 @@
+package astkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/astkratos"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/neatjson/neatjsons"
+)
+
+// TestListGrpcServicesMethods tests RPC method extraction from generic and legacy stream forms
+//
+// TestListGrpcServicesMethods 测试从泛型和旧版流形式中提取 RPC 方法
+func TestListGrpcServicesMethods(t *testing.T) {
+	services := astkratos.ListGrpcServices(demoApiRoot)
+	t.Log(neatjsons.S(services))
+	require.Len(t, services, 3)
+
+	// Echo uses the generic grpc stream types
+	// Echo 使用泛型 grpc 流类型
+	require.Equal(t, "Echo", services[0].Name)
+	require.Equal(t, []*astkratos.GrpcMethodDefinition{
+		{Name: "Ping", RequestType: "PingRequest", ResponseType: "PingReply", StreamingKind: astkratos.GrpcStreamingUnary, FullMethodName: "/echo.v1.Echo/Ping", Line: 105},
+		{Name: "Watch", RequestType: "WatchRequest", ResponseType: "WatchEvent", StreamingKind: astkratos.GrpcStreamingServer, FullMethodName: "/echo.v1.Echo/Watch", Line: 106},
+		{Name: "Collect", RequestType: "CollectItem", ResponseType: "CollectSummary", StreamingKind: astkratos.GrpcStreamingClient, FullMethodName: "/echo.v1.Echo/Collect", Line: 107},
+		{Name: "Chat", RequestType: "ChatMessage", ResponseType: "ChatMessage", StreamingKind: astkratos.GrpcStreamingBidi, FullMethodName: "/echo.v1.Echo/Chat", Line: 108},
+	}, services[0].Methods)
+
+	// Greeter is the default unary service
+	// Greeter 是默认的一元服务
+	require.Equal(t, "Greeter", services[1].Name)
+	require.Equal(t, []*astkratos.GrpcMethodDefinition{
+		{Name: "SayHello", RequestType: "HelloRequest", ResponseType: "HelloReply", StreamingKind: astkratos.GrpcStreamingUnary, FullMethodName: "/helloworld.v1.Greeter/SayHello", Line: 60},
+	}, services[1].Methods)
+
+	// Legacy uses the XxxStreamServer interfaces and inline full method names
+	// Legacy 使用 XxxStreamServer 接口和内联的完整方法名
+	require.Equal(t, "Legacy", services[2].Name)
+	require.Equal(t, []*astkratos.GrpcMethodDefinition{
+		{Name: "GetItem", RequestType: "GetItemRequest", ResponseType: "Item", StreamingKind: astkratos.GrpcStreamingUnary, FullMethodName: "/legacy.v1.Legacy/GetItem", Line: 149},
+		{Name: "Tail", RequestType: "TailRequest", ResponseType: "LogLine", StreamingKind: astkratos.GrpcStreamingServer, FullMethodName: "/legacy.v1.Legacy/Tail", Line: 150},
+		{Name: "Upload", RequestType: "Chunk", ResponseType: "UploadSummary", StreamingKind: astkratos.GrpcStreamingClient, FullMethodName: "/legacy.v1.Legacy/Upload", Line: 151},
+		{Name: "Sync", RequestType: "SyncRequest", ResponseType: "SyncReply", StreamingKind: astkratos.GrpcStreamingBidi, FullMethodName: "/legacy.v1.Legacy/Sync", Line: 152},
+	}, services[2].Methods)
+}
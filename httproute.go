@@ -0,0 +1,226 @@
+// Package astkratos HTTP route discovery: Routing tables from generated _http.pb.go files
+// Reads the RegisterXxxHTTPServer bodies that protoc-gen-go-http generates beside _grpc.pb.go
+// Resolves the verb, path template, operation constant, handler and RPC method of each route
+//
+// astkratos HTTP 路由发现：从生成的 _http.pb.go 文件中提取路由表
+// 读取 protoc-gen-go-http 在 _grpc.pb.go 旁生成的 RegisterXxxHTTPServer 函数体
+// 解析每条路由的 HTTP 方法、路径模板、操作常量、处理函数和 RPC 方法
+package astkratos
+
+import (
+	"go/ast"
+	"go/token"
+	"path"
+	"strings"
+
+	"github.com/yyle88/erero"
+)
+
+// HttpRouteDefinition represents an HTTP route registered by a generated RegisterXxxHTTPServer function
+//
+// HttpRouteDefinition 表示生成的 RegisterXxxHTTPServer 函数注册的 HTTP 路由
+type HttpRouteDefinition struct {
+	Service        string // Owning service name, such as Greeter // 所属服务名称，例如 Greeter
+	RpcMethod      string // RPC method called by the handler, such as SayHello // 处理函数调用的 RPC 方法，例如 SayHello
+	HttpMethod     string // HTTP verb, such as GET // HTTP 方法，例如 GET
+	PathTemplate   string // Path template, such as /helloworld/{name} // 路径模板，例如 /helloworld/{name}
+	Operation      string // Operation constant name, such as OperationGreeterSayHello // 操作常量名，例如 OperationGreeterSayHello
+	OperationValue string // Operation constant value, such as /helloworld.v1.Greeter/SayHello // 操作常量值，例如 /helloworld.v1.Greeter/SayHello
+	Handler        string // Handler function name, such as _Greeter_SayHello0_HTTP_Handler // 处理函数名，例如 _Greeter_SayHello0_HTTP_Handler
+	Package        string // Package name where the route is registered // 注册路由所在的包名
+	SrcPath        string // Source file path where the route is registered // 注册路由所在的源文件路径
+	Line           int    // Line number of the registration call // 注册调用所在的行号
+}
+
+// httpRouteVerbs lists the route methods of the kratos http.Router
+//
+// httpRouteVerbs 列出 kratos http.Router 的路由方法
+var httpRouteVerbs = map[string]bool{
+	"GET":     true,
+	"HEAD":    true,
+	"POST":    true,
+	"PUT":     true,
+	"PATCH":   true,
+	"DELETE":  true,
+	"CONNECT": true,
+	"OPTIONS": true,
+	"TRACE":   true,
+}
+
+// httpPbGoFile holds the HTTP definitions extracted from one _http.pb.go file
+//
+// httpPbGoFile 保存从单个 _http.pb.go 文件提取的 HTTP 定义
+type httpPbGoFile struct {
+	srcPath  string                 // Absolute path of the file // 文件的绝对路径
+	routes   []*HttpRouteDefinition // Routes in registration order // 按注册顺序排列的路由
+	services []string               // Services with a RegisterXxxHTTPServer function in the file // 文件中带有 RegisterXxxHTTPServer 函数的服务
+}
+
+// analyzeHttpPbGoFile parses the _http.pb.go file and collects the routes of its RegisterXxxHTTPServer functions
+//
+// analyzeHttpPbGoFile 解析 _http.pb.go 文件并收集其 RegisterXxxHTTPServer 函数中的路由
+func analyzeHttpPbGoFile(path string) (*httpPbGoFile, error) {
+	goFile, err := parseGoFile(path)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	astFile := goFile.astFile
+
+	// Collect the operation constants and handler functions used by the routes
+	// 收集路由使用的操作常量和处理函数
+	constValues := map[string]string{}
+	funcDecls := map[string]*ast.FuncDecl{}
+	for _, decl := range astFile.Decls {
+		switch x := decl.(type) {
+		case *ast.GenDecl:
+			if x.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range x.Specs {
+				valueSpec := spec.(*ast.ValueSpec)
+				for idx, name := range valueSpec.Names {
+					if idx < len(valueSpec.Values) {
+						constValues[name.Name] = stringLitValue(valueSpec.Values[idx])
+					}
+				}
+			}
+		case *ast.FuncDecl:
+			if x.Recv == nil {
+				funcDecls[x.Name.Name] = x
+			}
+		}
+	}
+
+	imports := importPaths(astFile)
+	result := &httpPbGoFile{srcPath: goFile.srcPath, routes: make([]*HttpRouteDefinition, 0)}
+	for _, decl := range astFile.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Recv != nil || funcDecl.Body == nil {
+			continue
+		}
+		name := funcDecl.Name.Name
+		if !strings.HasPrefix(name, "Register") || !strings.HasSuffix(name, "HTTPServer") {
+			continue
+		}
+		serviceName := strings.TrimSuffix(strings.TrimPrefix(name, "Register"), "HTTPServer")
+		result.services = append(result.services, serviceName)
+
+		// Track the route groups, such as r := s.Route("/")
+		// 跟踪路由分组，例如 r := s.Route("/")
+		routePrefixes := map[string]string{}
+		ast.Inspect(funcDecl.Body, func(node ast.Node) bool {
+			switch x := node.(type) {
+			case *ast.AssignStmt:
+				if len(x.Lhs) == 1 && len(x.Rhs) == 1 {
+					if ident, ok := x.Lhs[0].(*ast.Ident); ok {
+						if callExpr, ok := x.Rhs[0].(*ast.CallExpr); ok && selectorName(callExpr.Fun) == "Route" && len(callExpr.Args) > 0 {
+							routePrefixes[ident.Name] = stringLitValue(callExpr.Args[0])
+						}
+					}
+				}
+			case *ast.CallExpr:
+				selectorExpr, ok := x.Fun.(*ast.SelectorExpr)
+				if !ok {
+					return true
+				}
+				receiver, ok := selectorExpr.X.(*ast.Ident)
+				if !ok {
+					return true
+				}
+				prefix, ok := routePrefixes[receiver.Name]
+				if !ok {
+					return true
+				}
+				var httpMethod string
+				var args []ast.Expr
+				switch {
+				case httpRouteVerbs[selectorExpr.Sel.Name] && len(x.Args) == 2:
+					httpMethod, args = selectorExpr.Sel.Name, x.Args
+				case selectorExpr.Sel.Name == "Handle" && len(x.Args) == 3:
+					httpMethod, args = stringLitValue(x.Args[0]), x.Args[1:]
+				default:
+					return true
+				}
+				route := &HttpRouteDefinition{
+					Service:      serviceName,
+					HttpMethod:   httpMethod,
+					PathTemplate: joinRoutePath(prefix, stringLitValue(args[0])),
+					Package:      astFile.Name.Name,
+					SrcPath:      goFile.srcPath,
+					Line:         goFile.fset.Position(x.Pos()).Line,
+				}
+				if handlerCall, ok := args[1].(*ast.CallExpr); ok {
+					if handlerIdent, ok := handlerCall.Fun.(*ast.Ident); ok {
+						route.Handler = handlerIdent.Name
+					}
+				}
+				if handlerDecl, ok := funcDecls[route.Handler]; ok {
+					route.Operation, route.RpcMethod = inspectHttpHandler(handlerDecl, imports)
+					route.OperationValue = constValues[route.Operation]
+				}
+				result.routes = append(result.routes, route)
+			}
+			return true
+		})
+	}
+	return result, nil
+}
+
+// inspectHttpHandler reads the operation set by http.SetOperation and the srv method called in the handler
+// The Kratos http package is resolved through the imports of the file, so a renamed import still matches
+//
+// inspectHttpHandler 读取处理函数中 http.SetOperation 设置的操作以及调用的 srv 方法
+// 通过文件的导入解析 Kratos http 包，使重命名的导入同样能够匹配
+func inspectHttpHandler(funcDecl *ast.FuncDecl, imports map[string]string) (operation string, rpcMethod string) {
+	if funcDecl.Body == nil {
+		return "", ""
+	}
+	serverParam := ""
+	if params := funcDecl.Type.Params.List; len(params) > 0 && len(params[0].Names) > 0 {
+		serverParam = params[0].Names[0].Name
+	}
+	ast.Inspect(funcDecl.Body, func(node ast.Node) bool {
+		callExpr, ok := node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		selectorExpr, ok := callExpr.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		receiver, ok := selectorExpr.X.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		switch {
+		case imports[receiver.Name] == KratosModulePath+"/transport/http" && selectorExpr.Sel.Name == "SetOperation" && len(callExpr.Args) == 2:
+			if ident, ok := callExpr.Args[1].(*ast.Ident); ok {
+				operation = ident.Name
+			}
+		case receiver.Name == serverParam && rpcMethod == "":
+			rpcMethod = selectorExpr.Sel.Name
+		}
+		return true
+	})
+	return operation, rpcMethod
+}
+
+// selectorName returns the selected name of a selector expression, blank otherwise
+//
+// selectorName 返回选择器表达式中被选择的名称，否则返回空字符串
+func selectorName(expr ast.Expr) string {
+	if selectorExpr, ok := expr.(*ast.SelectorExpr); ok {
+		return selectorExpr.Sel.Name
+	}
+	return ""
+}
+
+// joinRoutePath joins the route group prefix and the route path
+//
+// joinRoutePath 拼接路由分组前缀和路由路径
+func joinRoutePath(prefix string, routePath string) string {
+	if prefix == "" || prefix == "/" {
+		return routePath
+	}
+	return path.Join(prefix, routePath)
+}
@@ -0,0 +1,110 @@
+package astkratos_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/orzkratos/astkratos"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/neatjson/neatjsons"
+	"github.com/yyle88/rese"
+	"github.com/yyle88/runpath"
+)
+
+// TestListHttpRoutes tests route discovery from RegisterXxxHTTPServer bodies
+//
+// TestListHttpRoutes 测试从 RegisterXxxHTTPServer 函数体中发现路由
+func TestListHttpRoutes(t *testing.T) {
+	routes := astkratos.ListHttpRoutes(demoApiRoot)
+	t.Log(neatjsons.S(routes))
+
+	srcPath := runpath.PARENT.Join("testdata", "demokratos", "api", "helloworld", "v1", "greeter_http.pb.go")
+	require.Equal(t, []*astkratos.HttpRouteDefinition{
+		{
+			Service:        "Greeter",
+			RpcMethod:      "SayHello",
+			HttpMethod:     "GET",
+			PathTemplate:   "/helloworld/{name}",
+			Operation:      "OperationGreeterSayHello",
+			OperationValue: "/helloworld.v1.Greeter/SayHello",
+			Handler:        "_Greeter_SayHello0_HTTP_Handler",
+			Package:        "v1",
+			SrcPath:        srcPath,
+			Line:           31,
+		},
+		{
+			Service:        "Greeter",
+			RpcMethod:      "SayHello",
+			HttpMethod:     "POST",
+			PathTemplate:   "/helloworld",
+			Operation:      "OperationGreeterSayHello",
+			OperationValue: "/helloworld.v1.Greeter/SayHello",
+			Handler:        "_Greeter_SayHello1_HTTP_Handler",
+			Package:        "v1",
+			SrcPath:        srcPath,
+			Line:           32,
+		},
+	}, routes)
+}
+
+// TestListHttpRoutes_ImportAlias tests that the operations are found when the Kratos http package is imported under another name
+//
+// TestListHttpRoutes_ImportAlias 测试以其他名称导入 Kratos http 包时仍能找到操作
+func TestListHttpRoutes_ImportAlias(t *testing.T) {
+	root := t.TempDir()
+	source := string(rese.V1(os.ReadFile(filepath.Join(demoApiRoot, "helloworld", "v1", "greeter_http.pb.go"))))
+	source = strings.ReplaceAll(source, "http.", "khttp.")
+	source = strings.Replace(source, "\thttp \"github.com", "\tkhttp \"github.com", 1)
+	must.Done(os.WriteFile(filepath.Join(root, "greeter_http.pb.go"), []byte(source), 0644))
+
+	routes := astkratos.ListHttpRoutes(root)
+	t.Log(neatjsons.S(routes))
+	require.Len(t, routes, 2)
+	for _, route := range routes {
+		require.Equal(t, "OperationGreeterSayHello", route.Operation)
+		require.Equal(t, "/helloworld.v1.Greeter/SayHello", route.OperationValue)
+		require.Equal(t, "SayHello", route.RpcMethod)
+	}
+}
+
+// TestListHttpRoutes_Unread tests that broken HTTP and error files fail their own listings alone
+//
+// TestListHttpRoutes_Unread 测试损坏的 HTTP 和错误文件只会让其自身的列表失败
+func TestListHttpRoutes_Unread(t *testing.T) {
+	root := t.TempDir()
+	apiRoot := filepath.Join(root, "api")
+	must.Done(os.MkdirAll(filepath.Join(apiRoot, "echo", "v1"), 0755))
+	must.Done(os.WriteFile(filepath.Join(root, "go.mod"), []byte("module shop\n\ngo 1.22\n"), 0644))
+	must.Done(os.WriteFile(filepath.Join(apiRoot, "echo", "v1", "echo_grpc.pb.go"), rese.V1(os.ReadFile(filepath.Join(demoApiRoot, "echo", "v1", "echo_grpc.pb.go"))), 0644))
+	httpPath := filepath.Join(apiRoot, "echo", "v1", "echo_http.pb.go")
+	must.Done(os.WriteFile(httpPath, []byte("package v1\n\nfunc RegisterEchoHTTPServer(\n"), 0644))
+	errorsPath := filepath.Join(apiRoot, "echo", "v1", "echo_errors.pb.go")
+	must.Done(os.WriteFile(errorsPath, []byte("package v1\n\nfunc IsEchoFailed(\n"), 0644))
+
+	clients, err := astkratos.ListGrpcClientsE(apiRoot)
+	require.NoError(t, err)
+	require.Equal(t, []string{"EchoClient"}, collectNames(clients))
+
+	_, err = astkratos.ListHttpRoutesE(apiRoot)
+	require.ErrorContains(t, err, httpPath)
+	_, err = astkratos.ListErrorReasonsE(apiRoot)
+	require.ErrorContains(t, err, errorsPath)
+
+	drifts, err := astkratos.CheckProtoDriftE(apiRoot)
+	require.NoError(t, err)
+	require.Len(t, drifts, 1)
+	require.Equal(t, astkratos.ProtoDriftUnread, drifts[0].Kind)
+	require.Equal(t, httpPath, drifts[0].GeneratedPosition.Path)
+
+	report, err := astkratos.AnalyzeProjectE(root)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Echo"}, collectNames(report.Services))
+	require.Empty(t, report.HttpRoutes)
+	require.Len(t, report.UnreadFiles, 2)
+	require.Equal(t, httpPath, report.UnreadFiles[0].Path)
+	require.Equal(t, errorsPath, report.UnreadFiles[1].Path)
+	require.Len(t, report.Upgrade.UnreadFiles, 2)
+}
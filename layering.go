@@ -0,0 +1,142 @@
+// Package astkratos layering: Import checks against the Kratos layering policy
+// Reads the imports of each package in the layers and finds the imports the policy forbids
+// Ships with the default Kratos policy where biz never imports data or service
+// Custom policies name their own layers and forbidden imports
+//
+// astkratos 分层：根据 Kratos 分层策略检查导入
+// 读取各层中每个包的导入，并找出策略禁止的导入
+// 内置默认的 Kratos 策略，其中 biz 不得导入 data 或 service
+// 自定义策略可以指定自己的层和禁止的导入
+package astkratos
+
+import (
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/yyle88/erero"
+)
+
+// LayerDefinition names a layer and the directory holding it
+//
+// LayerDefinition 指定层的名称及其所在目录
+type LayerDefinition struct {
+	Name string // Layer name, such as biz // 层名称，例如 biz
+	Dir  string // Directory relative to the project root, such as internal/biz // 相对项目根目录的目录，例如 internal/biz
+}
+
+// LayeringPolicy lists the layers and the layers each one must not import
+//
+// LayeringPolicy 列出各层以及每层不得导入的层
+type LayeringPolicy struct {
+	Layers    []*LayerDefinition  // Layers in check order // 按检查顺序排列的层
+	Forbidden map[string][]string // Forbidden layers keyed by the importing layer // 以导入方层为键的禁止导入层
+}
+
+// DefaultLayeringPolicy returns the Kratos layout policy
+// Service depends on biz, data implements biz, biz imports neither data nor service
+// Server wires the services and never reaches into biz or data
+//
+// DefaultLayeringPolicy 返回 Kratos 布局的分层策略
+// service 依赖 biz，data 实现 biz，biz 既不导入 data 也不导入 service
+// server 组装 service，不直接访问 biz 或 data
+func DefaultLayeringPolicy() *LayeringPolicy {
+	return &LayeringPolicy{
+		Layers: []*LayerDefinition{
+			{Name: "biz", Dir: "internal/biz"},
+			{Name: "data", Dir: "internal/data"},
+			{Name: "service", Dir: "internal/service"},
+			{Name: "server", Dir: "internal/server"},
+		},
+		Forbidden: map[string][]string{
+			"biz":     {"data", "service", "server"},
+			"data":    {"service", "server"},
+			"service": {"data", "server"},
+			"server":  {"biz", "data"},
+		},
+	}
+}
+
+// LayerViolation represents an import the policy forbids
+//
+// LayerViolation 表示策略禁止的一次导入
+type LayerViolation struct {
+	Layer         string   // Importing layer, such as biz // 导入方的层，例如 biz
+	ImportedLayer string   // Imported layer, such as data // 被导入的层，例如 data
+	Package       string   // Importing package name // 导入方的包名
+	ImportPath    string   // Forbidden import path // 被禁止的导入路径
+	SrcPath       string   // File containing the import // 包含该导入的文件
+	Position      Position // Position of the import spec // 导入声明的位置
+}
+
+// checkLayering finds the imports of the layer packages that the policy forbids
+//
+// checkLayering 查找各层包中被策略禁止的导入
+func checkLayering(projectRoot string, modulePath string, policy *LayeringPolicy) ([]*LayerViolation, error) {
+	projectRoot, err := filepath.Abs(projectRoot)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	violations := make([]*LayerViolation, 0)
+	for _, layer := range policy.Layers {
+		forbidden := policy.Forbidden[layer.Name]
+		if len(forbidden) == 0 {
+			continue
+		}
+		packages, err := loadGoPackages(filepath.Join(projectRoot, filepath.FromSlash(layer.Dir)))
+		if err != nil {
+			return nil, erero.Wro(err)
+		}
+		for _, pkg := range packages {
+			relDir, err := filepath.Rel(projectRoot, pkg.dir)
+			if err != nil {
+				return nil, erero.Wro(err)
+			}
+			// Leave the packages of a nested layer to that layer
+			// 嵌套层中的包交给该层检查
+			if policy.matchLayer(modulePath, modulePath+"/"+filepath.ToSlash(relDir)) != layer.Name {
+				continue
+			}
+			for _, goFile := range pkg.files {
+				for _, importSpec := range goFile.astFile.Imports {
+					importPath, err := strconv.Unquote(importSpec.Path.Value)
+					if err != nil {
+						return nil, erero.Wro(err)
+					}
+					importedLayer := policy.matchLayer(modulePath, importPath)
+					if importedLayer == "" || importedLayer == layer.Name || !slices.Contains(forbidden, importedLayer) {
+						continue
+					}
+					violations = append(violations, &LayerViolation{
+						Layer:         layer.Name,
+						ImportedLayer: importedLayer,
+						Package:       pkg.name,
+						ImportPath:    importPath,
+						SrcPath:       goFile.srcPath,
+						Position:      Position{Path: goFile.srcPath, Line: goFile.fset.Position(importSpec.Pos()).Line},
+					})
+				}
+			}
+		}
+	}
+	return violations, nil
+}
+
+// matchLayer returns the layer holding the import path, preferring the deepest directory
+//
+// matchLayer 返回包含该导入路径的层，优先选择最深的目录
+func (policy *LayeringPolicy) matchLayer(modulePath string, importPath string) string {
+	var name string
+	var depth int
+	for _, layer := range policy.Layers {
+		layerPath := modulePath + "/" + strings.Trim(layer.Dir, "/")
+		if importPath != layerPath && !strings.HasPrefix(importPath, layerPath+"/") {
+			continue
+		}
+		if len(layerPath) > depth {
+			name, depth = layer.Name, len(layerPath)
+		}
+	}
+	return name
+}
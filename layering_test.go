@@ -0,0 +1,66 @@
+package astkratos_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/orzkratos/astkratos"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/runpath"
+)
+
+// TestCheckLayering tests that the demo project follows the default Kratos layering policy
+//
+// TestCheckLayering 测试示例项目遵循默认的 Kratos 分层策略
+func TestCheckLayering(t *testing.T) {
+	violations := astkratos.CheckLayering(runpath.PARENT.Join("testdata", "demokratos"))
+	require.Empty(t, violations)
+}
+
+// TestCheckLayeringWithPolicy tests forbidden imports under the default and a custom policy
+//
+// TestCheckLayeringWithPolicy 测试默认策略和自定义策略下被禁止的导入
+func TestCheckLayeringWithPolicy(t *testing.T) {
+	root := t.TempDir()
+	must.Done(os.WriteFile(filepath.Join(root, "go.mod"), []byte("module demo\n\ngo 1.25.0\n"), 0644))
+	bizRoot := filepath.Join(root, "internal", "biz")
+	must.Done(os.MkdirAll(bizRoot, 0755))
+	must.Done(os.WriteFile(filepath.Join(bizRoot, "biz.go"), []byte(`package biz
+
+import (
+	"context"
+
+	"demo/internal/data"
+	"demo/pkg/util"
+)
+
+func Load(ctx context.Context) (*data.Data, error) {
+	return nil, util.Check()
+}
+`), 0644))
+	dataRoot := filepath.Join(root, "internal", "data")
+	must.Done(os.MkdirAll(dataRoot, 0755))
+	must.Done(os.WriteFile(filepath.Join(dataRoot, "data.go"), []byte(`package data
+
+type Data struct{}
+`), 0644))
+
+	violations := astkratos.CheckLayering(root)
+	require.Len(t, violations, 1)
+	require.Equal(t, "biz", violations[0].Layer)
+	require.Equal(t, "data", violations[0].ImportedLayer)
+	require.Equal(t, "demo/internal/data", violations[0].ImportPath)
+	require.Equal(t, filepath.Join(root, "internal", "biz", "biz.go"), violations[0].SrcPath)
+	require.Equal(t, 6, violations[0].Position.Line)
+
+	policy := astkratos.DefaultLayeringPolicy()
+	policy.Layers = append(policy.Layers, &astkratos.LayerDefinition{Name: "pkg", Dir: "pkg"})
+	policy.Forbidden["biz"] = []string{"pkg"}
+	violations = astkratos.CheckLayeringWithPolicy(root, policy)
+	require.Len(t, violations, 1)
+	require.Equal(t, "pkg", violations[0].ImportedLayer)
+	require.Equal(t, "demo/pkg/util", violations[0].ImportPath)
+	require.Equal(t, 7, violations[0].Position.Line)
+}
@@ -1,21 +1,24 @@
 // Package astkratos module utilities: Advanced Go module information extraction and analysis
 // Provides comprehensive module metadata parsing and toolchain version resolution capabilities
-// Features intelligent go.mod parsing with JSON-based configuration analysis workflows
-// Optimized in Kratos project with dependencies and version checking
+// Parses go.mod natively without a Go toolchain, keeping every directive with its comments and line
+// Keeps the go mod edit -json path as an option when the go command is preferred
 //
 // astkratos 模块工具：高级 Go 模块信息提取和分析
 // 提供全面的模块元数据解析和工具链版本解析功能
-// 具有智能的 go.mod 解析和基于 JSON 的配置分析工作流程
-// 针对 Kratos 项目依赖分析和版本兼容性验证优化
+// 无需 Go 工具链即可原生解析 go.mod，保留每条指令及其注释和行号
+// 在需要使用 go 命令时保留 go mod edit -json 方式作为选项
 package astkratos
 
 import (
 	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
 
-	"github.com/yyle88/must"
+	"github.com/yyle88/erero"
 	"github.com/yyle88/osexec"
-	"github.com/yyle88/rese"
 	"github.com/yyle88/tern/zerotern"
+	"golang.org/x/mod/modfile"
 )
 
 // Module represents the core module information from go.mod
@@ -24,7 +27,10 @@ import (
 // Module 代表来自 go.mod 的核心模块信息
 // 包含基本的模块路径和识别数据
 type Module struct {
-	Path string `json:"Path"` // Module path name // 模块路径名称
+	Path       string   `json:"Path"`                 // Module path name // 模块路径名称
+	Deprecated string   `json:"Deprecated,omitempty"` // Deprecation message if any // 弃用说明（如有）
+	Line       int      `json:"Line,omitempty"`       // Line in go.mod, native parsing only // 在 go.mod 中的行号，仅原生解析时提供
+	Comments   []string `json:"Comments,omitempty"`   // Attached comments, native parsing only // 附带的注释，仅原生解析时提供
 }
 
 // Require represents a module dependencies with version and indirect status
@@ -33,9 +39,70 @@ type Module struct {
 // Require 代表具有版本和间接状态的模块依赖
 // 包含分析工作流程中的全面依赖元数据
 type Require struct {
-	Path     string `json:"Path"`     // Required module path // 依赖模块路径
-	Version  string `json:"Version"`  // Required version // 所需版本
-	Indirect bool   `json:"Indirect"` // If this is an indirect import // 是否为间接依赖
+	Path     string   `json:"Path"`               // Required module path // 依赖模块路径
+	Version  string   `json:"Version"`            // Required version // 所需版本
+	Indirect bool     `json:"Indirect"`           // If this is an indirect import // 是否为间接依赖
+	Line     int      `json:"Line,omitempty"`     // Line in go.mod, native parsing only // 在 go.mod 中的行号，仅原生解析时提供
+	Comments []string `json:"Comments,omitempty"` // Attached comments, native parsing only // 附带的注释，仅原生解析时提供
+}
+
+// Godebug represents a godebug key=value directive
+//
+// Godebug 表示 godebug key=value 指令
+type Godebug struct {
+	Key      string   `json:"Key"`                // Setting name // 设置名称
+	Value    string   `json:"Value"`              // Setting value // 设置值
+	Line     int      `json:"Line,omitempty"`     // Line in go.mod or go.work, native parsing only // 在 go.mod 或 go.work 中的行号，仅原生解析时提供
+	Comments []string `json:"Comments,omitempty"` // Attached comments, native parsing only // 附带的注释，仅原生解析时提供
+}
+
+// Exclude represents an exclude directive
+//
+// Exclude 表示 exclude 指令
+type Exclude struct {
+	Path     string   `json:"Path"`               // Excluded module path // 被排除的模块路径
+	Version  string   `json:"Version"`            // Excluded version // 被排除的版本
+	Line     int      `json:"Line,omitempty"`     // Line in go.mod, native parsing only // 在 go.mod 中的行号，仅原生解析时提供
+	Comments []string `json:"Comments,omitempty"` // Attached comments, native parsing only // 附带的注释，仅原生解析时提供
+}
+
+// Replace represents a replace directive of go.mod
+//
+// Replace 表示 go.mod 中的 replace 指令
+type Replace struct {
+	Old      ModuleVersion `json:"Old"`                // Replaced module // 被替换的模块
+	New      ModuleVersion `json:"New"`                // Replacement module or local directory // 替换的模块或本地目录
+	Line     int           `json:"Line,omitempty"`     // Line in go.mod, native parsing only // 在 go.mod 中的行号，仅原生解析时提供
+	Comments []string      `json:"Comments,omitempty"` // Attached comments, native parsing only // 附带的注释，仅原生解析时提供
+}
+
+// Retract represents a retract directive, Low equals High when a single version is retracted
+//
+// Retract 表示 retract 指令，撤回单个版本时 Low 与 High 相同
+type Retract struct {
+	Low       string   `json:"Low"`                // Lower bound of the interval // 区间下界
+	High      string   `json:"High"`               // Upper bound of the interval // 区间上界
+	Rationale string   `json:"Rationale"`          // Reason from the comment // 来自注释的原因
+	Line      int      `json:"Line,omitempty"`     // Line in go.mod, native parsing only // 在 go.mod 中的行号，仅原生解析时提供
+	Comments  []string `json:"Comments,omitempty"` // Attached comments, native parsing only // 附带的注释，仅原生解析时提供
+}
+
+// Tool represents a tool directive
+//
+// Tool 表示 tool 指令
+type Tool struct {
+	Path     string   `json:"Path"`               // Tool package path // 工具包路径
+	Line     int      `json:"Line,omitempty"`     // Line in go.mod, native parsing only // 在 go.mod 中的行号，仅原生解析时提供
+	Comments []string `json:"Comments,omitempty"` // Attached comments, native parsing only // 附带的注释，仅原生解析时提供
+}
+
+// Ignore represents an ignore directive
+//
+// Ignore 表示 ignore 指令
+type Ignore struct {
+	Path     string   `json:"Path"`               // Ignored directory // 被忽略的目录
+	Line     int      `json:"Line,omitempty"`     // Line in go.mod, native parsing only // 在 go.mod 中的行号，仅原生解析时提供
+	Comments []string `json:"Comments,omitempty"` // Attached comments, native parsing only // 附带的注释，仅原生解析时提供
 }
 
 // ModuleInfo provides comprehensive Go module analysis with toolchain information
@@ -49,7 +116,13 @@ type ModuleInfo struct {
 	Module    *Module    `json:"Module"`    // Core module information // 核心模块信息
 	Go        string     `json:"Go"`        // Go version requirement // Go 版本要求
 	Toolchain string     `json:"Toolchain"` // Toolchain version if specified // 指定的工具链版本
+	Godebug   []*Godebug `json:"Godebug"`   // Godebug settings // godebug 设置
 	Require   []*Require `json:"Require"`   // Module dependencies list // 模块依赖列表
+	Exclude   []*Exclude `json:"Exclude"`   // Excluded module versions // 被排除的模块版本
+	Replace   []*Replace `json:"Replace"`   // Module replacements // 模块替换
+	Retract   []*Retract `json:"Retract"`   // Retracted versions // 被撤回的版本
+	Tool      []*Tool    `json:"Tool"`      // Tool dependencies // 工具依赖
+	Ignore    []*Ignore  `json:"Ignore"`    // Ignored directories // 被忽略的目录
 }
 
 // GetToolchainVersion resolves the effective Go toolchain version
@@ -65,18 +138,138 @@ func (a *ModuleInfo) GetToolchainVersion() string {
 	})
 }
 
+// GetKratosVersion returns the required version of github.com/go-kratos/kratos/v2, blank when not required
+// A replacement with a version takes precedence, local directory replacements keep the required version
+//
+// GetKratosVersion 返回 github.com/go-kratos/kratos/v2 的依赖版本，未依赖时返回空
+// 带版本的替换优先，本地目录替换保留依赖版本
+func (a *ModuleInfo) GetKratosVersion() string {
+	var version string
+	for _, require := range a.Require {
+		if require.Path == KratosModulePath {
+			version = require.Version
+		}
+	}
+	for _, replace := range a.Replace {
+		if replace.Old.Path == KratosModulePath && (replace.Old.Version == "" || replace.Old.Version == version) && replace.New.Version != "" {
+			version = replace.New.Version
+		}
+	}
+	return version
+}
+
 // GetModuleInfo extracts comprehensive module information from the specified project
-// Executes go mod edit command to retrieve JSON-formatted module metadata
-// Parses and unmarshals module configuration including dependencies and toolchain info
-// Returns complete ModuleInfo structure using assertion-style error handling
+// Parses the go.mod governing the project path natively, so no Go toolchain is needed
+// Fills every directive along with the comments and line of each entry
 //
 // GetModuleInfo 从指定项目提取全面的模块信息
-// 执行 go mod edit 命令检索 JSON 格式的模块元数据
-// 解析并反序列化包括依赖和工具链信息的模块配置
-// 使用断言风格的错误处理返回完整的 ModuleInfo 结构
+// 原生解析管理该项目路径的 go.mod，因此不需要 Go 工具链
+// 填充每条指令以及每个条目的注释和行号
 func GetModuleInfo(projectPath string) (*ModuleInfo, error) {
-	output := rese.V1(osexec.ExecInPath(projectPath, "go", "mod", "edit", "-json"))
+	absPath, err := filepath.Abs(projectPath)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	moduleRoot := findModuleRoot(absPath)
+	if moduleRoot == "" {
+		return nil, erero.Errorf("no go.mod found at or above %s", absPath)
+	}
+	return ParseModuleFile(filepath.Join(moduleRoot, "go.mod"))
+}
+
+// GetModuleInfoWithGoCommand extracts module information through go mod edit -json
+// Keeps the behavior of the go command, such as honoring GOFLAGS, at the cost of needing a toolchain
+//
+// GetModuleInfoWithGoCommand 通过 go mod edit -json 提取模块信息
+// 保留 go 命令的行为（例如遵循 GOFLAGS），代价是需要 Go 工具链
+func GetModuleInfoWithGoCommand(projectPath string) (*ModuleInfo, error) {
+	output, err := osexec.ExecInPath(projectPath, "go", "mod", "edit", "-json")
+	if err != nil {
+		return nil, erero.Wrapf(err, "run go mod edit in %s", projectPath)
+	}
 	var moduleInfo ModuleInfo
-	must.Done(json.Unmarshal(output, &moduleInfo))
+	if err := json.Unmarshal(output, &moduleInfo); err != nil {
+		return nil, erero.Wro(err)
+	}
 	return &moduleInfo, nil
 }
+
+// ParseModuleFile parses the go.mod file at the path without running the go command
+//
+// ParseModuleFile 在不运行 go 命令的情况下解析指定路径的 go.mod 文件
+func ParseModuleFile(path string) (*ModuleInfo, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	file, err := modfile.Parse(path, content, nil)
+	if err != nil {
+		return nil, erero.Wrapf(err, "parse %s", path)
+	}
+
+	moduleInfo := &ModuleInfo{
+		Module:  &Module{},
+		Godebug: make([]*Godebug, 0, len(file.Godebug)),
+		Require: make([]*Require, 0, len(file.Require)),
+		Exclude: make([]*Exclude, 0, len(file.Exclude)),
+		Replace: make([]*Replace, 0, len(file.Replace)),
+		Retract: make([]*Retract, 0, len(file.Retract)),
+		Tool:    make([]*Tool, 0, len(file.Tool)),
+		Ignore:  make([]*Ignore, 0, len(file.Ignore)),
+	}
+	if file.Module != nil {
+		moduleInfo.Module = &Module{
+			Path:       file.Module.Mod.Path,
+			Deprecated: file.Module.Deprecated,
+			Line:       file.Module.Syntax.Start.Line,
+			Comments:   modLineComments(file.Module.Syntax),
+		}
+	}
+	if file.Go != nil {
+		moduleInfo.Go = file.Go.Version
+	}
+	if file.Toolchain != nil {
+		moduleInfo.Toolchain = file.Toolchain.Name
+	}
+	for _, item := range file.Godebug {
+		moduleInfo.Godebug = append(moduleInfo.Godebug, &Godebug{Key: item.Key, Value: item.Value, Line: item.Syntax.Start.Line, Comments: modLineComments(item.Syntax)})
+	}
+	for _, item := range file.Require {
+		moduleInfo.Require = append(moduleInfo.Require, &Require{Path: item.Mod.Path, Version: item.Mod.Version, Indirect: item.Indirect, Line: item.Syntax.Start.Line, Comments: modLineComments(item.Syntax)})
+	}
+	for _, item := range file.Exclude {
+		moduleInfo.Exclude = append(moduleInfo.Exclude, &Exclude{Path: item.Mod.Path, Version: item.Mod.Version, Line: item.Syntax.Start.Line, Comments: modLineComments(item.Syntax)})
+	}
+	for _, item := range file.Replace {
+		moduleInfo.Replace = append(moduleInfo.Replace, &Replace{
+			Old:      ModuleVersion{Path: item.Old.Path, Version: item.Old.Version},
+			New:      ModuleVersion{Path: item.New.Path, Version: item.New.Version},
+			Line:     item.Syntax.Start.Line,
+			Comments: modLineComments(item.Syntax),
+		})
+	}
+	for _, item := range file.Retract {
+		moduleInfo.Retract = append(moduleInfo.Retract, &Retract{Low: item.Low, High: item.High, Rationale: item.Rationale, Line: item.Syntax.Start.Line, Comments: modLineComments(item.Syntax)})
+	}
+	for _, item := range file.Tool {
+		moduleInfo.Tool = append(moduleInfo.Tool, &Tool{Path: item.Path, Line: item.Syntax.Start.Line, Comments: modLineComments(item.Syntax)})
+	}
+	for _, item := range file.Ignore {
+		moduleInfo.Ignore = append(moduleInfo.Ignore, &Ignore{Path: item.Path, Line: item.Syntax.Start.Line, Comments: modLineComments(item.Syntax)})
+	}
+	return moduleInfo, nil
+}
+
+// modLineComments returns the comments before and after the go.mod line without the slashes
+//
+// modLineComments 返回 go.mod 行前后的注释，不含斜杠
+func modLineComments(line *modfile.Line) []string {
+	if line == nil {
+		return nil
+	}
+	var comments []string
+	for _, comment := range append(line.Before, line.Suffix...) {
+		comments = append(comments, strings.TrimSpace(strings.TrimPrefix(comment.Token, "//")))
+	}
+	return comments
+}
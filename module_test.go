@@ -1,10 +1,13 @@
 package astkratos_test
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/orzkratos/astkratos"
 	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
 	"github.com/yyle88/neatjson/neatjsons"
 	"github.com/yyle88/runpath"
 )
@@ -18,3 +21,95 @@ func TestGetModuleInfo(t *testing.T) {
 	t.Log(neatjsons.S(moduleInfo))
 	require.Equal(t, "go1.25.0", moduleInfo.GetToolchainVersion())
 }
+
+// TestParseModuleFile tests every go.mod directive along with comments and lines
+//
+// TestParseModuleFile 测试 go.mod 的每条指令以及注释和行号
+func TestParseModuleFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "go.mod")
+	must.Done(os.WriteFile(path, []byte(`// Deprecated: use example.com/shop/v2 instead.
+module example.com/shop
+
+go 1.25.0
+
+toolchain go1.25.1
+
+godebug default=go1.21
+
+require (
+	// kratos framework
+	github.com/go-kratos/kratos/v2 v2.8.0
+	golang.org/x/mod v0.30.0 // indirect
+)
+
+exclude github.com/go-kratos/kratos/v2 v2.7.0
+
+replace example.com/common v1.0.0 => ../common
+
+retract [v1.0.0, v1.0.5] // broken build
+
+tool github.com/google/wire/cmd/wire
+
+ignore ./node_modules
+`), 0644))
+
+	moduleInfo, err := astkratos.ParseModuleFile(path)
+	require.NoError(t, err)
+	t.Log(neatjsons.S(moduleInfo))
+
+	require.Equal(t, "example.com/shop", moduleInfo.Module.Path)
+	require.Equal(t, "use example.com/shop/v2 instead.", moduleInfo.Module.Deprecated)
+	require.Equal(t, 2, moduleInfo.Module.Line)
+	require.Equal(t, "1.25.0", moduleInfo.Go)
+	require.Equal(t, "go1.25.1", moduleInfo.GetToolchainVersion())
+
+	require.Len(t, moduleInfo.Godebug, 1)
+	require.Equal(t, "default", moduleInfo.Godebug[0].Key)
+	require.Equal(t, "go1.21", moduleInfo.Godebug[0].Value)
+
+	require.Len(t, moduleInfo.Require, 2)
+	require.Equal(t, "github.com/go-kratos/kratos/v2", moduleInfo.Require[0].Path)
+	require.Equal(t, 12, moduleInfo.Require[0].Line)
+	require.Equal(t, []string{"kratos framework"}, moduleInfo.Require[0].Comments)
+	require.True(t, moduleInfo.Require[1].Indirect)
+
+	require.Len(t, moduleInfo.Exclude, 1)
+	require.Equal(t, "v2.7.0", moduleInfo.Exclude[0].Version)
+
+	require.Len(t, moduleInfo.Replace, 1)
+	require.Equal(t, "example.com/common", moduleInfo.Replace[0].Old.Path)
+	require.Equal(t, "../common", moduleInfo.Replace[0].New.Path)
+	require.Equal(t, 18, moduleInfo.Replace[0].Line)
+
+	require.Len(t, moduleInfo.Retract, 1)
+	require.Equal(t, "v1.0.0", moduleInfo.Retract[0].Low)
+	require.Equal(t, "v1.0.5", moduleInfo.Retract[0].High)
+	require.Equal(t, "broken build", moduleInfo.Retract[0].Rationale)
+
+	require.Len(t, moduleInfo.Tool, 1)
+	require.Equal(t, "github.com/google/wire/cmd/wire", moduleInfo.Tool[0].Path)
+
+	require.Len(t, moduleInfo.Ignore, 1)
+	require.Equal(t, "./node_modules", moduleInfo.Ignore[0].Path)
+}
+
+// TestGetModuleInfoWithGoCommand tests that the go command path agrees with the native parser
+//
+// TestGetModuleInfoWithGoCommand 测试 go 命令方式与原生解析结果一致
+func TestGetModuleInfoWithGoCommand(t *testing.T) {
+	nativeInfo, err := astkratos.GetModuleInfo(runpath.PARENT.Path())
+	require.NoError(t, err)
+	commandInfo, err := astkratos.GetModuleInfoWithGoCommand(runpath.PARENT.Path())
+	require.NoError(t, err)
+
+	require.Equal(t, commandInfo.Module.Path, nativeInfo.Module.Path)
+	require.Equal(t, commandInfo.Go, nativeInfo.Go)
+	require.Equal(t, commandInfo.GetToolchainVersion(), nativeInfo.GetToolchainVersion())
+	require.Len(t, nativeInfo.Require, len(commandInfo.Require))
+	for idx, item := range commandInfo.Require {
+		require.Equal(t, item.Path, nativeInfo.Require[idx].Path)
+		require.Equal(t, item.Version, nativeInfo.Require[idx].Version)
+		require.Equal(t, item.Indirect, nativeInfo.Require[idx].Indirect)
+		require.Positive(t, nativeInfo.Require[idx].Line)
+	}
+}
@@ -0,0 +1,129 @@
+// Package astkratos project analysis: Assembly of the full report of one Kratos app
+// Runs every analysis on the app root and gathers the results into a ProjectReport
+// Takes the api roots and the import path of the app root as inputs, so that apps
+// nested in a monorepo and apps sharing an api tree are analyzed the same way
+//
+// astkratos 项目分析：组装单个 Kratos 应用的完整报告
+// 在应用根目录上运行每项分析并将结果汇总到 ProjectReport
+// 以 api 根目录列表和应用根目录的导入路径作为输入，使嵌套在单仓库中的应用
+// 和共享 api 目录树的应用都能以相同方式分析
+package astkratos
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/yyle88/erero"
+)
+
+// analyzeProject builds the report of the app at the project root
+// The import path is the one of the project root, which is the module path when the root holds go.mod
+// No api roots means the app has no api tree
+// The context is checked between the phases, so a canceled analysis stops at the next phase
+//
+// analyzeProject 构建项目根目录下应用的报告
+// 导入路径是项目根目录的导入路径，根目录包含 go.mod 时即为模块路径
+// 没有 api 根目录表示应用没有 api 目录树
+// 在各阶段之间检查上下文，使被取消的分析在下一阶段停止
+func (a *Analyzer) analyzeProject(ctx context.Context, projectRoot string, apiRoots []string, importPath string, moduleInfo *ModuleInfo) (*ProjectReport, error) {
+	// Scan gRPC components in API paths, parsing each generated file once
+	// 扫描 API 目录中的 gRPC 组件，每个生成文件只解析一次
+	apiScan, err := a.scanApiFiles(ctx, apiRoots, scanAllFiles)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	services := apiScan.listServices()
+	if err := ctx.Err(); err != nil {
+		return nil, erero.Wro(err)
+	}
+
+	// Map the services to their implementation structs in internal/service
+	// 将服务映射到 internal/service 中的实现结构体
+	implementations, err := analyzeServiceImplementations(projectRoot, filepath.Join(projectRoot, "internal", "service"), services)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, erero.Wro(err)
+	}
+
+	// Find the registration calls in internal/server
+	// 查找 internal/server 中的注册调用
+	registrations, err := analyzeServiceRegistrations(projectRoot, filepath.Join(projectRoot, "internal", "server"), services, apiScan.httpFiles)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, erero.Wro(err)
+	}
+
+	// Extract the wire ProviderSets and injectors
+	// 提取 wire ProviderSet 和注入器
+	wireProject, err := loadWireProject(projectRoot, importPath)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, erero.Wro(err)
+	}
+
+	layerViolations, err := checkLayering(projectRoot, importPath, DefaultLayeringPolicy())
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, erero.Wro(err)
+	}
+
+	// Extract the configuration schema and check the config files against it
+	// 提取配置结构并据此检查配置文件
+	configSchema, err := analyzeConfigSchema(filepath.Join(projectRoot, "internal", "conf"))
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	configIssues, err := checkConfigFiles(filepath.Join(projectRoot, "configs"), configSchema)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, erero.Wro(err)
+	}
+
+	// Collect the usages to revisit before bumping Kratos
+	// 收集升级 Kratos 前需要重新检查的用法
+	upgrade, err := checkKratosUpgrade(projectRoot, apiRoots, moduleInfo, implementations)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, erero.Wro(err)
+	}
+
+	// Build comprehensive report
+	// 构建全面报告
+	return &ProjectReport{
+		ModuleInfo:      moduleInfo,
+		ApiRoots:        apiRoots,
+		Clients:         apiScan.listClients(),
+		Servers:         apiScan.listServers(),
+		Services:        services,
+		ServiceDescs:    apiScan.listServiceDescriptors(),
+		HttpRoutes:      apiScan.collectHttpRoutes(),
+		ErrorReasons:    apiScan.collectErrorReasons(),
+		ProtoDrifts:     apiScan.checkProtoDrifts(),
+		GeneratedFiles:  apiScan.listGeneratedFiles(),
+		GeneratedIssues: apiScan.checkGeneratedFiles(),
+		Implementations: implementations,
+		Coverage:        newServiceCoverages(services, implementations),
+		Exposures:       newServiceExposures(services, registrations),
+		WireGraph:       wireProject.graph,
+		WireStaleness:   wireProject.checkStaleness(),
+		DependencyGraph: wireProject.dependencyGraph(importPath),
+		LayerViolations: layerViolations,
+		Repositories:    wireProject.repoImplementations(importPath),
+		ConfigSchema:    configSchema,
+		ConfigIssues:    configIssues,
+		Upgrade:         upgrade,
+		UnreadFiles:     newUnreadFiles(apiScan.httpFailures, apiScan.errorsFailures),
+	}, nil
+}
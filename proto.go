@@ -0,0 +1,214 @@
+// Package astkratos proto analysis: Native parsing of .proto files in api directories
+// Reads package, options, imports, services, RPCs, messages and enums without protoc
+// Keeps google.api.http annotations, stream markers, field numbers and leading comments
+// Works even when the generated Go code is missing or stale
+//
+// astkratos proto 分析：原生解析 api 目录中的 .proto 文件
+// 无需 protoc 即可读取包名、选项、导入、服务、RPC、消息和枚举
+// 保留 google.api.http 注解、流标记、字段编号和前置注释
+// 即使生成的 Go 代码缺失或过期也能正常工作
+package astkratos
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yyle88/erero"
+)
+
+// ProtoFile represents a parsed .proto file
+//
+// ProtoFile 表示解析后的 .proto 文件
+type ProtoFile struct {
+	Path     string          // Absolute path of the .proto file // .proto 文件的绝对路径
+	Syntax   string          // Syntax or edition, such as proto3 // 语法或版本，例如 proto3
+	Package  string          // Proto package, such as helloworld.v1 // proto 包名，例如 helloworld.v1
+	Options  []*ProtoOption  // File options, such as go_package // 文件选项，例如 go_package
+	Imports  []*ProtoImport  // Imported files // 导入的文件
+	Services []*ProtoService // Services in declaration order // 按声明顺序排列的服务
+	Messages []*ProtoMessage // Top-level messages // 顶层消息
+	Enums    []*ProtoEnum    // Top-level enums // 顶层枚举
+}
+
+// GetOption returns the value of the named file option, blank when absent
+//
+// GetOption 返回指定文件选项的值，不存在时返回空字符串
+func (f *ProtoFile) GetOption(name string) string {
+	return getProtoOption(f.Options, name)
+}
+
+// ProtoOption represents an option assignment
+// Scalar values are unquoted, aggregate values keep the source text between the braces
+//
+// ProtoOption 表示一个选项赋值
+// 标量值去掉引号，聚合值保留大括号内的源码文本
+type ProtoOption struct {
+	Name  string // Option name, such as go_package or (google.api.http) // 选项名称，例如 go_package 或 (google.api.http)
+	Value string // Option value // 选项值
+	Line  int    // Line number of the option // 选项所在行号
+}
+
+// ProtoImport represents an import statement
+//
+// ProtoImport 表示一条导入语句
+type ProtoImport struct {
+	Path     string // Imported file path // 导入的文件路径
+	Modifier string // Blank, public or weak // 空、public 或 weak
+	Line     int    // Line number of the import // 导入所在行号
+}
+
+// ProtoService represents a service definition
+//
+// ProtoService 表示服务定义
+type ProtoService struct {
+	Name    string         // Service name, such as Greeter // 服务名称，例如 Greeter
+	Rpcs    []*ProtoRpc    // RPC methods in declaration order // 按声明顺序排列的 RPC 方法
+	Options []*ProtoOption // Service options // 服务选项
+	Comment string         // Leading comment // 前置注释
+	Line    int            // Line number of the definition // 定义所在行号
+}
+
+// ProtoRpc represents an RPC method definition
+//
+// ProtoRpc 表示 RPC 方法定义
+type ProtoRpc struct {
+	Name            string           // RPC name, such as SayHello // RPC 名称，例如 SayHello
+	RequestType     string           // Request message type // 请求消息类型
+	ResponseType    string           // Response message type // 响应消息类型
+	ClientStreaming bool             // Request marked with stream // 请求带有 stream 标记
+	ServerStreaming bool             // Response marked with stream // 响应带有 stream 标记
+	HttpRules       []*ProtoHttpRule // google.api.http bindings, additional bindings included // google.api.http 绑定，包含附加绑定
+	Options         []*ProtoOption   // RPC options // RPC 选项
+	Comment         string           // Leading comment // 前置注释
+	Line            int              // Line number of the definition // 定义所在行号
+}
+
+// StreamingKind returns the streaming kind derived from the stream markers
+//
+// StreamingKind 根据流标记返回流式类型
+func (r *ProtoRpc) StreamingKind() GrpcStreamingKind {
+	switch {
+	case r.ClientStreaming && r.ServerStreaming:
+		return GrpcStreamingBidi
+	case r.ClientStreaming:
+		return GrpcStreamingClient
+	case r.ServerStreaming:
+		return GrpcStreamingServer
+	default:
+		return GrpcStreamingUnary
+	}
+}
+
+// ProtoHttpRule represents a google.api.http binding
+//
+// ProtoHttpRule 表示 google.api.http 绑定
+type ProtoHttpRule struct {
+	Method       string // HTTP verb, such as GET, or the custom kind // HTTP 方法，例如 GET，或自定义类型
+	Path         string // Path template, such as /helloworld/{name} // 路径模板，例如 /helloworld/{name}
+	Body         string // Body field, such as * // 请求体字段，例如 *
+	ResponseBody string // Response body field // 响应体字段
+	Line         int    // Line number of the annotation // 注解所在行号
+}
+
+// ProtoMessage represents a message definition
+//
+// ProtoMessage 表示消息定义
+type ProtoMessage struct {
+	Name     string          // Message name // 消息名称
+	Fields   []*ProtoField   // Fields in declaration order, oneof fields included // 按声明顺序排列的字段，包含 oneof 字段
+	Messages []*ProtoMessage // Nested messages // 嵌套消息
+	Enums    []*ProtoEnum    // Nested enums // 嵌套枚举
+	Options  []*ProtoOption  // Message options // 消息选项
+	Comment  string          // Leading comment // 前置注释
+	Line     int             // Line number of the definition // 定义所在行号
+}
+
+// ProtoField represents a message field
+//
+// ProtoField 表示消息字段
+type ProtoField struct {
+	Name       string         // Field name, such as max_idle // 字段名称，例如 max_idle
+	JsonName   string         // JSON name, such as maxIdle // JSON 名称，例如 maxIdle
+	Type       string         // Field type, the value type of map fields // 字段类型，map 字段为值类型
+	MapKeyType string         // Key type of map fields, blank otherwise // map 字段的键类型，否则为空
+	Label      string         // Blank, repeated, optional or required // 空、repeated、optional 或 required
+	Number     int            // Field number // 字段编号
+	Oneof      string         // Name of the enclosing oneof // 所属 oneof 的名称
+	Options    []*ProtoOption // Field options // 字段选项
+	Comment    string         // Leading comment // 前置注释
+	Line       int            // Line number of the definition // 定义所在行号
+}
+
+// ProtoEnum represents an enum definition
+//
+// ProtoEnum 表示枚举定义
+type ProtoEnum struct {
+	Name    string            // Enum name // 枚举名称
+	Values  []*ProtoEnumValue // Values in declaration order // 按声明顺序排列的枚举值
+	Options []*ProtoOption    // Enum options // 枚举选项
+	Comment string            // Leading comment // 前置注释
+	Line    int               // Line number of the definition // 定义所在行号
+}
+
+// ProtoEnumValue represents an enum value
+//
+// ProtoEnumValue 表示枚举值
+type ProtoEnumValue struct {
+	Name    string         // Value name // 枚举值名称
+	Number  int            // Value number // 枚举值编号
+	Options []*ProtoOption // Value options, such as (errors.code) // 枚举值选项，例如 (errors.code)
+	Comment string         // Leading comment // 前置注释
+	Line    int            // Line number of the definition // 定义所在行号
+}
+
+// ParseProtoFile parses the .proto file at the path without invoking protoc
+//
+// ParseProtoFile 在不调用 protoc 的情况下解析指定路径的 .proto 文件
+func ParseProtoFile(path string) (*ProtoFile, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	source, err := os.ReadFile(absPath)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	protoFile, err := parseProtoSource(absPath, source)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return protoFile, nil
+}
+
+// getProtoOption returns the value of the named option, blank when absent
+//
+// getProtoOption 返回指定选项的值，不存在时返回空字符串
+func getProtoOption(options []*ProtoOption, name string) string {
+	for _, option := range options {
+		if option.Name == name {
+			return option.Value
+		}
+	}
+	return ""
+}
+
+// protoJsonName converts a field name to the default protobuf JSON name, such as max_idle to maxIdle
+//
+// protoJsonName 将字段名转换为默认的 protobuf JSON 名称，例如 max_idle 转为 maxIdle
+func protoJsonName(name string) string {
+	var builder strings.Builder
+	upper := false
+	for _, c := range name {
+		switch {
+		case c == '_':
+			upper = true
+		case upper:
+			builder.WriteString(strings.ToUpper(string(c)))
+			upper = false
+		default:
+			builder.WriteRune(c)
+		}
+	}
+	return builder.String()
+}
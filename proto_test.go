@@ -0,0 +1,125 @@
+package astkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/astkratos"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/neatjson/neatjsons"
+	"github.com/yyle88/rese"
+	"github.com/yyle88/runpath"
+)
+
+// TestParseProtoFile tests parsing of package, options, imports, services and HTTP annotations
+//
+// TestParseProtoFile 测试解析包名、选项、导入、服务和 HTTP 注解
+func TestParseProtoFile(t *testing.T) {
+	protoFile := rese.P1(astkratos.ParseProtoFile(runpath.PARENT.Join("testdata", "demokratos", "api", "helloworld", "v1", "greeter.proto")))
+	t.Log(neatjsons.S(protoFile))
+
+	require.Equal(t, "proto3", protoFile.Syntax)
+	require.Equal(t, "helloworld.v1", protoFile.Package)
+	require.Equal(t, "demokratos/api/helloworld/v1;v1", protoFile.GetOption("go_package"))
+	require.Equal(t, "true", protoFile.GetOption("java_multiple_files"))
+	require.Len(t, protoFile.Imports, 1)
+	require.Equal(t, "google/api/annotations.proto", protoFile.Imports[0].Path)
+
+	require.Len(t, protoFile.Services, 1)
+	service := protoFile.Services[0]
+	require.Equal(t, "Greeter", service.Name)
+	require.Equal(t, "The greeting service definition.", service.Comment)
+	require.Equal(t, 13, service.Line)
+
+	require.Len(t, service.Rpcs, 1)
+	rpc := service.Rpcs[0]
+	require.Equal(t, "SayHello", rpc.Name)
+	require.Equal(t, "HelloRequest", rpc.RequestType)
+	require.Equal(t, "HelloReply", rpc.ResponseType)
+	require.Equal(t, astkratos.GrpcStreamingUnary, rpc.StreamingKind())
+	require.Equal(t, "Sends a greeting", rpc.Comment)
+	require.Equal(t, []*astkratos.ProtoHttpRule{
+		{Method: "GET", Path: "/helloworld/{name}", Line: 16},
+		{Method: "POST", Path: "/helloworld", Body: "*", Line: 18},
+	}, rpc.HttpRules)
+
+	require.Len(t, protoFile.Messages, 2)
+	require.Equal(t, "HelloRequest", protoFile.Messages[0].Name)
+	require.Equal(t, "The request message containing the user's name.", protoFile.Messages[0].Comment)
+	require.Equal(t, "name", protoFile.Messages[0].Fields[0].Name)
+	require.Equal(t, "string", protoFile.Messages[0].Fields[0].Type)
+	require.Equal(t, 1, protoFile.Messages[0].Fields[0].Number)
+}
+
+// TestParseProtoFile_Streaming tests stream markers, map fields and oneof fields
+//
+// TestParseProtoFile_Streaming 测试流标记、map 字段和 oneof 字段
+func TestParseProtoFile_Streaming(t *testing.T) {
+	protoFile := rese.P1(astkratos.ParseProtoFile(runpath.PARENT.Join("testdata", "demokratos", "api", "echo", "v1", "echo.proto")))
+
+	kinds := map[string]astkratos.GrpcStreamingKind{}
+	for _, rpc := range protoFile.Services[0].Rpcs {
+		kinds[rpc.Name] = rpc.StreamingKind()
+	}
+	require.Equal(t, map[string]astkratos.GrpcStreamingKind{
+		"Ping":    astkratos.GrpcStreamingUnary,
+		"Watch":   astkratos.GrpcStreamingServer,
+		"Collect": astkratos.GrpcStreamingClient,
+		"Chat":    astkratos.GrpcStreamingBidi,
+	}, kinds)
+
+	messages := map[string]*astkratos.ProtoMessage{}
+	for _, message := range protoFile.Messages {
+		messages[message.Name] = message
+	}
+	labels := messages["WatchEvent"].Fields[2]
+	require.Equal(t, "labels", labels.Name)
+	require.Equal(t, "string", labels.MapKeyType)
+	require.Equal(t, "string", labels.Type)
+	require.Equal(t, 3, labels.Number)
+
+	chat := messages["ChatMessage"].Fields
+	require.Len(t, chat, 3)
+	require.Equal(t, "", chat[0].Oneof)
+	require.Equal(t, "content", chat[1].Oneof)
+	require.Equal(t, "content", chat[2].Oneof)
+}
+
+// TestParseProtoFile_Enums tests enum values with options, nested enums and json_name
+//
+// TestParseProtoFile_Enums 测试带选项的枚举值、嵌套枚举和 json_name
+func TestParseProtoFile_Enums(t *testing.T) {
+	errorsProto := rese.P1(astkratos.ParseProtoFile(runpath.PARENT.Join("testdata", "demokratos", "api", "helloworld", "v1", "error_reason.proto")))
+	require.Len(t, errorsProto.Enums, 1)
+	enum := errorsProto.Enums[0]
+	require.Equal(t, "ErrorReason", enum.Name)
+	require.Equal(t, "(errors.default_code)", enum.Options[0].Name)
+	require.Equal(t, "500", enum.Options[0].Value)
+	require.Equal(t, "USER_NOT_FOUND", enum.Values[1].Name)
+	require.Equal(t, 1, enum.Values[1].Number)
+	require.Equal(t, "(errors.code)", enum.Values[1].Options[0].Name)
+	require.Equal(t, "404", enum.Values[1].Options[0].Value)
+
+	legacyProto := rese.P1(astkratos.ParseProtoFile(runpath.PARENT.Join("testdata", "demokratos", "api", "legacy", "v1", "legacy.proto")))
+	require.Equal(t, "Legacy is generated with an old protoc-gen-go-grpc.", legacyProto.Services[0].Comment)
+	item := legacyProto.Messages[1]
+	require.Equal(t, "Item", item.Name)
+	require.Equal(t, "repeated", item.Fields[2].Label)
+	require.Equal(t, "Kind", item.Enums[0].Name)
+	require.Len(t, item.Enums[0].Values, 2)
+
+	syncReply := legacyProto.Messages[len(legacyProto.Messages)-1]
+	require.Equal(t, "val", syncReply.Fields[1].JsonName)
+	require.Equal(t, "key", syncReply.Fields[0].JsonName)
+}
+
+// TestListProtoFiles tests listing of the .proto files in the api tree
+//
+// TestListProtoFiles 测试列出 api 目录树中的 .proto 文件
+func TestListProtoFiles(t *testing.T) {
+	protoFiles := astkratos.ListProtoFiles(demoApiRoot)
+	var packages []string
+	for _, protoFile := range protoFiles {
+		packages = append(packages, protoFile.Package)
+	}
+	require.ElementsMatch(t, []string{"echo.v1", "helloworld.v1", "helloworld.v1", "legacy.v1"}, packages)
+}
@@ -0,0 +1,1034 @@
+// Package astkratos proto parsing internals: Tokenizer and recursive descent parser of .proto sources
+// Tokenizes identifiers, numbers, strings and symbols and attaches leading comment blocks
+// Parses the proto2/proto3 grammar used in api directories and skips unsupported blocks
+//
+// astkratos proto 解析内部实现：.proto 源码的分词器和递归下降解析器
+// 对标识符、数字、字符串和符号分词，并附加前置注释块
+// 解析 api 目录中使用的 proto2/proto3 语法，跳过不支持的语句块
+package astkratos
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/yyle88/erero"
+)
+
+// protoTokenKind represents the kind of a proto token
+//
+// protoTokenKind 表示 proto 词法单元的类型
+type protoTokenKind int
+
+const (
+	protoTokenEOF    protoTokenKind = iota // End of source // 源码结束
+	protoTokenIdent                        // Identifier, dotted names included // 标识符，包含带点的名称
+	protoTokenNumber                       // Numeric literal // 数字字面量
+	protoTokenString                       // String literal, text is unquoted // 字符串字面量，文本已去掉引号
+	protoTokenSymbol                       // Single character symbol // 单字符符号
+)
+
+// protoToken represents a token with its position and leading comment
+//
+// protoToken 表示带位置和前置注释的词法单元
+type protoToken struct {
+	kind    protoTokenKind // Token kind // 词法单元类型
+	text    string         // Token text // 词法单元文本
+	line    int            // Line number, starting at 1 // 行号，从 1 开始
+	column  int            // Column number, starting at 1 // 列号，从 1 开始
+	offset  int            // Start offset in source // 在源码中的起始偏移
+	end     int            // End offset in source // 在源码中的结束偏移
+	comment string         // Leading comment block // 前置注释块
+}
+
+// protoComment represents a comment with its line range
+//
+// protoComment 表示带行范围的注释
+type protoComment struct {
+	text      string // Comment text without markers // 去掉标记的注释文本
+	startLine int    // First line // 起始行
+	endLine   int    // Last line // 结束行
+}
+
+// tokenizeProto splits the proto source into tokens
+//
+// tokenizeProto 将 proto 源码拆分为词法单元
+func tokenizeProto(path string, source []byte) ([]*protoToken, error) {
+	var tokens []*protoToken
+	var pending []*protoComment
+	line, column := 1, 1
+	lastTokenLine := 0
+
+	advance := func(n int, idx int) {
+		for i := idx; i < idx+n && i < len(source); i++ {
+			if source[i] == '\n' {
+				line++
+				column = 1
+			} else {
+				column++
+			}
+		}
+	}
+
+	for idx := 0; idx < len(source); {
+		c := source[idx]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == '\v':
+			advance(1, idx)
+			idx++
+		case c == '/' && idx+1 < len(source) && source[idx+1] == '/':
+			end := idx
+			for end < len(source) && source[end] != '\n' {
+				end++
+			}
+			pending = append(pending, &protoComment{
+				text:      strings.TrimSpace(strings.TrimPrefix(string(source[idx:end]), "//")),
+				startLine: line,
+				endLine:   line,
+			})
+			advance(end-idx, idx)
+			idx = end
+		case c == '/' && idx+1 < len(source) && source[idx+1] == '*':
+			closeIdx := strings.Index(string(source[idx+2:]), "*/")
+			if closeIdx < 0 {
+				return nil, erero.Errorf("%s:%d:%d: unterminated block comment", path, line, column)
+			}
+			end := idx + 2 + closeIdx + 2
+			startLine := line
+			body := string(source[idx+2 : end-2])
+			var lines []string
+			for _, s := range strings.Split(body, "\n") {
+				lines = append(lines, strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(s), "*")))
+			}
+			advance(end-idx, idx)
+			pending = append(pending, &protoComment{
+				text:      strings.TrimSpace(strings.Join(lines, "\n")),
+				startLine: startLine,
+				endLine:   line,
+			})
+			idx = end
+		default:
+			token := &protoToken{line: line, column: column, offset: idx}
+			end := idx
+			switch {
+			case c == '"' || c == '\'':
+				end++
+				for end < len(source) && source[end] != c {
+					if source[end] == '\\' {
+						end++
+					}
+					if end < len(source) && source[end] == '\n' {
+						return nil, erero.Errorf("%s:%d:%d: unterminated string", path, line, column)
+					}
+					end++
+				}
+				if end >= len(source) {
+					return nil, erero.Errorf("%s:%d:%d: unterminated string", path, line, column)
+				}
+				end++
+				token.kind = protoTokenString
+				token.text = unquoteProtoString(string(source[idx+1 : end-1]))
+			case isProtoDigit(c) || (c == '.' && idx+1 < len(source) && isProtoDigit(source[idx+1])):
+				end = scanProtoNumber(source, idx)
+				token.kind = protoTokenNumber
+				token.text = string(source[idx:end])
+			case isProtoIdentByte(c):
+				for end < len(source) && isProtoIdentByte(source[end]) {
+					end++
+				}
+				token.kind = protoTokenIdent
+				token.text = string(source[idx:end])
+			default:
+				end++
+				token.kind = protoTokenSymbol
+				token.text = string(c)
+			}
+			token.end = end
+			token.comment = leadingProtoComment(pending, token.line, lastTokenLine)
+			pending = nil
+			lastTokenLine = line
+			advance(end-idx, idx)
+			idx = end
+			tokens = append(tokens, token)
+		}
+	}
+	tokens = append(tokens, &protoToken{kind: protoTokenEOF, line: line, column: column, offset: len(source), end: len(source)})
+	return tokens, nil
+}
+
+// isProtoIdentByte reports whether the byte can appear in an identifier or a dotted name
+//
+// isProtoIdentByte 判断字节是否可以出现在标识符或带点名称中
+func isProtoIdentByte(c byte) bool {
+	return c == '_' || c == '.' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || isProtoDigit(c)
+}
+
+// isProtoDigit reports whether the byte is a decimal digit
+//
+// isProtoDigit 判断字节是否为十进制数字
+func isProtoDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// scanProtoNumber returns the end offset of the numeric literal starting at the offset
+// Reads hex and octal integers, and decimals with a fraction and an exponent such as 1.5e-3 and .5
+// The sign is a token of its own, the parser joins it with the number
+//
+// scanProtoNumber 返回从该偏移开始的数字字面量的结束偏移
+// 读取十六进制和八进制整数，以及带小数和指数的十进制数，例如 1.5e-3 和 .5
+// 符号是单独的词法单元，由解析器将其与数字拼接
+func scanProtoNumber(source []byte, idx int) int {
+	end := idx
+	if source[end] == '0' && end+1 < len(source) && (source[end+1] == 'x' || source[end+1] == 'X') {
+		end += 2
+		for end < len(source) && strings.IndexByte("0123456789abcdefABCDEF", source[end]) >= 0 {
+			end++
+		}
+		return end
+	}
+	for end < len(source) && isProtoDigit(source[end]) {
+		end++
+	}
+	if end < len(source) && source[end] == '.' {
+		end++
+		for end < len(source) && isProtoDigit(source[end]) {
+			end++
+		}
+	}
+	if end < len(source) && (source[end] == 'e' || source[end] == 'E') {
+		exponent := end + 1
+		if exponent < len(source) && (source[exponent] == '+' || source[exponent] == '-') {
+			exponent++
+		}
+		if exponent < len(source) && isProtoDigit(source[exponent]) {
+			end = exponent
+			for end < len(source) && isProtoDigit(source[end]) {
+				end++
+			}
+		}
+	}
+	return end
+}
+
+// leadingProtoComment joins the comment block that ends right above the token line
+// Comments on the line of the previous token are trailing comments and are left out
+//
+// leadingProtoComment 拼接紧贴在词法单元所在行上方的注释块
+// 与上一个词法单元同一行的注释属于行尾注释，不计入
+func leadingProtoComment(pending []*protoComment, tokenLine int, lastTokenLine int) string {
+	expectLine := tokenLine - 1
+	start := len(pending)
+	for idx := len(pending) - 1; idx >= 0; idx-- {
+		comment := pending[idx]
+		if comment.endLine != expectLine && comment.endLine != tokenLine {
+			break
+		}
+		if comment.startLine == lastTokenLine {
+			break
+		}
+		start = idx
+		expectLine = comment.startLine - 1
+	}
+	var texts []string
+	for _, comment := range pending[start:] {
+		texts = append(texts, comment.text)
+	}
+	return strings.Join(texts, "\n")
+}
+
+// unquoteProtoString resolves the escape sequences of a proto string literal body
+//
+// unquoteProtoString 解析 proto 字符串字面量内容中的转义序列
+func unquoteProtoString(body string) string {
+	if !strings.Contains(body, `\`) {
+		return body
+	}
+	var builder strings.Builder
+	for idx := 0; idx < len(body); idx++ {
+		if body[idx] != '\\' || idx+1 >= len(body) {
+			builder.WriteByte(body[idx])
+			continue
+		}
+		idx++
+		switch c := body[idx]; c {
+		case 'n':
+			builder.WriteByte('\n')
+		case 't':
+			builder.WriteByte('\t')
+		case 'r':
+			builder.WriteByte('\r')
+		case 'x', 'X':
+			end := idx + 1
+			for end < len(body) && end < idx+3 && strings.IndexByte("0123456789abcdefABCDEF", body[end]) >= 0 {
+				end++
+			}
+			if value, err := strconv.ParseUint(body[idx+1:end], 16, 8); err == nil {
+				builder.WriteByte(byte(value))
+			}
+			idx = end - 1
+		case '0', '1', '2', '3', '4', '5', '6', '7':
+			end := idx
+			for end < len(body) && end < idx+3 && body[end] >= '0' && body[end] <= '7' {
+				end++
+			}
+			if value, err := strconv.ParseUint(body[idx:end], 8, 8); err == nil {
+				builder.WriteByte(byte(value))
+			}
+			idx = end - 1
+		default:
+			builder.WriteByte(c)
+		}
+	}
+	return builder.String()
+}
+
+// protoParser parses tokens into a ProtoFile
+//
+// protoParser 将词法单元解析为 ProtoFile
+type protoParser struct {
+	path   string        // Source path used in error messages // 错误信息中使用的源文件路径
+	source []byte        // Source code // 源码
+	tokens []*protoToken // Tokens ending with EOF // 以 EOF 结尾的词法单元
+	pos    int           // Current token index // 当前词法单元索引
+}
+
+// protoTextField represents a field of an aggregate option value in protobuf text format
+//
+// protoTextField 表示 protobuf 文本格式聚合选项值中的字段
+type protoTextField struct {
+	name   string            // Field name // 字段名称
+	value  string            // Scalar value // 标量值
+	fields []*protoTextField // Nested fields of message values // 消息值的嵌套字段
+	line   int               // Line number of the field // 字段所在行号
+}
+
+// parseProtoSource tokenizes and parses the proto source
+//
+// parseProtoSource 对 proto 源码分词并解析
+func parseProtoSource(path string, source []byte) (*ProtoFile, error) {
+	tokens, err := tokenizeProto(path, source)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	p := &protoParser{path: path, source: source, tokens: tokens}
+	return p.parseFile()
+}
+
+func (p *protoParser) peek() *protoToken {
+	return p.tokens[p.pos]
+}
+
+func (p *protoParser) next() *protoToken {
+	token := p.tokens[p.pos]
+	if token.kind != protoTokenEOF {
+		p.pos++
+	}
+	return token
+}
+
+// is reports whether the current token is the keyword or symbol
+//
+// is 判断当前词法单元是否为指定的关键字或符号
+func (p *protoParser) is(text string) bool {
+	token := p.peek()
+	return token.kind != protoTokenString && token.kind != protoTokenEOF && token.text == text
+}
+
+// accept consumes the current token when it is the keyword or symbol
+//
+// accept 当前词法单元为指定的关键字或符号时将其消费
+func (p *protoParser) accept(text string) bool {
+	if p.is(text) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *protoParser) errorf(token *protoToken, format string, args ...any) error {
+	return erero.Errorf("%s:%d:%d: %s", p.path, token.line, token.column, fmt.Sprintf(format, args...))
+}
+
+func (p *protoParser) expect(text string) (*protoToken, error) {
+	if !p.is(text) {
+		return nil, p.errorf(p.peek(), "expected %q, found %q", text, p.peek().text)
+	}
+	return p.next(), nil
+}
+
+func (p *protoParser) expectKind(kind protoTokenKind, what string) (*protoToken, error) {
+	if p.peek().kind != kind {
+		return nil, p.errorf(p.peek(), "expected %s, found %q", what, p.peek().text)
+	}
+	return p.next(), nil
+}
+
+func (p *protoParser) parseFile() (*ProtoFile, error) {
+	protoFile := &ProtoFile{
+		Path:     p.path,
+		Options:  make([]*ProtoOption, 0),
+		Imports:  make([]*ProtoImport, 0),
+		Services: make([]*ProtoService, 0),
+		Messages: make([]*ProtoMessage, 0),
+		Enums:    make([]*ProtoEnum, 0),
+	}
+	for p.peek().kind != protoTokenEOF {
+		token := p.peek()
+		switch {
+		case p.accept(";"):
+		case p.is("syntax") || p.is("edition"):
+			p.next()
+			if _, err := p.expect("="); err != nil {
+				return nil, err
+			}
+			value, err := p.expectKind(protoTokenString, "syntax string")
+			if err != nil {
+				return nil, err
+			}
+			protoFile.Syntax = value.text
+			if _, err := p.expect(";"); err != nil {
+				return nil, err
+			}
+		case p.accept("package"):
+			name, err := p.expectKind(protoTokenIdent, "package name")
+			if err != nil {
+				return nil, err
+			}
+			protoFile.Package = name.text
+			if _, err := p.expect(";"); err != nil {
+				return nil, err
+			}
+		case p.accept("import"):
+			protoImport := &ProtoImport{Line: token.line}
+			if p.is("public") || p.is("weak") {
+				protoImport.Modifier = p.next().text
+			}
+			value, err := p.expectKind(protoTokenString, "import path")
+			if err != nil {
+				return nil, err
+			}
+			protoImport.Path = value.text
+			protoFile.Imports = append(protoFile.Imports, protoImport)
+			if _, err := p.expect(";"); err != nil {
+				return nil, err
+			}
+		case p.accept("option"):
+			option, _, err := p.parseOptionStatement(token)
+			if err != nil {
+				return nil, err
+			}
+			protoFile.Options = append(protoFile.Options, option)
+		case p.is("message"):
+			message, err := p.parseMessage()
+			if err != nil {
+				return nil, err
+			}
+			protoFile.Messages = append(protoFile.Messages, message)
+		case p.is("enum"):
+			enum, err := p.parseEnum()
+			if err != nil {
+				return nil, err
+			}
+			protoFile.Enums = append(protoFile.Enums, enum)
+		case p.is("service"):
+			service, err := p.parseService()
+			if err != nil {
+				return nil, err
+			}
+			protoFile.Services = append(protoFile.Services, service)
+		case p.is("extend"):
+			if err := p.skipStatement(); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, p.errorf(token, "unexpected %q", token.text)
+		}
+	}
+	return protoFile, nil
+}
+
+// parseOptionStatement parses "name = value;" after the option keyword
+//
+// parseOptionStatement 解析 option 关键字之后的 "name = value;"
+func (p *protoParser) parseOptionStatement(start *protoToken) (*ProtoOption, []*protoTextField, error) {
+	option, fields, err := p.parseOptionAssignment(start)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := p.expect(";"); err != nil {
+		return nil, nil, err
+	}
+	return option, fields, nil
+}
+
+// parseOptionAssignment parses "name = value" and returns the aggregate fields of message values
+//
+// parseOptionAssignment 解析 "name = value"，消息值时返回聚合字段
+func (p *protoParser) parseOptionAssignment(start *protoToken) (*ProtoOption, []*protoTextField, error) {
+	name, err := p.parseOptionName()
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := p.expect("="); err != nil {
+		return nil, nil, err
+	}
+	option := &ProtoOption{Name: name, Line: start.line}
+	if p.is("{") {
+		open := p.next()
+		fields, err := p.parseTextFields("}")
+		if err != nil {
+			return nil, nil, err
+		}
+		closeToken := p.tokens[p.pos-1]
+		option.Value = strings.TrimSpace(string(p.source[open.end:closeToken.offset]))
+		return option, fields, nil
+	}
+	value, err := p.parseScalarValue()
+	if err != nil {
+		return nil, nil, err
+	}
+	option.Value = value
+	return option, nil, nil
+}
+
+// parseOptionName parses names such as go_package, (google.api.http) and (validate.rules).string.min_len
+//
+// parseOptionName 解析 go_package、(google.api.http) 和 (validate.rules).string.min_len 等名称
+func (p *protoParser) parseOptionName() (string, error) {
+	var builder strings.Builder
+	for {
+		switch {
+		case p.accept("("):
+			name, err := p.expectKind(protoTokenIdent, "option name")
+			if err != nil {
+				return "", err
+			}
+			if _, err := p.expect(")"); err != nil {
+				return "", err
+			}
+			builder.WriteString("(" + name.text + ")")
+		case p.peek().kind == protoTokenIdent && (builder.Len() == 0 || strings.HasPrefix(p.peek().text, ".")):
+			builder.WriteString(p.next().text)
+		default:
+			if builder.Len() == 0 {
+				return "", p.errorf(p.peek(), "expected option name, found %q", p.peek().text)
+			}
+			return builder.String(), nil
+		}
+	}
+}
+
+// parseScalarValue parses a constant: adjacent strings, identifiers or signed numbers
+//
+// parseScalarValue 解析常量：相邻字符串、标识符或带符号数字
+func (p *protoParser) parseScalarValue() (string, error) {
+	token := p.peek()
+	switch {
+	case token.kind == protoTokenString:
+		var builder strings.Builder
+		for p.peek().kind == protoTokenString {
+			builder.WriteString(p.next().text)
+		}
+		return builder.String(), nil
+	case p.is("-") || p.is("+"):
+		sign := p.next().text
+		value := p.next()
+		if value.kind != protoTokenNumber && value.kind != protoTokenIdent {
+			return "", p.errorf(value, "expected number, found %q", value.text)
+		}
+		return strings.TrimPrefix(sign, "+") + value.text, nil
+	case token.kind == protoTokenIdent || token.kind == protoTokenNumber:
+		return p.next().text, nil
+	default:
+		return "", p.errorf(token, "expected constant, found %q", token.text)
+	}
+}
+
+// parseTextFields parses protobuf text format fields until the closing symbol
+//
+// parseTextFields 解析 protobuf 文本格式字段直到结束符号
+func (p *protoParser) parseTextFields(closeSymbol string) ([]*protoTextField, error) {
+	var fields []*protoTextField
+	for !p.accept(closeSymbol) {
+		token := p.peek()
+		if token.kind == protoTokenEOF {
+			return nil, p.errorf(token, "expected %q, found end of file", closeSymbol)
+		}
+		var name string
+		if p.accept("[") {
+			nameToken, err := p.expectKind(protoTokenIdent, "extension name")
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			name = "[" + nameToken.text + "]"
+		} else {
+			nameToken, err := p.expectKind(protoTokenIdent, "field name")
+			if err != nil {
+				return nil, err
+			}
+			name = nameToken.text
+		}
+		p.accept(":")
+		if p.accept("[") {
+			for !p.accept("]") {
+				field, err := p.parseTextValue(name, token.line)
+				if err != nil {
+					return nil, err
+				}
+				fields = append(fields, field)
+				p.accept(",")
+			}
+		} else {
+			field, err := p.parseTextValue(name, token.line)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, field)
+		}
+		if !p.accept(",") {
+			p.accept(";")
+		}
+	}
+	return fields, nil
+}
+
+// parseTextValue parses one text format value, either a message or a scalar
+//
+// parseTextValue 解析一个文本格式值，消息或标量
+func (p *protoParser) parseTextValue(name string, line int) (*protoTextField, error) {
+	field := &protoTextField{name: name, line: line}
+	switch {
+	case p.accept("{"):
+		fields, err := p.parseTextFields("}")
+		if err != nil {
+			return nil, err
+		}
+		field.fields = fields
+	case p.accept("<"):
+		fields, err := p.parseTextFields(">")
+		if err != nil {
+			return nil, err
+		}
+		field.fields = fields
+	default:
+		value, err := p.parseScalarValue()
+		if err != nil {
+			return nil, err
+		}
+		field.value = value
+	}
+	return field, nil
+}
+
+// parseFieldOptions parses "[name = value, ...]" when present
+//
+// parseFieldOptions 解析存在的 "[name = value, ...]"
+func (p *protoParser) parseFieldOptions() ([]*ProtoOption, error) {
+	options := make([]*ProtoOption, 0)
+	if !p.accept("[") {
+		return options, nil
+	}
+	for {
+		option, _, err := p.parseOptionAssignment(p.peek())
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, option)
+		if p.accept("]") {
+			return options, nil
+		}
+		if _, err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseNumber parses a possibly negative integer in decimal, hex or octal
+//
+// parseNumber 解析可能为负的十进制、十六进制或八进制整数
+func (p *protoParser) parseNumber() (int, error) {
+	token := p.peek()
+	text, err := p.parseScalarValue()
+	if err != nil {
+		return 0, err
+	}
+	value, err := strconv.ParseInt(text, 0, 64)
+	if err != nil {
+		return 0, p.errorf(token, "invalid number %q", text)
+	}
+	return int(value), nil
+}
+
+func (p *protoParser) parseMessage() (*ProtoMessage, error) {
+	start := p.next()
+	name, err := p.expectKind(protoTokenIdent, "message name")
+	if err != nil {
+		return nil, err
+	}
+	message := &ProtoMessage{
+		Name:     name.text,
+		Fields:   make([]*ProtoField, 0),
+		Messages: make([]*ProtoMessage, 0),
+		Enums:    make([]*ProtoEnum, 0),
+		Options:  make([]*ProtoOption, 0),
+		Comment:  start.comment,
+		Line:     start.line,
+	}
+	if _, err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	if err := p.parseMessageBody(message, ""); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// parseMessageBody parses message elements until the closing brace, oneof bodies included
+//
+// parseMessageBody 解析消息元素直到右大括号，包含 oneof 内容
+func (p *protoParser) parseMessageBody(message *ProtoMessage, oneof string) error {
+	for !p.accept("}") {
+		token := p.peek()
+		switch {
+		case token.kind == protoTokenEOF:
+			return p.errorf(token, "expected \"}\", found end of file")
+		case p.accept(";"):
+		case p.accept("option"):
+			option, _, err := p.parseOptionStatement(token)
+			if err != nil {
+				return err
+			}
+			if oneof == "" {
+				message.Options = append(message.Options, option)
+			}
+		case oneof == "" && p.is("message"):
+			nested, err := p.parseMessage()
+			if err != nil {
+				return err
+			}
+			message.Messages = append(message.Messages, nested)
+		case oneof == "" && p.is("enum"):
+			nested, err := p.parseEnum()
+			if err != nil {
+				return err
+			}
+			message.Enums = append(message.Enums, nested)
+		case oneof == "" && p.is("oneof"):
+			p.next()
+			name, err := p.expectKind(protoTokenIdent, "oneof name")
+			if err != nil {
+				return err
+			}
+			if _, err := p.expect("{"); err != nil {
+				return err
+			}
+			if err := p.parseMessageBody(message, name.text); err != nil {
+				return err
+			}
+		case p.is("reserved") || p.is("extensions") || p.is("extend") || p.is("group"):
+			if err := p.skipStatement(); err != nil {
+				return err
+			}
+		default:
+			field, err := p.parseField(oneof)
+			if err != nil {
+				return err
+			}
+			message.Fields = append(message.Fields, field)
+		}
+	}
+	return nil
+}
+
+// parseField parses "[label] type name = number [options];" and map fields
+//
+// parseField 解析 "[label] type name = number [options];" 以及 map 字段
+func (p *protoParser) parseField(oneof string) (*ProtoField, error) {
+	start := p.peek()
+	field := &ProtoField{Oneof: oneof, Comment: start.comment, Line: start.line}
+	if p.is("repeated") || p.is("optional") || p.is("required") {
+		field.Label = p.next().text
+	}
+	if p.is("map") && p.tokens[p.pos+1].text == "<" {
+		p.next()
+		p.next()
+		keyType, err := p.expectKind(protoTokenIdent, "map key type")
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(","); err != nil {
+			return nil, err
+		}
+		valueType, err := p.expectKind(protoTokenIdent, "map value type")
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(">"); err != nil {
+			return nil, err
+		}
+		field.MapKeyType = keyType.text
+		field.Type = valueType.text
+	} else {
+		fieldType, err := p.expectKind(protoTokenIdent, "field type")
+		if err != nil {
+			return nil, err
+		}
+		field.Type = fieldType.text
+	}
+	name, err := p.expectKind(protoTokenIdent, "field name")
+	if err != nil {
+		return nil, err
+	}
+	field.Name = name.text
+	if _, err := p.expect("="); err != nil {
+		return nil, err
+	}
+	if field.Number, err = p.parseNumber(); err != nil {
+		return nil, err
+	}
+	if field.Options, err = p.parseFieldOptions(); err != nil {
+		return nil, err
+	}
+	field.JsonName = getProtoOption(field.Options, "json_name")
+	if field.JsonName == "" {
+		field.JsonName = protoJsonName(field.Name)
+	}
+	if _, err := p.expect(";"); err != nil {
+		return nil, err
+	}
+	return field, nil
+}
+
+func (p *protoParser) parseEnum() (*ProtoEnum, error) {
+	start := p.next()
+	name, err := p.expectKind(protoTokenIdent, "enum name")
+	if err != nil {
+		return nil, err
+	}
+	enum := &ProtoEnum{
+		Name:    name.text,
+		Values:  make([]*ProtoEnumValue, 0),
+		Options: make([]*ProtoOption, 0),
+		Comment: start.comment,
+		Line:    start.line,
+	}
+	if _, err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	for !p.accept("}") {
+		token := p.peek()
+		switch {
+		case token.kind == protoTokenEOF:
+			return nil, p.errorf(token, "expected \"}\", found end of file")
+		case p.accept(";"):
+		case p.accept("option"):
+			option, _, err := p.parseOptionStatement(token)
+			if err != nil {
+				return nil, err
+			}
+			enum.Options = append(enum.Options, option)
+		case p.is("reserved"):
+			if err := p.skipStatement(); err != nil {
+				return nil, err
+			}
+		default:
+			valueName, err := p.expectKind(protoTokenIdent, "enum value name")
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.expect("="); err != nil {
+				return nil, err
+			}
+			value := &ProtoEnumValue{Name: valueName.text, Comment: token.comment, Line: token.line}
+			if value.Number, err = p.parseNumber(); err != nil {
+				return nil, err
+			}
+			if value.Options, err = p.parseFieldOptions(); err != nil {
+				return nil, err
+			}
+			if _, err := p.expect(";"); err != nil {
+				return nil, err
+			}
+			enum.Values = append(enum.Values, value)
+		}
+	}
+	return enum, nil
+}
+
+func (p *protoParser) parseService() (*ProtoService, error) {
+	start := p.next()
+	name, err := p.expectKind(protoTokenIdent, "service name")
+	if err != nil {
+		return nil, err
+	}
+	service := &ProtoService{
+		Name:    name.text,
+		Rpcs:    make([]*ProtoRpc, 0),
+		Options: make([]*ProtoOption, 0),
+		Comment: start.comment,
+		Line:    start.line,
+	}
+	if _, err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	for !p.accept("}") {
+		token := p.peek()
+		switch {
+		case token.kind == protoTokenEOF:
+			return nil, p.errorf(token, "expected \"}\", found end of file")
+		case p.accept(";"):
+		case p.accept("option"):
+			option, _, err := p.parseOptionStatement(token)
+			if err != nil {
+				return nil, err
+			}
+			service.Options = append(service.Options, option)
+		case p.is("rpc"):
+			rpc, err := p.parseRpc()
+			if err != nil {
+				return nil, err
+			}
+			service.Rpcs = append(service.Rpcs, rpc)
+		default:
+			return nil, p.errorf(token, "unexpected %q in service", token.text)
+		}
+	}
+	return service, nil
+}
+
+// parseRpc parses "rpc Name ([stream] Req) returns ([stream] Resp) { options }"
+//
+// parseRpc 解析 "rpc Name ([stream] Req) returns ([stream] Resp) { options }"
+func (p *protoParser) parseRpc() (*ProtoRpc, error) {
+	start := p.next()
+	name, err := p.expectKind(protoTokenIdent, "rpc name")
+	if err != nil {
+		return nil, err
+	}
+	rpc := &ProtoRpc{
+		Name:      name.text,
+		HttpRules: make([]*ProtoHttpRule, 0),
+		Options:   make([]*ProtoOption, 0),
+		Comment:   start.comment,
+		Line:      start.line,
+	}
+	if rpc.ClientStreaming, rpc.RequestType, err = p.parseRpcType(); err != nil {
+		return nil, err
+	}
+	if _, err := p.expect("returns"); err != nil {
+		return nil, err
+	}
+	if rpc.ServerStreaming, rpc.ResponseType, err = p.parseRpcType(); err != nil {
+		return nil, err
+	}
+	if p.accept(";") {
+		return rpc, nil
+	}
+	if _, err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	for !p.accept("}") {
+		token := p.peek()
+		switch {
+		case token.kind == protoTokenEOF:
+			return nil, p.errorf(token, "expected \"}\", found end of file")
+		case p.accept(";"):
+		case p.accept("option"):
+			option, fields, err := p.parseOptionStatement(token)
+			if err != nil {
+				return nil, err
+			}
+			rpc.Options = append(rpc.Options, option)
+			if option.Name == "(google.api.http)" {
+				rpc.HttpRules = append(rpc.HttpRules, newProtoHttpRules(fields, option.Line)...)
+			}
+		default:
+			return nil, p.errorf(token, "unexpected %q in rpc", token.text)
+		}
+	}
+	p.accept(";")
+	return rpc, nil
+}
+
+// parseRpcType parses "([stream] Type)"
+//
+// parseRpcType 解析 "([stream] Type)"
+func (p *protoParser) parseRpcType() (bool, string, error) {
+	if _, err := p.expect("("); err != nil {
+		return false, "", err
+	}
+	streaming := false
+	if p.is("stream") && p.tokens[p.pos+1].kind == protoTokenIdent {
+		p.next()
+		streaming = true
+	}
+	typeName, err := p.expectKind(protoTokenIdent, "message type")
+	if err != nil {
+		return false, "", err
+	}
+	if _, err := p.expect(")"); err != nil {
+		return false, "", err
+	}
+	return streaming, typeName.text, nil
+}
+
+// skipStatement skips a statement ending with a semicolon or a balanced brace block
+//
+// skipStatement 跳过以分号结尾或以配对大括号结尾的语句
+func (p *protoParser) skipStatement() error {
+	depth := 0
+	for {
+		token := p.next()
+		switch {
+		case token.kind == protoTokenEOF:
+			return p.errorf(token, "unexpected end of file")
+		case token.kind == protoTokenSymbol && token.text == "{":
+			depth++
+		case token.kind == protoTokenSymbol && token.text == "}":
+			depth--
+			if depth <= 0 {
+				return nil
+			}
+		case token.kind == protoTokenSymbol && token.text == ";" && depth == 0:
+			return nil
+		}
+	}
+}
+
+// newProtoHttpRules converts the google.api.http aggregate into HTTP rules, additional bindings included
+//
+// newProtoHttpRules 将 google.api.http 聚合值转换为 HTTP 规则，包含附加绑定
+func newProtoHttpRules(fields []*protoTextField, line int) []*ProtoHttpRule {
+	rule := &ProtoHttpRule{Line: line}
+	var rules []*ProtoHttpRule
+	var additional []*ProtoHttpRule
+	for _, field := range fields {
+		switch field.name {
+		case "get", "put", "post", "delete", "patch":
+			rule.Method = strings.ToUpper(field.name)
+			rule.Path = field.value
+		case "custom":
+			for _, custom := range field.fields {
+				switch custom.name {
+				case "kind":
+					rule.Method = custom.value
+				case "path":
+					rule.Path = custom.value
+				}
+			}
+		case "body":
+			rule.Body = field.value
+		case "response_body":
+			rule.ResponseBody = field.value
+		case "additional_bindings":
+			additional = append(additional, newProtoHttpRules(field.fields, field.line)...)
+		}
+	}
+	if rule.Method != "" {
+		rules = append(rules, rule)
+	}
+	return append(rules, additional...)
+}
@@ -0,0 +1,111 @@
+package astkratos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+)
+
+// TestParseProtoSource_Comments tests that only the comment block right above an element is kept
+//
+// TestParseProtoSource_Comments 测试只保留紧贴在元素上方的注释块
+func TestParseProtoSource_Comments(t *testing.T) {
+	source := `syntax = "proto3";
+package demo.v1;
+
+// detached comment
+
+// first line
+// second line
+message Demo {
+  int32 a = 1; // trailing comment of a
+  int32 b = 2;
+  /* block comment of c */
+  int32 c = 3;
+}
+`
+	protoFile := rese.P1(parseProtoSource("demo.proto", []byte(source)))
+	message := protoFile.Messages[0]
+	require.Equal(t, "first line\nsecond line", message.Comment)
+	require.Equal(t, "", message.Fields[0].Comment)
+	require.Equal(t, "", message.Fields[1].Comment)
+	require.Equal(t, "block comment of c", message.Fields[2].Comment)
+	require.Equal(t, 12, message.Fields[2].Line)
+}
+
+// TestParseProtoSource_Options tests option names, aggregate values, custom HTTP rules and skipped blocks
+//
+// TestParseProtoSource_Options 测试选项名称、聚合值、自定义 HTTP 规则和跳过的语句块
+func TestParseProtoSource_Options(t *testing.T) {
+	source := `syntax = "proto3";
+package demo.v1;
+import public "other.proto";
+option go_package = "demo/api/v1" ";v1";
+
+extend google.protobuf.FieldOptions { string tag = 50000; }
+
+service Demo {
+  option deprecated = true;
+  rpc Head(Req) returns (Resp) {
+    option (google.api.http) = { custom: { kind: "HEAD" path: "/v1/{id=items/*}" } response_body: "data" };
+  };
+}
+
+message Req {
+  reserved 2 to 5;
+  string id = 1 [(validate.rules).string.min_len = 1, deprecated = true];
+  sint32 delta = 6 [default = -1];
+  int32 mask = 0x07;
+}
+`
+	protoFile := rese.P1(parseProtoSource("demo.proto", []byte(source)))
+	require.Equal(t, "demo/api/v1;v1", protoFile.GetOption("go_package"))
+	require.Equal(t, "public", protoFile.Imports[0].Modifier)
+
+	rpc := protoFile.Services[0].Rpcs[0]
+	require.Equal(t, []*ProtoHttpRule{{Method: "HEAD", Path: "/v1/{id=items/*}", ResponseBody: "data", Line: 11}}, rpc.HttpRules)
+	require.Equal(t, `custom: { kind: "HEAD" path: "/v1/{id=items/*}" } response_body: "data"`, rpc.Options[0].Value)
+
+	fields := protoFile.Messages[0].Fields
+	require.Equal(t, "(validate.rules).string.min_len", fields[0].Options[0].Name)
+	require.Equal(t, "1", fields[0].Options[0].Value)
+	require.Equal(t, "-1", fields[1].Options[0].Value)
+	require.Equal(t, 7, fields[2].Number)
+}
+
+// TestParseProtoSource_Numbers tests float literals with fractions, exponents and signs in option values
+//
+// TestParseProtoSource_Numbers 测试选项值中带小数、指数和符号的浮点字面量
+func TestParseProtoSource_Numbers(t *testing.T) {
+	source := `syntax = "proto3";
+package demo.v1;
+
+message Req {
+  double rate = 1 [(validate.rules).double = { gte: -0.5, lte: 1.5e-3 }];
+  float ratio = 2 [default = -.25];
+  double scale = 3 [default = 2E+10];
+  int32 mask = 0X1f;
+}
+`
+	protoFile := rese.P1(parseProtoSource("demo.proto", []byte(source)))
+	fields := protoFile.Messages[0].Fields
+	require.Len(t, fields, 4)
+	require.Equal(t, "gte: -0.5, lte: 1.5e-3", fields[0].Options[0].Value)
+	require.Equal(t, "-.25", fields[1].Options[0].Value)
+	require.Equal(t, "2E+10", fields[2].Options[0].Value)
+	require.Equal(t, 31, fields[3].Number)
+}
+
+// TestParseProtoSource_Error tests that syntax errors carry the path and position
+//
+// TestParseProtoSource_Error 测试语法错误带有路径和位置
+func TestParseProtoSource_Error(t *testing.T) {
+	_, err := parseProtoSource("broken.proto", []byte("syntax = \"proto3\";\nmessage Broken {\n  string name 1;\n}\n"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "broken.proto:3:15")
+
+	_, err = parseProtoSource("broken.proto", []byte("message Broken {\n  string name = \"x\n}\n"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unterminated string")
+}
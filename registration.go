@@ -0,0 +1,178 @@
+// Package astkratos service registration: Detection of services that are never registered on a server
+// Finds the RegisterXxxServer and RegisterXxxHTTPServer calls in internal/server
+// Takes the RegisterXxxHTTPServer functions from the _http.pb.go files, since a service
+// named FooHTTP has a RegisterFooHTTPServer function of its own
+// Builds an exposure matrix telling whether each service is served over gRPC, HTTP, both or neither
+//
+// astkratos 服务注册：检测从未注册到服务器上的服务
+// 查找 internal/server 中的 RegisterXxxServer 和 RegisterXxxHTTPServer 调用
+// RegisterXxxHTTPServer 函数取自 _http.pb.go 文件，因为名为 FooHTTP 的服务
+// 自身就有 RegisterFooHTTPServer 函数
+// 构建暴露矩阵，说明每个服务是通过 gRPC、HTTP、两者还是都未对外提供
+package astkratos
+
+import (
+	"go/ast"
+	"path/filepath"
+	"slices"
+
+	"github.com/yyle88/erero"
+)
+
+// ServiceTransport represents the transport a service is registered on
+//
+// ServiceTransport 表示服务注册所在的传输协议
+type ServiceTransport string
+
+const (
+	ServiceTransportGrpc ServiceTransport = "grpc" // Registered with RegisterXxxServer // 通过 RegisterXxxServer 注册
+	ServiceTransportHttp ServiceTransport = "http" // Registered with RegisterXxxHTTPServer // 通过 RegisterXxxHTTPServer 注册
+)
+
+// ServiceRegistration represents a registration call of a service on a server
+//
+// ServiceRegistration 表示服务在服务器上的注册调用
+type ServiceRegistration struct {
+	Service        string           // gRPC service name, such as Greeter // gRPC 服务名称，例如 Greeter
+	ServiceSrcPath string           // Generated _grpc.pb.go file of the service // 服务所在的生成 _grpc.pb.go 文件
+	Transport      ServiceTransport // Transport of the registration // 注册的传输协议
+	Call           string           // Called function as written, such as v1.RegisterGreeterServer // 源码中被调用的函数，例如 v1.RegisterGreeterServer
+	Position       Position         // Position of the call // 调用所在的位置
+}
+
+// ServiceExposureKind represents how a service is exposed
+//
+// ServiceExposureKind 表示服务的对外暴露方式
+type ServiceExposureKind string
+
+const (
+	ServiceExposedGrpc ServiceExposureKind = "grpc" // Served over gRPC only // 仅通过 gRPC 提供
+	ServiceExposedHttp ServiceExposureKind = "http" // Served over HTTP only // 仅通过 HTTP 提供
+	ServiceExposedBoth ServiceExposureKind = "both" // Served over gRPC and HTTP // 同时通过 gRPC 和 HTTP 提供
+	ServiceExposedNone ServiceExposureKind = "none" // Never registered // 从未注册
+)
+
+// ServiceExposure represents one row of the exposure matrix
+//
+// ServiceExposure 表示暴露矩阵中的一行
+type ServiceExposure struct {
+	Service       string                 // gRPC service name, such as Greeter // gRPC 服务名称，例如 Greeter
+	Package       string                 // Package name of the generated code // 生成代码的包名
+	SrcPath       string                 // Generated _grpc.pb.go file of the service // 服务所在的生成 _grpc.pb.go 文件
+	Exposure      ServiceExposureKind    // gRPC, HTTP, both or none // gRPC、HTTP、两者或都没有
+	Registrations []*ServiceRegistration // Registration calls of the service // 该服务的注册调用
+}
+
+// analyzeServiceRegistrations finds the registration calls of the services under the server root
+// A RegisterXxxHTTPServer call counts as HTTP only when one of the _http.pb.go files declares the function
+//
+// analyzeServiceRegistrations 查找服务器根目录下服务的注册调用
+// 仅当某个 _http.pb.go 文件声明了 RegisterXxxHTTPServer 函数时，对它的调用才算作 HTTP 注册
+func analyzeServiceRegistrations(projectRoot string, serverRoot string, services []*GrpcTypeDefinition, httpFiles []*httpPbGoFile) ([]*ServiceRegistration, error) {
+	projectRoot, err := filepath.Abs(projectRoot)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	packages, err := loadGoPackages(serverRoot)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+
+	// The same function name may register different services in different packages
+	// 同一函数名可能在不同的包中注册不同的服务
+	type registerTarget struct {
+		service   *GrpcTypeDefinition
+		transport ServiceTransport
+	}
+	registerTargets := map[string][]*registerTarget{}
+	for _, service := range services {
+		name := "Register" + service.Name + "Server"
+		registerTargets[name] = append(registerTargets[name], &registerTarget{service: service, transport: ServiceTransportGrpc})
+	}
+	for _, httpFile := range httpFiles {
+		for _, serviceName := range httpFile.services {
+			for _, service := range services {
+				if service.Name == serviceName && filepath.Dir(service.SrcPath) == filepath.Dir(httpFile.srcPath) {
+					name := "Register" + service.Name + "HTTPServer"
+					registerTargets[name] = append(registerTargets[name], &registerTarget{service: service, transport: ServiceTransportHttp})
+				}
+			}
+		}
+	}
+
+	registrations := make([]*ServiceRegistration, 0)
+	for _, pkg := range packages {
+		for _, goFile := range pkg.files {
+			imports := importPaths(goFile.astFile)
+			ast.Inspect(goFile.astFile, func(node ast.Node) bool {
+				callExpr, ok := node.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				selectorExpr, ok := callExpr.Fun.(*ast.SelectorExpr)
+				if !ok {
+					return true
+				}
+				importName, ok := selectorExpr.X.(*ast.Ident)
+				if !ok {
+					return true
+				}
+				targets, ok := registerTargets[selectorExpr.Sel.Name]
+				if !ok {
+					return true
+				}
+				candidates := make([]*GrpcTypeDefinition, 0, len(targets))
+				for _, target := range targets {
+					candidates = append(candidates, target.service)
+				}
+				service := matchServicePackage(projectRoot, candidates, imports[importName.Name])
+				if service == nil {
+					return true
+				}
+				target := targets[slices.Index(candidates, service)]
+				registrations = append(registrations, &ServiceRegistration{
+					Service:        service.Name,
+					ServiceSrcPath: service.SrcPath,
+					Transport:      target.transport,
+					Call:           importName.Name + "." + selectorExpr.Sel.Name,
+					Position:       Position{Path: goFile.srcPath, Line: goFile.fset.Position(callExpr.Pos()).Line},
+				})
+				return true
+			})
+		}
+	}
+	return registrations, nil
+}
+
+// newServiceExposures builds the exposure matrix with one row per service
+//
+// newServiceExposures 构建每个服务一行的暴露矩阵
+func newServiceExposures(services []*GrpcTypeDefinition, registrations []*ServiceRegistration) []*ServiceExposure {
+	exposures := make([]*ServiceExposure, 0, len(services))
+	for _, service := range services {
+		exposure := &ServiceExposure{
+			Service:       service.Name,
+			Package:       service.Package,
+			SrcPath:       service.SrcPath,
+			Exposure:      ServiceExposedNone,
+			Registrations: make([]*ServiceRegistration, 0),
+		}
+		transports := map[ServiceTransport]bool{}
+		for _, registration := range registrations {
+			if registration.Service == service.Name && registration.ServiceSrcPath == service.SrcPath {
+				exposure.Registrations = append(exposure.Registrations, registration)
+				transports[registration.Transport] = true
+			}
+		}
+		switch {
+		case transports[ServiceTransportGrpc] && transports[ServiceTransportHttp]:
+			exposure.Exposure = ServiceExposedBoth
+		case transports[ServiceTransportGrpc]:
+			exposure.Exposure = ServiceExposedGrpc
+		case transports[ServiceTransportHttp]:
+			exposure.Exposure = ServiceExposedHttp
+		}
+		exposures = append(exposures, exposure)
+	}
+	return exposures
+}
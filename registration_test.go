@@ -0,0 +1,165 @@
+package astkratos_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/orzkratos/astkratos"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/neatjson/neatjsons"
+	"github.com/yyle88/rese"
+	"github.com/yyle88/runpath"
+)
+
+// TestListServiceExposures tests the exposure kind and the registration calls of each demo service
+//
+// TestListServiceExposures 测试每个演示服务的暴露方式和注册调用
+func TestListServiceExposures(t *testing.T) {
+	exposures := astkratos.ListServiceExposures(runpath.PARENT.Join("testdata", "demokratos"))
+	t.Log(neatjsons.S(exposures))
+	require.Len(t, exposures, 3)
+
+	grpcPath := runpath.PARENT.Join("testdata", "demokratos", "internal", "server", "grpc.go")
+	httpPath := runpath.PARENT.Join("testdata", "demokratos", "internal", "server", "http.go")
+
+	echo := exposures[0]
+	require.Equal(t, "Echo", echo.Service)
+	require.Equal(t, filepath.Join(demoApiRoot, "echo", "v1", "echo_grpc.pb.go"), echo.SrcPath)
+	require.Equal(t, astkratos.ServiceExposedGrpc, echo.Exposure)
+	require.Equal(t, []*astkratos.ServiceRegistration{
+		{
+			Service:        "Echo",
+			ServiceSrcPath: echo.SrcPath,
+			Transport:      astkratos.ServiceTransportGrpc,
+			Call:           "echov1.RegisterEchoServer",
+			Position:       astkratos.Position{Path: grpcPath, Line: 32},
+		},
+	}, echo.Registrations)
+
+	greeter := exposures[1]
+	require.Equal(t, "Greeter", greeter.Service)
+	require.Equal(t, astkratos.ServiceExposedBoth, greeter.Exposure)
+	require.Equal(t, []*astkratos.ServiceRegistration{
+		{
+			Service:        "Greeter",
+			ServiceSrcPath: greeter.SrcPath,
+			Transport:      astkratos.ServiceTransportGrpc,
+			Call:           "v1.RegisterGreeterServer",
+			Position:       astkratos.Position{Path: grpcPath, Line: 31},
+		},
+		{
+			Service:        "Greeter",
+			ServiceSrcPath: greeter.SrcPath,
+			Transport:      astkratos.ServiceTransportHttp,
+			Call:           "v1.RegisterGreeterHTTPServer",
+			Position:       astkratos.Position{Path: httpPath, Line: 30},
+		},
+	}, greeter.Registrations)
+	require.Equal(t, httpPath+":30", greeter.Registrations[1].Position.String())
+
+	legacy := exposures[2]
+	require.Equal(t, "Legacy", legacy.Service)
+	require.Equal(t, astkratos.ServiceExposedNone, legacy.Exposure)
+	require.Empty(t, legacy.Registrations)
+}
+
+// TestListServiceExposures_HttpOnly tests a service registered on the HTTP server alone, with a renamed import
+//
+// TestListServiceExposures_HttpOnly 测试仅注册在 HTTP 服务器上且导入被重命名的服务
+func TestListServiceExposures_HttpOnly(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"api/echo/v1", "internal/server"} {
+		must.Done(os.MkdirAll(filepath.Join(root, dir), 0755))
+	}
+	must.Done(os.WriteFile(filepath.Join(root, "go.mod"), []byte("module shop\n\ngo 1.22\n"), 0644))
+	must.Done(os.WriteFile(filepath.Join(root, "api", "echo", "v1", "echo_grpc.pb.go"), rese.V1(os.ReadFile(filepath.Join(demoApiRoot, "echo", "v1", "echo_grpc.pb.go"))), 0644))
+	must.Done(os.WriteFile(filepath.Join(root, "api", "echo", "v1", "echo_http.pb.go"), []byte(`package v1
+
+import http "github.com/go-kratos/kratos/v2/transport/http"
+
+type EchoHTTPServer interface{}
+
+func RegisterEchoHTTPServer(s *http.Server, srv EchoHTTPServer) {}
+`), 0644))
+	httpPath := filepath.Join(root, "internal", "server", "http.go")
+	must.Done(os.WriteFile(httpPath, []byte(`package server
+
+import (
+	"github.com/go-kratos/kratos/v2/transport/http"
+
+	pb "shop/api/echo/v1"
+)
+
+func NewHTTPServer(echo pb.EchoHTTPServer) *http.Server {
+	srv := http.NewServer()
+	pb.RegisterEchoHTTPServer(srv, echo)
+	return srv
+}
+`), 0644))
+
+	exposures := astkratos.ListServiceExposures(root)
+	t.Log(neatjsons.S(exposures))
+	require.Len(t, exposures, 1)
+	require.Equal(t, astkratos.ServiceExposedHttp, exposures[0].Exposure)
+	require.Len(t, exposures[0].Registrations, 1)
+	require.Equal(t, astkratos.ServiceTransportHttp, exposures[0].Registrations[0].Transport)
+	require.Equal(t, "pb.RegisterEchoHTTPServer", exposures[0].Registrations[0].Call)
+	require.Equal(t, astkratos.Position{Path: httpPath, Line: 11}, exposures[0].Registrations[0].Position)
+}
+
+// TestListServiceExposures_HttpSuffixName tests that a service named FooHTTP is not taken as the HTTP binding of Foo
+//
+// TestListServiceExposures_HttpSuffixName 测试名为 FooHTTP 的服务不会被当作 Foo 的 HTTP 绑定
+func TestListServiceExposures_HttpSuffixName(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"api/gateway/v1", "api/foo/v1", "internal/server"} {
+		must.Done(os.MkdirAll(filepath.Join(root, dir), 0755))
+	}
+	must.Done(os.WriteFile(filepath.Join(root, "go.mod"), []byte("module shop\n\ngo 1.22\n"), 0644))
+	echoSource := string(rese.V1(os.ReadFile(filepath.Join(demoApiRoot, "echo", "v1", "echo_grpc.pb.go"))))
+	must.Done(os.WriteFile(filepath.Join(root, "api", "gateway", "v1", "gateway_grpc.pb.go"), []byte(strings.ReplaceAll(echoSource, "Echo", "FooHTTP")), 0644))
+	must.Done(os.WriteFile(filepath.Join(root, "api", "foo", "v1", "foo_grpc.pb.go"), []byte(strings.ReplaceAll(echoSource, "Echo", "Foo")), 0644))
+	must.Done(os.WriteFile(filepath.Join(root, "api", "foo", "v1", "foo_http.pb.go"), []byte(`package v1
+
+import http "github.com/go-kratos/kratos/v2/transport/http"
+
+type FooHTTPServer interface{}
+
+func RegisterFooHTTPServer(s *http.Server, srv FooHTTPServer) {}
+`), 0644))
+	must.Done(os.WriteFile(filepath.Join(root, "internal", "server", "server.go"), []byte(`package server
+
+import (
+	"github.com/go-kratos/kratos/v2/transport/grpc"
+	"github.com/go-kratos/kratos/v2/transport/http"
+
+	foov1 "shop/api/foo/v1"
+	gatewayv1 "shop/api/gateway/v1"
+)
+
+func NewGRPCServer(gateway gatewayv1.FooHTTPServer) *grpc.Server {
+	srv := grpc.NewServer()
+	gatewayv1.RegisterFooHTTPServer(srv, gateway)
+	return srv
+}
+
+func NewHTTPServer(foo foov1.FooHTTPServer) *http.Server {
+	srv := http.NewServer()
+	foov1.RegisterFooHTTPServer(srv, foo)
+	return srv
+}
+`), 0644))
+
+	exposures := astkratos.ListServiceExposures(root)
+	t.Log(neatjsons.S(exposures))
+	require.Len(t, exposures, 2)
+	require.Equal(t, "Foo", exposures[0].Service)
+	require.Equal(t, astkratos.ServiceExposedHttp, exposures[0].Exposure)
+	require.Equal(t, "foov1.RegisterFooHTTPServer", exposures[0].Registrations[0].Call)
+	require.Equal(t, "FooHTTP", exposures[1].Service)
+	require.Equal(t, astkratos.ServiceExposedGrpc, exposures[1].Exposure)
+	require.Equal(t, "gatewayv1.RegisterFooHTTPServer", exposures[1].Registrations[0].Call)
+}
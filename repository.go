@@ -0,0 +1,341 @@
+// Package astkratos repository: Matching of biz repo interfaces to their data implementations
+// Collects the repo interfaces of internal/biz and the data constructors returning them
+// Falls back to wire.Bind when the constructor returns the struct itself
+// Compares the method sets and reports missing methods and mismatched signatures
+//
+// astkratos 仓储：将 biz 仓储接口与 data 实现进行匹配
+// 收集 internal/biz 中的仓储接口以及返回这些接口的 data 构造函数
+// 当构造函数直接返回结构体时回退使用 wire.Bind
+// 比较方法集并报告缺失的方法和签名不一致的方法
+package astkratos
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"github.com/yyle88/erero"
+)
+
+// RepoMethodMismatch represents a method whose implementation signature differs from the interface
+//
+// RepoMethodMismatch 表示实现签名与接口不一致的方法
+type RepoMethodMismatch struct {
+	Method   string   // Method name // 方法名
+	Expected string   // Signature declared in the interface // 接口中声明的签名
+	Actual   string   // Signature of the implementation // 实现的签名
+	Position Position // Position of the implementation method // 实现方法的位置
+}
+
+// RepoImplementation links a biz repo interface to the data struct implementing it
+//
+// RepoImplementation 将 biz 仓储接口关联到实现它的 data 结构体
+type RepoImplementation struct {
+	Interface           string                // Interface name, such as GreeterRepo // 接口名称，例如 GreeterRepo
+	Package             string                // Package name of the interface // 接口所在的包名
+	Position            Position              // Position of the interface // 接口的位置
+	Methods             []string              // Methods declared in the interface // 接口中声明的方法
+	StructName          string                // Implementation struct name, empty when none // 实现结构体名称，没有时为空
+	StructPackage       string                // Package name of the struct // 结构体所在的包名
+	StructPosition      Position              // Position of the struct // 结构体的位置
+	Constructor         string                // Constructor returning the interface, such as NewGreeterRepo // 返回该接口的构造函数，例如 NewGreeterRepo
+	ConstructorPosition Position              // Position of the constructor // 构造函数的位置
+	MissingMethods      []string              // Interface methods the struct lacks // 结构体缺少的接口方法
+	MismatchedMethods   []*RepoMethodMismatch // Methods with a different signature // 签名不一致的方法
+}
+
+// IsImplemented reports whether a struct implements every method with the declared signature
+//
+// IsImplemented 判断是否有结构体以声明的签名实现了所有方法
+func (r *RepoImplementation) IsImplemented() bool {
+	return r.StructName != "" && len(r.MissingMethods) == 0 && len(r.MismatchedMethods) == 0
+}
+
+// repoInterface holds a biz interface with the file declaring it
+//
+// repoInterface 保存 biz 接口及其声明文件
+type repoInterface struct {
+	pkg           *goPackage          // Declaring package // 声明包
+	goFile        *parsedGoFile       // Declaring file // 声明文件
+	interfaceType *ast.InterfaceType  // Interface type // 接口类型
+	result        *RepoImplementation // Result being filled // 正在填充的结果
+}
+
+// repoMethod holds the name, signature and position of a method
+//
+// repoMethod 保存方法的名称、签名和位置
+type repoMethod struct {
+	name      string   // Method name // 方法名
+	signature string   // Qualified signature // 限定签名
+	position  Position // Position of the method, empty in interfaces // 方法的位置，接口中为空
+}
+
+// analyzeRepoImplementations links the repo interfaces of biz to the structs of data
+//
+// analyzeRepoImplementations 将 biz 的仓储接口关联到 data 的结构体
+func analyzeRepoImplementations(projectRoot string, modulePath string) ([]*RepoImplementation, error) {
+	project, err := loadWireProject(projectRoot, modulePath)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return project.repoImplementations(modulePath), nil
+}
+
+// repoImplementations matches the interfaces through the data constructors and wire.Bind, then compares methods
+// Interfaces named XxxRepo or XxxRepository are included, and so are the interfaces a data constructor returns
+//
+// repoImplementations 通过 data 构造函数和 wire.Bind 匹配接口，然后比较方法
+// 名为 XxxRepo 或 XxxRepository 的接口会被包含，data 构造函数返回的接口同样会被包含
+func (p *wireProject) repoImplementations(modulePath string) []*RepoImplementation {
+	var interfaceKeys []string
+	interfaces := map[string]*repoInterface{}
+	for _, pkg := range p.packages {
+		if !isLayerImportPath(modulePath, p.builder.importPaths[pkg], "biz") {
+			continue
+		}
+		for _, goFile := range pkg.files {
+			for _, decl := range goFile.astFile.Decls {
+				genDecl, ok := decl.(*ast.GenDecl)
+				if !ok || genDecl.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range genDecl.Specs {
+					typeSpec := spec.(*ast.TypeSpec)
+					interfaceType, ok := typeSpec.Type.(*ast.InterfaceType)
+					if !ok {
+						continue
+					}
+					key := p.builder.importPaths[pkg] + "." + typeSpec.Name.Name
+					interfaceKeys = append(interfaceKeys, key)
+					interfaces[key] = &repoInterface{
+						pkg:           pkg,
+						goFile:        goFile,
+						interfaceType: interfaceType,
+						result: &RepoImplementation{
+							Interface:         typeSpec.Name.Name,
+							Package:           pkg.name,
+							Position:          Position{Path: goFile.srcPath, Line: goFile.fset.Position(typeSpec.Pos()).Line},
+							Methods:           make([]string, 0),
+							MissingMethods:    make([]string, 0),
+							MismatchedMethods: make([]*RepoMethodMismatch, 0),
+						},
+					}
+				}
+			}
+		}
+	}
+
+	var dataPackages []*goPackage
+	for _, pkg := range p.packages {
+		if isLayerImportPath(modulePath, p.builder.importPaths[pkg], "data") {
+			dataPackages = append(dataPackages, pkg)
+		}
+	}
+	matched := map[string]*goPackage{}
+	for _, pkg := range dataPackages {
+		for _, goFile := range pkg.files {
+			imports := importPaths(goFile.astFile)
+			for _, decl := range goFile.astFile.Decls {
+				funcDecl, ok := decl.(*ast.FuncDecl)
+				if !ok || funcDecl.Recv != nil || funcDecl.Body == nil || funcDecl.Type.Results == nil || len(funcDecl.Type.Results.List) == 0 {
+					continue
+				}
+				selectorExpr, ok := funcDecl.Type.Results.List[0].Type.(*ast.SelectorExpr)
+				if !ok {
+					continue
+				}
+				importName, ok := selectorExpr.X.(*ast.Ident)
+				if !ok {
+					continue
+				}
+				key := imports[importName.Name] + "." + selectorExpr.Sel.Name
+				item, ok := interfaces[key]
+				if !ok || item.result.StructName != "" {
+					continue
+				}
+				structName := returnedStructName(funcDecl)
+				if structName == "" {
+					continue
+				}
+				item.result.StructName = structName
+				item.result.StructPackage = pkg.name
+				item.result.Constructor = funcDecl.Name.Name
+				item.result.ConstructorPosition = Position{Path: goFile.srcPath, Line: goFile.fset.Position(funcDecl.Pos()).Line}
+				matched[key] = pkg
+			}
+		}
+	}
+
+	// Fall back to wire.Bind(new(biz.XxxRepo), new(*xxxRepo)) for constructors returning the struct
+	// 对返回结构体的构造函数回退使用 wire.Bind(new(biz.XxxRepo), new(*xxxRepo))
+	for _, providerSet := range p.graph.ProviderSets {
+		for _, provider := range providerSet.Providers {
+			if provider.Kind != WireProviderKindBind {
+				continue
+			}
+			for _, key := range interfaceKeys {
+				item := interfaces[key]
+				if item.result.StructName != "" || provider.Type != item.result.Package+"."+item.result.Interface {
+					continue
+				}
+				for _, pkg := range dataPackages {
+					structName, ok := strings.CutPrefix(strings.TrimPrefix(provider.Implementation, "*"), pkg.name+".")
+					if ok && p.builder.importPaths[pkg] == providerSet.ImportPath {
+						item.result.StructName = structName
+						item.result.StructPackage = pkg.name
+						matched[key] = pkg
+					}
+				}
+			}
+		}
+	}
+
+	implementations := make([]*RepoImplementation, 0)
+	for _, key := range interfaceKeys {
+		item := interfaces[key]
+		name := item.result.Interface
+		if _, ok := matched[key]; !ok && !strings.HasSuffix(name, "Repo") && !strings.HasSuffix(name, "Repository") {
+			continue
+		}
+		expected := p.interfaceMethods(item, map[*ast.InterfaceType]bool{})
+		for _, method := range expected {
+			item.result.Methods = append(item.result.Methods, method.name)
+		}
+		if pkg, ok := matched[key]; ok {
+			item.result.StructPosition = findStructPosition(pkg, item.result.StructName)
+			actual := structMethods(pkg, item.result.StructName)
+			for _, method := range expected {
+				implemented, ok := actual[method.name]
+				if !ok {
+					item.result.MissingMethods = append(item.result.MissingMethods, method.name)
+				} else if implemented.signature != method.signature {
+					item.result.MismatchedMethods = append(item.result.MismatchedMethods, &RepoMethodMismatch{
+						Method:   method.name,
+						Expected: method.signature,
+						Actual:   implemented.signature,
+						Position: implemented.position,
+					})
+				}
+			}
+		}
+		implementations = append(implementations, item.result)
+	}
+	return implementations
+}
+
+// interfaceMethods lists the methods of the interface, expanding embedded interfaces of the same package
+//
+// interfaceMethods 列出接口的方法，并展开同一包中嵌入的接口
+func (p *wireProject) interfaceMethods(item *repoInterface, visited map[*ast.InterfaceType]bool) []*repoMethod {
+	if visited[item.interfaceType] {
+		return nil
+	}
+	visited[item.interfaceType] = true
+	imports := importPaths(item.goFile.astFile)
+	var methods []*repoMethod
+	for _, field := range item.interfaceType.Methods.List {
+		funcType, ok := field.Type.(*ast.FuncType)
+		if !ok {
+			if ident, ok := field.Type.(*ast.Ident); ok {
+				if embedded := p.findInterface(item.pkg, ident.Name); embedded != nil {
+					methods = append(methods, p.interfaceMethods(embedded, visited)...)
+				}
+			}
+			continue
+		}
+		signature := newSignature(qualifiedFieldTypes(funcType.Params, item.pkg.name, imports), qualifiedFieldTypes(funcType.Results, item.pkg.name, imports))
+		for _, name := range field.Names {
+			methods = append(methods, &repoMethod{name: name.Name, signature: signature})
+		}
+	}
+	return methods
+}
+
+// findInterface finds the interface declared with the name in the package
+//
+// findInterface 在包中查找以该名称声明的接口
+func (p *wireProject) findInterface(pkg *goPackage, name string) *repoInterface {
+	for _, goFile := range pkg.files {
+		for _, decl := range goFile.astFile.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				if interfaceType, ok := typeSpec.Type.(*ast.InterfaceType); ok && typeSpec.Name.Name == name {
+					return &repoInterface{pkg: pkg, goFile: goFile, interfaceType: interfaceType}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// returnedStructName returns the struct name of the first return value, such as greeterRepo in return &greeterRepo{}
+//
+// returnedStructName 返回第一个返回值的结构体名称，例如 return &greeterRepo{} 中的 greeterRepo
+func returnedStructName(funcDecl *ast.FuncDecl) string {
+	var structName string
+	ast.Inspect(funcDecl.Body, func(node ast.Node) bool {
+		if _, ok := node.(*ast.FuncLit); ok {
+			return false
+		}
+		returnStmt, ok := node.(*ast.ReturnStmt)
+		if !ok || len(returnStmt.Results) == 0 || structName != "" {
+			return structName == ""
+		}
+		result := returnStmt.Results[0]
+		if unaryExpr, ok := result.(*ast.UnaryExpr); ok && unaryExpr.Op == token.AND {
+			result = unaryExpr.X
+		}
+		if compositeLit, ok := result.(*ast.CompositeLit); ok {
+			if ident, ok := compositeLit.Type.(*ast.Ident); ok {
+				structName = ident.Name
+			}
+		}
+		return structName == ""
+	})
+	return structName
+}
+
+// findStructPosition finds the struct declared with the name in the package, through the struct parsing of GetStructsMap
+//
+// findStructPosition 通过 GetStructsMap 的结构体解析，在包中查找以该名称声明的结构体
+func findStructPosition(pkg *goPackage, structName string) Position {
+	for _, goFile := range pkg.files {
+		if structDefinition, ok := newStructDefinitions(goFile.source, goFile.astFile)[structName]; ok {
+			return Position{Path: goFile.srcPath, Line: goFile.fset.Position(structDefinition.Type.Pos()).Line}
+		}
+	}
+	return Position{}
+}
+
+// structMethods collects the methods declared on the struct or its pointer, keyed by name
+//
+// structMethods 收集在结构体或其指针上声明的方法，以名称为键
+func structMethods(pkg *goPackage, structName string) map[string]*repoMethod {
+	methods := map[string]*repoMethod{}
+	for _, goFile := range pkg.files {
+		imports := importPaths(goFile.astFile)
+		for _, decl := range goFile.astFile.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 {
+				continue
+			}
+			recvType := funcDecl.Recv.List[0].Type
+			if starExpr, ok := recvType.(*ast.StarExpr); ok {
+				recvType = starExpr.X
+			}
+			if ident, ok := recvType.(*ast.Ident); !ok || ident.Name != structName {
+				continue
+			}
+			methods[funcDecl.Name.Name] = &repoMethod{
+				name:      funcDecl.Name.Name,
+				signature: newSignature(qualifiedFieldTypes(funcDecl.Type.Params, pkg.name, imports), qualifiedFieldTypes(funcDecl.Type.Results, pkg.name, imports)),
+				position:  Position{Path: goFile.srcPath, Line: goFile.fset.Position(funcDecl.Pos()).Line},
+			}
+		}
+	}
+	return methods
+}
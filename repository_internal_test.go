@@ -0,0 +1,115 @@
+package astkratos
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/rese"
+)
+
+// TestAnalyzeRepoImplementations_Problems tests missing and mismatched methods, wire.Bind and interfaces without implementation
+//
+// TestAnalyzeRepoImplementations_Problems 测试缺失和签名不一致的方法、wire.Bind 以及没有实现的接口
+func TestAnalyzeRepoImplementations_Problems(t *testing.T) {
+	root := t.TempDir()
+	bizRoot := filepath.Join(root, "internal", "biz")
+	must.Done(os.MkdirAll(bizRoot, 0755))
+	must.Done(os.WriteFile(filepath.Join(bizRoot, "biz.go"), []byte(`package biz
+
+import "context"
+
+type User struct{}
+
+type Reader interface {
+	Get(ctx context.Context, id int64) (*User, error)
+}
+
+type UserRepo interface {
+	Reader
+	Save(context.Context, *User) error
+	Delete(context.Context, int64) error
+}
+
+type OrderRepo interface {
+	Count(context.Context) (int, error)
+}
+
+type AuditRepo interface {
+	Log(string)
+}
+
+type Clock interface {
+	Now() int64
+}
+`), 0644))
+	dataRoot := filepath.Join(root, "internal", "data")
+	must.Done(os.MkdirAll(dataRoot, 0755))
+	must.Done(os.WriteFile(filepath.Join(dataRoot, "data.go"), []byte(`package data
+
+import (
+	"context"
+
+	"demo/internal/biz"
+
+	"github.com/google/wire"
+)
+
+var ProviderSet = wire.NewSet(NewUserRepo, NewOrderRepo, wire.Bind(new(biz.OrderRepo), new(*orderRepo)))
+
+type userRepo struct{}
+
+func NewUserRepo() biz.UserRepo {
+	return &userRepo{}
+}
+
+func (r *userRepo) Get(ctx context.Context, id int64) (*biz.User, error) {
+	return nil, nil
+}
+
+func (r *userRepo) Save(ctx context.Context, user biz.User) error {
+	return nil
+}
+
+type orderRepo struct{}
+
+func NewOrderRepo() *orderRepo {
+	return &orderRepo{}
+}
+
+func (r orderRepo) Count(context.Context) (int, error) {
+	return 0, nil
+}
+`), 0644))
+
+	repositories := rese.V1(analyzeRepoImplementations(root, "demo"))
+	require.Len(t, repositories, 3)
+
+	userRepo := repositories[0]
+	require.Equal(t, "UserRepo", userRepo.Interface)
+	require.Equal(t, []string{"Get", "Save", "Delete"}, userRepo.Methods)
+	require.Equal(t, "userRepo", userRepo.StructName)
+	require.Equal(t, "NewUserRepo", userRepo.Constructor)
+	require.Equal(t, []string{"Delete"}, userRepo.MissingMethods)
+	require.Len(t, userRepo.MismatchedMethods, 1)
+	require.Equal(t, "Save", userRepo.MismatchedMethods[0].Method)
+	require.Equal(t, "func(context.Context, *biz.User) error", userRepo.MismatchedMethods[0].Expected)
+	require.Equal(t, "func(context.Context, biz.User) error", userRepo.MismatchedMethods[0].Actual)
+	require.Equal(t, 23, userRepo.MismatchedMethods[0].Position.Line)
+	require.False(t, userRepo.IsImplemented())
+
+	orderRepo := repositories[1]
+	require.Equal(t, "OrderRepo", orderRepo.Interface)
+	require.Equal(t, "orderRepo", orderRepo.StructName)
+	require.Empty(t, orderRepo.Constructor)
+	require.Equal(t, 27, orderRepo.StructPosition.Line)
+	require.True(t, orderRepo.IsImplemented())
+
+	auditRepo := repositories[2]
+	require.Equal(t, "AuditRepo", auditRepo.Interface)
+	require.Empty(t, auditRepo.StructName)
+	require.Equal(t, []string{"Log"}, auditRepo.Methods)
+	require.False(t, auditRepo.IsImplemented())
+}
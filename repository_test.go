@@ -0,0 +1,32 @@
+package astkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/astkratos"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/neatjson/neatjsons"
+	"github.com/yyle88/runpath"
+)
+
+// TestListRepoImplementations tests that GreeterRepo of the demo links to greeterRepo in data
+//
+// TestListRepoImplementations 测试示例中的 GreeterRepo 关联到 data 中的 greeterRepo
+func TestListRepoImplementations(t *testing.T) {
+	repositories := astkratos.ListRepoImplementations(runpath.PARENT.Join("testdata", "demokratos"))
+	t.Log(neatjsons.S(repositories))
+
+	require.Len(t, repositories, 1)
+	repository := repositories[0]
+	require.Equal(t, "GreeterRepo", repository.Interface)
+	require.Equal(t, "biz", repository.Package)
+	require.Equal(t, 23, repository.Position.Line)
+	require.Equal(t, []string{"Save", "Update", "FindByID", "ListByHello", "ListAll"}, repository.Methods)
+	require.Equal(t, "greeterRepo", repository.StructName)
+	require.Equal(t, "data", repository.StructPackage)
+	require.Equal(t, runpath.PARENT.Join("testdata", "demokratos", "internal", "data", "greeter.go"), repository.StructPosition.Path)
+	require.Equal(t, 11, repository.StructPosition.Line)
+	require.Equal(t, "NewGreeterRepo", repository.Constructor)
+	require.Equal(t, 17, repository.ConstructorPosition.Line)
+	require.True(t, repository.IsImplemented())
+}
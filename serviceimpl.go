@@ -0,0 +1,212 @@
+// Package astkratos service implementation: Mapping of gRPC services to the structs in internal/service
+// Finds the structs that embed pb.UnimplementedXxxServer, or the stub of the same package, and links them to the generated service
+// Locates the NewXxxService constructor and the source file of each implementation
+// Resolves import names so that aliases such as v1 and pb point at the right api package
+//
+// astkratos 服务实现：将 gRPC 服务映射到 internal/service 中的结构体
+// 查找嵌入 pb.UnimplementedXxxServer 或同包存根的结构体并将其关联到生成的服务
+// 定位每个实现的 NewXxxService 构造函数和源文件
+// 解析导入名称，使 v1 和 pb 等别名指向正确的 api 包
+package astkratos
+
+import (
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"strings"
+
+	"github.com/yyle88/erero"
+)
+
+// ServiceImplementation links a gRPC service to its implementation struct and constructor
+//
+// ServiceImplementation 将 gRPC 服务关联到其实现结构体和构造函数
+type ServiceImplementation struct {
+	Service             string   // gRPC service name, such as Greeter // gRPC 服务名称，例如 Greeter
+	ServiceSrcPath      string   // Generated _grpc.pb.go file of the service // 服务所在的生成 _grpc.pb.go 文件
+	StructName          string   // Implementation struct name, such as GreeterService // 实现结构体名称，例如 GreeterService
+	Package             string   // Package name of the struct // 结构体所在的包名
+	SrcPath             string   // Source file of the struct // 结构体所在的源文件
+	Position            Position // Position of the struct // 结构体的位置
+	EmbeddedStub        string   // Embedded stub as written, such as v1.UnimplementedGreeterServer // 源码中嵌入的存根，例如 v1.UnimplementedGreeterServer
+	PointerEmbedded     bool     // Stub embedded through a pointer // 通过指针嵌入存根
+	Constructor         string   // Constructor name, such as NewGreeterService // 构造函数名称，例如 NewGreeterService
+	ConstructorPosition Position // Position of the constructor // 构造函数的位置
+	Methods             []string // Exported methods declared on the struct // 结构体上声明的导出方法
+}
+
+// analyzeServiceImplementations maps the services to the structs under the service root
+//
+// analyzeServiceImplementations 将服务映射到服务根目录下的结构体
+func analyzeServiceImplementations(projectRoot string, serviceRoot string, services []*GrpcTypeDefinition) ([]*ServiceImplementation, error) {
+	projectRoot, err := filepath.Abs(projectRoot)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	packages, err := loadGoPackages(serviceRoot)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+
+	stubs := map[string][]*GrpcTypeDefinition{}
+	for _, service := range services {
+		stub := "Unimplemented" + service.Name + "Server"
+		stubs[stub] = append(stubs[stub], service)
+	}
+
+	implementations := make([]*ServiceImplementation, 0)
+	for _, pkg := range packages {
+		var pkgImplementations []*ServiceImplementation
+		for _, goFile := range pkg.files {
+			imports := importPaths(goFile.astFile)
+			for _, decl := range goFile.astFile.Decls {
+				genDecl, ok := decl.(*ast.GenDecl)
+				if !ok || genDecl.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range genDecl.Specs {
+					typeSpec := spec.(*ast.TypeSpec)
+					structType, ok := typeSpec.Type.(*ast.StructType)
+					if !ok {
+						continue
+					}
+					for _, field := range structType.Fields.List {
+						if len(field.Names) > 0 {
+							continue
+						}
+						fieldType, pointer := field.Type, false
+						if starExpr, ok := fieldType.(*ast.StarExpr); ok {
+							fieldType, pointer = starExpr.X, true
+						}
+						var service *GrpcTypeDefinition
+						var embeddedStub string
+						switch x := fieldType.(type) {
+						case *ast.SelectorExpr:
+							importName, ok := x.X.(*ast.Ident)
+							if !ok {
+								continue
+							}
+							service = matchServicePackage(projectRoot, stubs[x.Sel.Name], imports[importName.Name])
+							embeddedStub = importName.Name + "." + x.Sel.Name
+						case *ast.Ident:
+							// The generated code lives in the package of the struct itself
+							// 生成代码就位于结构体自身所在的包中
+							service = matchServiceDir(stubs[x.Name], pkg.dir)
+							embeddedStub = x.Name
+						}
+						if service == nil {
+							continue
+						}
+						pkgImplementations = append(pkgImplementations, &ServiceImplementation{
+							Service:         service.Name,
+							ServiceSrcPath:  service.SrcPath,
+							StructName:      typeSpec.Name.Name,
+							Package:         pkg.name,
+							SrcPath:         goFile.srcPath,
+							Position:        Position{Path: goFile.srcPath, Line: goFile.fset.Position(typeSpec.Pos()).Line},
+							EmbeddedStub:    embeddedStub,
+							PointerEmbedded: pointer,
+						})
+					}
+				}
+			}
+		}
+		for _, implementation := range pkgImplementations {
+			implementation.Constructor, implementation.ConstructorPosition = findConstructor(pkg, implementation.StructName)
+			implementation.Methods = listExportedMethods(pkg, implementation.StructName)
+		}
+		implementations = append(implementations, pkgImplementations...)
+	}
+	return implementations, nil
+}
+
+// matchServiceDir picks the service whose generated file is in the directory, nil when none is
+//
+// matchServiceDir 选择生成文件位于该目录中的服务，没有时返回 nil
+func matchServiceDir(candidates []*GrpcTypeDefinition, dir string) *GrpcTypeDefinition {
+	for _, service := range candidates {
+		if filepath.Dir(service.SrcPath) == dir {
+			return service
+		}
+	}
+	return nil
+}
+
+// matchServicePackage picks the service whose generated package is the import path
+// Falls back to the first candidate when the generated file is outside the project root
+//
+// matchServicePackage 选择生成包与导入路径一致的服务
+// 生成文件不在项目根目录下时回退为第一个候选
+func matchServicePackage(projectRoot string, candidates []*GrpcTypeDefinition, importPath string) *GrpcTypeDefinition {
+	if importPath == "" || len(candidates) == 0 {
+		return nil
+	}
+	for _, service := range candidates {
+		relDir, err := filepath.Rel(projectRoot, filepath.Dir(service.SrcPath))
+		if err != nil || strings.HasPrefix(relDir, "..") {
+			continue
+		}
+		relDir = filepath.ToSlash(relDir)
+		if importPath == relDir || strings.HasSuffix(importPath, "/"+relDir) {
+			return service
+		}
+	}
+	for _, service := range candidates {
+		relDir, err := filepath.Rel(projectRoot, filepath.Dir(service.SrcPath))
+		if err != nil || strings.HasPrefix(relDir, "..") {
+			return service
+		}
+	}
+	return nil
+}
+
+// findConstructor finds the function returning the struct, preferring the NewXxx naming
+//
+// findConstructor 查找返回该结构体的函数，优先选择 NewXxx 命名
+func findConstructor(pkg *goPackage, structName string) (string, Position) {
+	var name string
+	var position Position
+	for _, goFile := range pkg.files {
+		for _, decl := range goFile.astFile.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Recv != nil || funcDecl.Type.Results == nil || len(funcDecl.Type.Results.List) == 0 {
+				continue
+			}
+			resultType := funcDecl.Type.Results.List[0].Type
+			if starExpr, ok := resultType.(*ast.StarExpr); ok {
+				resultType = starExpr.X
+			}
+			if ident, ok := resultType.(*ast.Ident); !ok || ident.Name != structName {
+				continue
+			}
+			if name == "" || funcDecl.Name.Name == "New"+structName {
+				name = funcDecl.Name.Name
+				position = Position{Path: goFile.srcPath, Line: goFile.fset.Position(funcDecl.Pos()).Line}
+			}
+		}
+	}
+	return name, position
+}
+
+// listExportedMethods lists the exported methods declared on the struct or its pointer in the package
+//
+// listExportedMethods 列出包中在结构体或其指针上声明的导出方法
+func listExportedMethods(pkg *goPackage, structName string) []string {
+	methods := make([]string, 0)
+	for _, goFile := range pkg.files {
+		for _, decl := range goFile.astFile.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 || !funcDecl.Name.IsExported() {
+				continue
+			}
+			recvType := funcDecl.Recv.List[0].Type
+			if starExpr, ok := recvType.(*ast.StarExpr); ok {
+				recvType = starExpr.X
+			}
+			if ident, ok := recvType.(*ast.Ident); ok && ident.Name == structName {
+				methods = append(methods, funcDecl.Name.Name)
+			}
+		}
+	}
+	return methods
+}
@@ -0,0 +1,54 @@
+package astkratos
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/rese"
+)
+
+// TestAnalyzeServiceImplementations_ImportPath tests that services with the same name are told apart by import path
+//
+// TestAnalyzeServiceImplementations_ImportPath 测试同名服务通过导入路径区分
+func TestAnalyzeServiceImplementations_ImportPath(t *testing.T) {
+	root := t.TempDir()
+	serviceRoot := filepath.Join(root, "internal", "service")
+	must.Done(os.MkdirAll(serviceRoot, 0755))
+	must.Done(os.WriteFile(filepath.Join(serviceRoot, "greeter.go"), []byte(`package service
+
+import greeterv2 "demo/api/helloworld/v2"
+
+type GreeterV2Service struct {
+	*greeterv2.UnimplementedGreeterServer
+}
+
+func newGreeter() *GreeterV2Service {
+	return &GreeterV2Service{}
+}
+`), 0644))
+	must.Done(os.WriteFile(filepath.Join(serviceRoot, "greeter_test.go"), []byte("package service\n\ntype Ignored struct{}\n"), 0644))
+
+	services := []*GrpcTypeDefinition{
+		{Name: "Greeter", Package: "v1", SrcPath: filepath.Join(root, "api", "helloworld", "v1", "greeter_grpc.pb.go")},
+		{Name: "Greeter", Package: "v2", SrcPath: filepath.Join(root, "api", "helloworld", "v2", "greeter_grpc.pb.go")},
+	}
+	implementations := rese.V1(analyzeServiceImplementations(root, serviceRoot, services))
+	require.Len(t, implementations, 1)
+	require.Equal(t, services[1].SrcPath, implementations[0].ServiceSrcPath)
+	require.Equal(t, "greeterv2.UnimplementedGreeterServer", implementations[0].EmbeddedStub)
+	require.True(t, implementations[0].PointerEmbedded)
+	require.Equal(t, "newGreeter", implementations[0].Constructor)
+	require.Equal(t, 9, implementations[0].ConstructorPosition.Line)
+}
+
+// TestAnalyzeServiceImplementations_MissingRoot tests that a project without internal/service yields no implementations
+//
+// TestAnalyzeServiceImplementations_MissingRoot 测试没有 internal/service 的项目不返回任何实现
+func TestAnalyzeServiceImplementations_MissingRoot(t *testing.T) {
+	root := t.TempDir()
+	implementations := rese.V1(analyzeServiceImplementations(root, filepath.Join(root, "internal", "service"), nil))
+	require.Empty(t, implementations)
+}
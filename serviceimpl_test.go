@@ -0,0 +1,98 @@
+package astkratos_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/orzkratos/astkratos"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/neatjson/neatjsons"
+	"github.com/yyle88/rese"
+	"github.com/yyle88/runpath"
+)
+
+// TestListServiceImplementations tests mapping of services to the structs embedding the Unimplemented stubs
+//
+// TestListServiceImplementations 测试将服务映射到嵌入 Unimplemented 存根的结构体
+func TestListServiceImplementations(t *testing.T) {
+	implementations := astkratos.ListServiceImplementations(runpath.PARENT.Join("testdata", "demokratos"))
+	t.Log(neatjsons.S(implementations))
+
+	apiRoot := runpath.PARENT.Join("testdata", "demokratos", "api")
+	echoPath := runpath.PARENT.Join("testdata", "demokratos", "internal", "service", "echo.go")
+	greeterPath := runpath.PARENT.Join("testdata", "demokratos", "internal", "service", "greeter.go")
+	require.Equal(t, []*astkratos.ServiceImplementation{
+		{
+			Service:             "Echo",
+			ServiceSrcPath:      apiRoot + "/echo/v1/echo_grpc.pb.go",
+			StructName:          "EchoService",
+			Package:             "service",
+			SrcPath:             echoPath,
+			Position:            astkratos.Position{Path: echoPath, Line: 13},
+			EmbeddedStub:        "pb.UnimplementedEchoServer",
+			Constructor:         "NewEchoService",
+			ConstructorPosition: astkratos.Position{Path: echoPath, Line: 20},
+			Methods:             []string{"Ping", "Watch"},
+		},
+		{
+			Service:             "Greeter",
+			ServiceSrcPath:      apiRoot + "/helloworld/v1/greeter_grpc.pb.go",
+			StructName:          "GreeterService",
+			Package:             "service",
+			SrcPath:             greeterPath,
+			Position:            astkratos.Position{Path: greeterPath, Line: 11},
+			EmbeddedStub:        "v1.UnimplementedGreeterServer",
+			Constructor:         "NewGreeterService",
+			ConstructorPosition: astkratos.Position{Path: greeterPath, Line: 18},
+			Methods:             []string{"SayHello"},
+		},
+	}, implementations)
+}
+
+// TestListServiceImplementations_SamePackage tests a stub embedded without a package name, next to its generated code
+//
+// TestListServiceImplementations_SamePackage 测试在生成代码旁边不带包名嵌入的存根
+func TestListServiceImplementations_SamePackage(t *testing.T) {
+	root := t.TempDir()
+	serviceRoot := filepath.Join(root, "internal", "service")
+	must.Done(os.MkdirAll(serviceRoot, 0755))
+	must.Done(os.WriteFile(filepath.Join(root, "go.mod"), []byte("module shop\n\ngo 1.22\n"), 0644))
+	echoSource := string(rese.V1(os.ReadFile(filepath.Join(demoApiRoot, "echo", "v1", "echo_grpc.pb.go"))))
+	must.Done(os.WriteFile(filepath.Join(serviceRoot, "echo_grpc.pb.go"), []byte(strings.Replace(echoSource, "package v1", "package service", 1)), 0644))
+	srcPath := filepath.Join(serviceRoot, "echo.go")
+	must.Done(os.WriteFile(srcPath, []byte(`package service
+
+import "context"
+
+type EchoService struct {
+	*UnimplementedEchoServer
+}
+
+func NewEchoService() *EchoService {
+	return &EchoService{}
+}
+
+func (s *EchoService) Ping(ctx context.Context, req *PingRequest) (*PingReply, error) {
+	return &PingReply{}, nil
+}
+`), 0644))
+
+	report, err := astkratos.NewAnalyzer(astkratos.WithApiRoots("internal/service")).Analyze(context.Background(), root)
+	require.NoError(t, err)
+	t.Log(neatjsons.S(report.Implementations))
+	require.Len(t, report.Implementations, 1)
+	require.Equal(t, "EchoService", report.Implementations[0].StructName)
+	require.Equal(t, "UnimplementedEchoServer", report.Implementations[0].EmbeddedStub)
+	require.True(t, report.Implementations[0].PointerEmbedded)
+	require.Equal(t, "NewEchoService", report.Implementations[0].Constructor)
+
+	require.Len(t, report.Coverage, 1)
+	require.Equal(t, []string{"Ping"}, report.Coverage[0].ImplementedRpcs)
+	require.Equal(t, 4, report.Coverage[0].TotalRpcs)
+	require.Len(t, report.Coverage[0].UnimplementedRpcs, 3)
+	require.Equal(t, "Watch", report.Coverage[0].UnimplementedRpcs[0].Name)
+}
@@ -0,0 +1,11 @@
+module demokratos
+
+go 1.22
+
+require (
+	github.com/go-kratos/kratos/v2 v2.7.2
+	github.com/google/wire v0.6.0
+	google.golang.org/genproto/googleapis/api v0.0.0-20240102182953-50ed04b92917
+	google.golang.org/grpc v1.61.1
+	google.golang.org/protobuf v1.33.0
+)
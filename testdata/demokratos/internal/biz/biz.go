@@ -0,0 +1,6 @@
+package biz
+
+import "github.com/google/wire"
+
+// ProviderSet is biz providers.
+var ProviderSet = wire.NewSet(NewGreeterUsecase)
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+
+	pb "demokratos/api/echo/v1"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"google.golang.org/grpc"
+)
+
+// EchoService answers pings and streams watch events.
+type EchoService struct {
+	pb.UnimplementedEchoServer
+
+	log *log.Helper
+}
+
+// NewEchoService new an echo service.
+func NewEchoService(logger log.Logger) *EchoService {
+	return &EchoService{log: log.NewHelper(logger)}
+}
+
+// Ping replies with the payload of the request.
+func (s *EchoService) Ping(ctx context.Context, req *pb.PingRequest) (*pb.PingReply, error) {
+	return &pb.PingReply{Payload: req.Payload}, nil
+}
+
+// Watch sends one event of the requested topic.
+func (s *EchoService) Watch(req *pb.WatchRequest, stream grpc.ServerStreamingServer[pb.WatchEvent]) error {
+	return stream.Send(&pb.WatchEvent{Topic: req.Topic})
+}
@@ -0,0 +1,283 @@
+// Package astkratos upgrade advisor: Kratos API usages to revisit before bumping the framework
+// Reads the github.com/go-kratos/kratos/v2 version from the require list of go.mod
+// Finds transport options, middleware options and log helpers deprecated or changed in newer releases
+// Finds old Unimplemented stubs and pointer embedded stubs that newer protoc-gen-go-grpc rejects
+//
+// astkratos 升级顾问：升级框架前需要重新检查的 Kratos API 用法
+// 从 go.mod 的 require 列表中读取 github.com/go-kratos/kratos/v2 的版本
+// 查找在新版本中已弃用或已变更的传输选项、中间件选项和日志辅助函数
+// 查找新版 protoc-gen-go-grpc 不再接受的旧 Unimplemented 存根和指针嵌入的存根
+package astkratos
+
+import (
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/yyle88/erero"
+)
+
+// KratosModulePath is the module path of the Kratos framework
+//
+// KratosModulePath 是 Kratos 框架的模块路径
+const KratosModulePath = "github.com/go-kratos/kratos/v2"
+
+// UpgradeRule represents a kind of usage to revisit when upgrading Kratos
+//
+// UpgradeRule 表示升级 Kratos 时需要重新检查的用法类型
+type UpgradeRule string
+
+const (
+	UpgradeGrpcLoggerOption    UpgradeRule = "grpc_logger_option"    // grpc.Logger server option // grpc.Logger 服务器选项
+	UpgradeHttpLoggerOption    UpgradeRule = "http_logger_option"    // http.Logger server option // http.Logger 服务器选项
+	UpgradeRecoveryWithLogger  UpgradeRule = "recovery_with_logger"  // recovery.WithLogger middleware option // recovery.WithLogger 中间件选项
+	UpgradeLogHelperName       UpgradeRule = "log_helper_name"       // log.NewHelper called with a module name first // 以模块名作为第一个参数调用 log.NewHelper
+	UpgradeValidateMiddleware  UpgradeRule = "validate_middleware"   // validate.Validator middleware // validate.Validator 中间件
+	UpgradeStubNotByValue      UpgradeRule = "stub_not_by_value"     // Unimplemented stub without testEmbeddedByValue // 没有 testEmbeddedByValue 的 Unimplemented 存根
+	UpgradePointerEmbeddedStub UpgradeRule = "pointer_embedded_stub" // Unimplemented stub embedded through a pointer // 通过指针嵌入的 Unimplemented 存根
+)
+
+// upgradeCallRule matches calls of a function in a Kratos package
+//
+// upgradeCallRule 匹配 Kratos 包中某个函数的调用
+type upgradeCallRule struct {
+	rule       UpgradeRule              // Rule reported // 报告的规则
+	importPath string                   // Package of the function // 函数所在的包
+	funcName   string                   // Function name // 函数名称
+	match      func(*ast.CallExpr) bool // Extra check of the call, nil to match every call // 对调用的额外检查，为 nil 时匹配每个调用
+	advice     string                   // What to change // 需要做的修改
+}
+
+// upgradeCallRules lists the calls known to be deprecated or changed in newer Kratos releases
+//
+// upgradeCallRules 列出已知在新版 Kratos 中已弃用或已变更的调用
+var upgradeCallRules = []*upgradeCallRule{
+	{
+		rule:       UpgradeGrpcLoggerOption,
+		importPath: KratosModulePath + "/transport/grpc",
+		funcName:   "Logger",
+		advice:     "grpc.Logger is deprecated and ignored, the server logs through the global logger set by log.SetLogger",
+	},
+	{
+		rule:       UpgradeHttpLoggerOption,
+		importPath: KratosModulePath + "/transport/http",
+		funcName:   "Logger",
+		advice:     "http.Logger is deprecated and ignored, the server logs through the global logger set by log.SetLogger",
+	},
+	{
+		rule:       UpgradeRecoveryWithLogger,
+		importPath: KratosModulePath + "/middleware/recovery",
+		funcName:   "WithLogger",
+		advice:     "recovery.WithLogger is gone, recovery logs through the global logger and recovery.WithHandler customizes the reply",
+	},
+	{
+		rule:       UpgradeLogHelperName,
+		importPath: KratosModulePath + "/log",
+		funcName:   "NewHelper",
+		match: func(call *ast.CallExpr) bool {
+			if len(call.Args) == 0 {
+				return false
+			}
+			basicLit, ok := call.Args[0].(*ast.BasicLit)
+			return ok && basicLit.Kind == token.STRING
+		},
+		advice: "log.NewHelper takes the logger first, attach the module name with log.NewHelper(log.With(logger, \"module\", name))",
+	},
+	{
+		rule:       UpgradeValidateMiddleware,
+		importPath: KratosModulePath + "/middleware/validate",
+		funcName:   "Validator",
+		advice:     "middleware/validate is deprecated in favor of the protovalidate middleware of kratos contrib",
+	},
+}
+
+// UpgradeFinding represents one usage to revisit
+//
+// UpgradeFinding 表示一处需要重新检查的用法
+type UpgradeFinding struct {
+	Rule     UpgradeRule // Rule matched // 匹配的规则
+	Usage    string      // Usage as written, such as grpc.Logger or *v1.UnimplementedGreeterServer // 源码中的用法，例如 grpc.Logger 或 *v1.UnimplementedGreeterServer
+	Advice   string      // What to change // 需要做的修改
+	Position Position    // Position of the usage // 用法的位置
+}
+
+// UpgradeFile groups the findings of one source file
+//
+// UpgradeFile 汇总单个源文件中的发现
+type UpgradeFile struct {
+	Path     string            // Absolute source file path // 源文件绝对路径
+	Findings []*UpgradeFinding // Findings in line order // 按行号排列的发现
+}
+
+// UpgradeChecklist represents the per-file upgrade checklist of a project
+//
+// UpgradeChecklist 表示项目按文件划分的升级清单
+type UpgradeChecklist struct {
+	KratosVersion string         // Required Kratos version, blank when not required // 依赖的 Kratos 版本，未依赖时为空
+	Files         []*UpgradeFile // Files with findings in path order // 按路径排列的有发现的文件
+	UnreadFiles   []*UnreadFile  // Go files that could not be parsed, so they were not checked // 无法解析因而未检查的 Go 文件
+}
+
+// checkKratosUpgrade scans the Go files of the project and the api roots for usages to revisit
+// An api root is scanned on its own only when it lies outside the project root
+// A file failing to parse is listed as unread instead of failing the whole checklist
+//
+// checkKratosUpgrade 扫描项目和 api 根目录中的 Go 文件，查找需要重新检查的用法
+// 仅当 api 根目录位于项目根目录之外时才单独扫描该目录
+// 解析失败的文件列为未读取，而不是使整个清单失败
+func checkKratosUpgrade(projectRoot string, apiRoots []string, moduleInfo *ModuleInfo, implementations []*ServiceImplementation) (*UpgradeChecklist, error) {
+	projectRoot, err := filepath.Abs(projectRoot)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	roots := []string{projectRoot}
+	for _, apiRoot := range apiRoots {
+		if apiRoot, err = filepath.Abs(apiRoot); err != nil {
+			return nil, erero.Wro(err)
+		}
+		if relDir, err := filepath.Rel(projectRoot, apiRoot); err != nil || strings.HasPrefix(relDir, "..") {
+			roots = append(roots, apiRoot)
+		}
+	}
+
+	var findings []*UpgradeFinding
+	var failures []*scanFailure
+	for _, root := range roots {
+		packages, rootFailures, err := walkGoPackages(root)
+		if err != nil {
+			return nil, erero.Wro(err)
+		}
+		failures = append(failures, rootFailures...)
+		for _, pkg := range packages {
+			for _, goFile := range pkg.files {
+				findings = append(findings, findUpgradeCalls(goFile)...)
+				findings = append(findings, findUpgradeStubs(goFile)...)
+			}
+		}
+	}
+	for _, implementation := range implementations {
+		if !implementation.PointerEmbedded {
+			continue
+		}
+		findings = append(findings, &UpgradeFinding{
+			Rule:     UpgradePointerEmbeddedStub,
+			Usage:    "*" + implementation.EmbeddedStub,
+			Advice:   "embed " + implementation.EmbeddedStub + " by value, stubs from protoc-gen-go-grpc v1.5 and newer panic at registration when embedded through a nil pointer",
+			Position: implementation.Position,
+		})
+	}
+
+	checklist := &UpgradeChecklist{Files: make([]*UpgradeFile, 0), UnreadFiles: newUnreadFiles(failures)}
+	if moduleInfo != nil {
+		checklist.KratosVersion = moduleInfo.GetKratosVersion()
+	}
+	fileMap := map[string]*UpgradeFile{}
+	for _, finding := range findings {
+		upgradeFile, ok := fileMap[finding.Position.Path]
+		if !ok {
+			upgradeFile = &UpgradeFile{Path: finding.Position.Path}
+			fileMap[finding.Position.Path] = upgradeFile
+			checklist.Files = append(checklist.Files, upgradeFile)
+		}
+		upgradeFile.Findings = append(upgradeFile.Findings, finding)
+	}
+	slices.SortFunc(checklist.Files, func(a, b *UpgradeFile) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	for _, upgradeFile := range checklist.Files {
+		slices.SortStableFunc(upgradeFile.Findings, func(a, b *UpgradeFinding) int {
+			return a.Position.Line - b.Position.Line
+		})
+	}
+	return checklist, nil
+}
+
+// findUpgradeCalls finds the calls in the file matching the upgrade call rules
+//
+// findUpgradeCalls 查找文件中与升级调用规则匹配的调用
+func findUpgradeCalls(goFile *parsedGoFile) []*UpgradeFinding {
+	imports := importPaths(goFile.astFile)
+	var findings []*UpgradeFinding
+	ast.Inspect(goFile.astFile, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		selectorExpr, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		importName, ok := selectorExpr.X.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		for _, rule := range upgradeCallRules {
+			if imports[importName.Name] != rule.importPath || selectorExpr.Sel.Name != rule.funcName {
+				continue
+			}
+			if rule.match != nil && !rule.match(call) {
+				continue
+			}
+			findings = append(findings, &UpgradeFinding{
+				Rule:     rule.rule,
+				Usage:    importName.Name + "." + selectorExpr.Sel.Name,
+				Advice:   rule.advice,
+				Position: Position{Path: goFile.srcPath, Line: goFile.fset.Position(call.Pos()).Line},
+			})
+		}
+		return true
+	})
+	return findings
+}
+
+// findUpgradeStubs finds the Unimplemented stubs with mustEmbed methods but without testEmbeddedByValue
+// Such stubs come from protoc-gen-go-grpc before v1.5, which never checked how the stub is embedded
+//
+// findUpgradeStubs 查找带有 mustEmbed 方法但没有 testEmbeddedByValue 的 Unimplemented 存根
+// 这类存根来自 v1.5 之前的 protoc-gen-go-grpc，它从不检查存根的嵌入方式
+func findUpgradeStubs(goFile *parsedGoFile) []*UpgradeFinding {
+	stubMethods := map[string]map[string]bool{}
+	for _, decl := range goFile.astFile.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Recv == nil || len(funcDecl.Recv.List) != 1 {
+			continue
+		}
+		recvType := funcDecl.Recv.List[0].Type
+		if starExpr, ok := recvType.(*ast.StarExpr); ok {
+			recvType = starExpr.X
+		}
+		if ident, ok := recvType.(*ast.Ident); ok {
+			if stubMethods[ident.Name] == nil {
+				stubMethods[ident.Name] = map[string]bool{}
+			}
+			stubMethods[ident.Name][funcDecl.Name.Name] = true
+		}
+	}
+
+	var findings []*UpgradeFinding
+	for _, decl := range goFile.astFile.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			name := typeSpec.Name.Name
+			if _, ok := typeSpec.Type.(*ast.StructType); !ok || !strings.HasPrefix(name, "Unimplemented") || !strings.HasSuffix(name, "Server") {
+				continue
+			}
+			if !stubMethods[name]["mustEmbed"+name] || stubMethods[name]["testEmbeddedByValue"] {
+				continue
+			}
+			findings = append(findings, &UpgradeFinding{
+				Rule:     UpgradeStubNotByValue,
+				Usage:    name,
+				Advice:   name + " lacks testEmbeddedByValue, regenerate it with protoc-gen-go-grpc v1.5 or newer and embed it by value",
+				Position: Position{Path: goFile.srcPath, Line: goFile.fset.Position(typeSpec.Pos()).Line},
+			})
+		}
+	}
+	return findings
+}
@@ -0,0 +1,143 @@
+package astkratos_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/orzkratos/astkratos"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/neatjson/neatjsons"
+	"github.com/yyle88/rese"
+	"github.com/yyle88/runpath"
+)
+
+// TestCheckKratosUpgrade tests that the legacy stub of the demo project is listed
+//
+// TestCheckKratosUpgrade 测试演示项目的 legacy 存根会被列出
+func TestCheckKratosUpgrade(t *testing.T) {
+	checklist := astkratos.CheckKratosUpgrade(runpath.PARENT.Join("testdata", "demokratos"))
+	t.Log(neatjsons.S(checklist))
+	require.Equal(t, "v2.7.2", checklist.KratosVersion)
+	require.Len(t, checklist.Files, 1)
+	require.Equal(t, filepath.Join(demoApiRoot, "legacy", "v1", "legacy_grpc.pb.go"), checklist.Files[0].Path)
+	require.Len(t, checklist.Files[0].Findings, 1)
+	require.Equal(t, astkratos.UpgradeStubNotByValue, checklist.Files[0].Findings[0].Rule)
+	require.Equal(t, "UnimplementedLegacyServer", checklist.Files[0].Findings[0].Usage)
+	require.Equal(t, 157, checklist.Files[0].Findings[0].Position.Line)
+}
+
+// TestCheckKratosUpgrade_Usages tests deprecated options, old log helpers and pointer embedded stubs
+//
+// TestCheckKratosUpgrade_Usages 测试已弃用的选项、旧的日志辅助函数和指针嵌入的存根
+func TestCheckKratosUpgrade_Usages(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"api/legacy/v1", "internal/server", "internal/service"} {
+		must.Done(os.MkdirAll(filepath.Join(root, dir), 0755))
+	}
+	must.Done(os.WriteFile(filepath.Join(root, "go.mod"), []byte(`module shop
+
+go 1.22
+
+require github.com/go-kratos/kratos/v2 v2.7.2
+
+replace github.com/go-kratos/kratos/v2 => github.com/go-kratos/kratos/v2 v2.8.4
+`), 0644))
+	must.Done(os.WriteFile(filepath.Join(root, "api", "legacy", "v1", "legacy_grpc.pb.go"), rese.V1(os.ReadFile(filepath.Join(demoApiRoot, "legacy", "v1", "legacy_grpc.pb.go"))), 0644))
+	serverPath := filepath.Join(root, "internal", "server", "grpc.go")
+	must.Done(os.WriteFile(serverPath, []byte(`package server
+
+import (
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/middleware/recovery"
+	"github.com/go-kratos/kratos/v2/middleware/validate"
+	"github.com/go-kratos/kratos/v2/transport/grpc"
+)
+
+func NewGRPCServer(logger log.Logger) *grpc.Server {
+	helper := log.NewHelper("server", logger)
+	helper.Info("starting")
+	return grpc.NewServer(
+		grpc.Logger(logger),
+		grpc.Middleware(
+			recovery.Recovery(recovery.WithLogger(logger)),
+			validate.Validator(),
+		),
+	)
+}
+`), 0644))
+	servicePath := filepath.Join(root, "internal", "service", "legacy.go")
+	must.Done(os.WriteFile(servicePath, []byte(`package service
+
+import (
+	"github.com/go-kratos/kratos/v2/log"
+
+	pb "shop/api/legacy/v1"
+)
+
+type LegacyService struct {
+	*pb.UnimplementedLegacyServer
+
+	log *log.Helper
+}
+
+func NewLegacyService(logger log.Logger) *LegacyService {
+	return &LegacyService{log: log.NewHelper(logger)}
+}
+`), 0644))
+
+	checklist := astkratos.CheckKratosUpgrade(root)
+	t.Log(neatjsons.S(checklist))
+	require.Equal(t, "v2.8.4", checklist.KratosVersion)
+	require.Len(t, checklist.Files, 3)
+
+	serverFile := checklist.Files[1]
+	require.Equal(t, serverPath, serverFile.Path)
+	rules := make([]astkratos.UpgradeRule, 0, len(serverFile.Findings))
+	for _, finding := range serverFile.Findings {
+		rules = append(rules, finding.Rule)
+	}
+	require.Equal(t, []astkratos.UpgradeRule{
+		astkratos.UpgradeLogHelperName,
+		astkratos.UpgradeGrpcLoggerOption,
+		astkratos.UpgradeRecoveryWithLogger,
+		astkratos.UpgradeValidateMiddleware,
+	}, rules)
+	require.Equal(t, 11, serverFile.Findings[0].Position.Line)
+
+	serviceFile := checklist.Files[2]
+	require.Equal(t, servicePath, serviceFile.Path)
+	require.Len(t, serviceFile.Findings, 1)
+	require.Equal(t, astkratos.UpgradePointerEmbeddedStub, serviceFile.Findings[0].Rule)
+	require.Equal(t, "*pb.UnimplementedLegacyServer", serviceFile.Findings[0].Usage)
+}
+
+// TestCheckKratosUpgrade_UnreadFiles tests that vendored and test data files are skipped and broken files are listed
+//
+// TestCheckKratosUpgrade_UnreadFiles 测试 vendor 和测试数据中的文件被跳过，损坏的文件被列出
+func TestCheckKratosUpgrade_UnreadFiles(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"api/echo/v1", "vendor/example.com/broken", "testdata", ".cache", "internal/biz"} {
+		must.Done(os.MkdirAll(filepath.Join(root, dir), 0755))
+	}
+	must.Done(os.WriteFile(filepath.Join(root, "go.mod"), []byte("module shop\n\ngo 1.22\n"), 0644))
+	must.Done(os.WriteFile(filepath.Join(root, "api", "echo", "v1", "echo_grpc.pb.go"), rese.V1(os.ReadFile(filepath.Join(demoApiRoot, "echo", "v1", "echo_grpc.pb.go"))), 0644))
+	for _, path := range []string{"vendor/example.com/broken/broken.go", "testdata/broken.go", ".cache/broken.go"} {
+		must.Done(os.WriteFile(filepath.Join(root, path), []byte("package broken\n\nfunc (\n"), 0644))
+	}
+	brokenPath := filepath.Join(root, "internal", "biz", "broken.go")
+	must.Done(os.WriteFile(brokenPath, []byte("package biz\n\nfunc (\n"), 0644))
+
+	checklist, err := astkratos.CheckKratosUpgradeE(root)
+	require.NoError(t, err)
+	require.Len(t, checklist.UnreadFiles, 1)
+	require.Equal(t, brokenPath, checklist.UnreadFiles[0].Path)
+	require.Contains(t, checklist.UnreadFiles[0].Detail, brokenPath)
+
+	must.Done(os.Remove(brokenPath))
+	report, err := astkratos.AnalyzeProjectE(root)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Echo"}, collectNames(report.Services))
+	require.Empty(t, report.Upgrade.UnreadFiles)
+}
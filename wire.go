@@ -0,0 +1,435 @@
+// Package astkratos wire graph: Extraction of the wire ProviderSets and injectors of a Kratos project
+// Parses var ProviderSet = wire.NewSet(...) in internal and the wire.Build injectors in cmd
+// Links each provider to its package and constructor signature without running wire
+// Flattens the sets referenced by each injector to show how an app is assembled
+//
+// astkratos wire 图：提取 Kratos 项目中的 wire ProviderSet 和注入器
+// 解析 internal 中的 var ProviderSet = wire.NewSet(...) 和 cmd 中的 wire.Build 注入器
+// 无需运行 wire 即可将每个提供者关联到其包和构造函数签名
+// 展开每个注入器引用的集合，展示应用的组装方式
+package astkratos
+
+import (
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"strings"
+
+	"github.com/yyle88/erero"
+)
+
+// wireImportPath is the import path of the wire package
+//
+// wireImportPath 是 wire 包的导入路径
+const wireImportPath = "github.com/google/wire"
+
+// WireProviderKind represents the kind of a wire provider
+//
+// WireProviderKind 表示 wire 提供者的类型
+type WireProviderKind string
+
+const (
+	WireProviderKindFunc           WireProviderKind = "func"            // Constructor function in the project // 项目中的构造函数
+	WireProviderKindSet            WireProviderKind = "set"             // Reference to a ProviderSet // 对 ProviderSet 的引用
+	WireProviderKindBind           WireProviderKind = "bind"            // wire.Bind of an interface to an implementation // 将接口绑定到实现的 wire.Bind
+	WireProviderKindStruct         WireProviderKind = "struct"          // wire.Struct provider // wire.Struct 提供者
+	WireProviderKindValue          WireProviderKind = "value"           // wire.Value provider // wire.Value 提供者
+	WireProviderKindInterfaceValue WireProviderKind = "interface_value" // wire.InterfaceValue provider // wire.InterfaceValue 提供者
+	WireProviderKindFieldsOf       WireProviderKind = "fields_of"       // wire.FieldsOf provider // wire.FieldsOf 提供者
+	WireProviderKindExternal       WireProviderKind = "external"        // Reference into a package outside the project // 对项目外部包的引用
+	WireProviderKindUnknown        WireProviderKind = "unknown"         // Reference that cannot be resolved // 无法解析的引用
+)
+
+// WireProvider represents one argument of wire.NewSet or wire.Build
+//
+// WireProvider 表示 wire.NewSet 或 wire.Build 的一个参数
+type WireProvider struct {
+	Kind           WireProviderKind // Provider kind // 提供者类型
+	Name           string           // Reference as written, such as NewGreeterUsecase or data.ProviderSet // 源码中的引用，例如 NewGreeterUsecase 或 data.ProviderSet
+	Package        string           // Package name declaring the provider // 声明提供者的包名
+	ImportPath     string           // Import path of the declaring package // 声明包的导入路径
+	Signature      string           // Constructor signature, such as func(*biz.Greeter) error // 构造函数签名，例如 func(*biz.Greeter) error
+	Params         []string         // Qualified parameter types of the constructor // 构造函数的限定参数类型
+	Results        []string         // Qualified result types of the constructor // 构造函数的限定返回类型
+	Type           string           // Bound interface or provided type of wire helpers // wire 辅助函数绑定的接口或提供的类型
+	Implementation string           // Implementation type of wire.Bind // wire.Bind 的实现类型
+	Position       Position         // Position of the reference // 引用所在的位置
+	DeclPosition   Position         // Position of the constructor or set declaration // 构造函数或集合声明的位置
+}
+
+// WireProviderSet represents a var X = wire.NewSet(...) declaration
+//
+// WireProviderSet 表示 var X = wire.NewSet(...) 声明
+type WireProviderSet struct {
+	Name       string          // Variable name, such as ProviderSet // 变量名，例如 ProviderSet
+	Package    string          // Package name, such as biz // 包名，例如 biz
+	ImportPath string          // Import path of the package // 包的导入路径
+	Position   Position        // Position of the declaration // 声明所在的位置
+	Providers  []*WireProvider // Arguments of wire.NewSet // wire.NewSet 的参数
+}
+
+// WireInjector represents a function that calls wire.Build, such as wireApp
+//
+// WireInjector 表示调用 wire.Build 的函数，例如 wireApp
+type WireInjector struct {
+	Name              string          // Function name, such as wireApp // 函数名，例如 wireApp
+	Package           string          // Package name, such as main // 包名，例如 main
+	ImportPath        string          // Import path of the package // 包的导入路径
+	Position          Position        // Position of the function // 函数所在的位置
+	Params            []string        // Qualified parameter types, the inputs of the injector // 限定参数类型，即注入器的输入
+	Results           []string        // Qualified result types // 限定返回类型
+	Providers         []*WireProvider // Arguments of wire.Build // wire.Build 的参数
+	ResolvedProviders []*WireProvider // Providers reachable through the referenced sets, sets excluded // 通过引用集合可达的提供者，不含集合本身
+}
+
+// WireGraph holds the ProviderSets and injectors of a project
+//
+// WireGraph 保存项目中的 ProviderSet 和注入器
+type WireGraph struct {
+	ProviderSets []*WireProviderSet // ProviderSets in walk order // 按遍历顺序排列的 ProviderSet
+	Injectors    []*WireInjector    // Injectors in walk order // 按遍历顺序排列的注入器
+}
+
+// wireFuncDecl holds a top-level function with the file declaring it
+//
+// wireFuncDecl 保存顶层函数及其声明文件
+type wireFuncDecl struct {
+	pkg      *goPackage    // Declaring package // 声明包
+	goFile   *parsedGoFile // Declaring file // 声明文件
+	funcDecl *ast.FuncDecl // Function declaration // 函数声明
+}
+
+// wireGraphBuilder resolves provider references across the loaded packages
+//
+// wireGraphBuilder 在已加载的包之间解析提供者引用
+type wireGraphBuilder struct {
+	importPaths map[*goPackage]string             // Import path of each package // 每个包的导入路径
+	funcs       map[string]*wireFuncDecl          // Top-level functions keyed by importPath.Name // 以 importPath.Name 为键的顶层函数
+	sets        map[string]*WireProviderSet       // ProviderSets keyed by importPath.Name // 以 importPath.Name 为键的 ProviderSet
+	setArgs     map[*WireProviderSet]*wireSetArgs // Pending arguments of each ProviderSet // 每个 ProviderSet 待解析的参数
+}
+
+// wireSetArgs holds the unresolved arguments of a wire call
+//
+// wireSetArgs 保存 wire 调用中尚未解析的参数
+type wireSetArgs struct {
+	pkg       *goPackage    // Package of the call // 调用所在的包
+	goFile    *parsedGoFile // File of the call // 调用所在的文件
+	wireAlias string        // Local name of the wire import // wire 导入的本地名称
+	args      []ast.Expr    // Call arguments // 调用参数
+}
+
+// wireProject holds the loaded packages and the wire graph extracted from them
+//
+// wireProject 保存已加载的包以及从中提取的 wire 图
+type wireProject struct {
+	packages []*goPackage      // Packages under internal and cmd // internal 和 cmd 下的包
+	builder  *wireGraphBuilder // Reference resolver // 引用解析器
+	graph    *WireGraph        // Extracted graph // 提取出的图
+}
+
+// analyzeWireGraph extracts the ProviderSets and injectors under internal and cmd of the project
+//
+// analyzeWireGraph 提取项目 internal 和 cmd 目录下的 ProviderSet 和注入器
+func analyzeWireGraph(projectRoot string, modulePath string) (*WireGraph, error) {
+	project, err := loadWireProject(projectRoot, modulePath)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return project.graph, nil
+}
+
+// loadWireProject loads the packages under internal and cmd and extracts the wire graph
+//
+// loadWireProject 加载 internal 和 cmd 下的包并提取 wire 图
+func loadWireProject(projectRoot string, modulePath string) (*wireProject, error) {
+	projectRoot, err := filepath.Abs(projectRoot)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	var packages []*goPackage
+	for _, layer := range []string{"internal", "cmd"} {
+		layerPackages, err := loadGoPackages(filepath.Join(projectRoot, layer))
+		if err != nil {
+			return nil, erero.Wro(err)
+		}
+		packages = append(packages, layerPackages...)
+	}
+
+	builder := &wireGraphBuilder{
+		importPaths: map[*goPackage]string{},
+		funcs:       map[string]*wireFuncDecl{},
+		sets:        map[string]*WireProviderSet{},
+		setArgs:     map[*WireProviderSet]*wireSetArgs{},
+	}
+	graph := &WireGraph{
+		ProviderSets: make([]*WireProviderSet, 0),
+		Injectors:    make([]*WireInjector, 0),
+	}
+	for _, pkg := range packages {
+		relDir, err := filepath.Rel(projectRoot, pkg.dir)
+		if err != nil {
+			return nil, erero.Wro(err)
+		}
+		importPath := modulePath + "/" + filepath.ToSlash(relDir)
+		builder.importPaths[pkg] = importPath
+
+		for _, goFile := range pkg.files {
+			wireAlias := importAlias(goFile.astFile, wireImportPath)
+			for _, decl := range goFile.astFile.Decls {
+				switch x := decl.(type) {
+				case *ast.FuncDecl:
+					if x.Recv == nil {
+						builder.funcs[importPath+"."+x.Name.Name] = &wireFuncDecl{pkg: pkg, goFile: goFile, funcDecl: x}
+					}
+				case *ast.GenDecl:
+					if x.Tok != token.VAR || wireAlias == "" {
+						continue
+					}
+					for _, spec := range x.Specs {
+						valueSpec := spec.(*ast.ValueSpec)
+						for idx, name := range valueSpec.Names {
+							if idx >= len(valueSpec.Values) {
+								break
+							}
+							callExpr, ok := valueSpec.Values[idx].(*ast.CallExpr)
+							if !ok || wireCallName(callExpr, wireAlias) != "NewSet" {
+								continue
+							}
+							providerSet := &WireProviderSet{
+								Name:       name.Name,
+								Package:    pkg.name,
+								ImportPath: importPath,
+								Position:   Position{Path: goFile.srcPath, Line: goFile.fset.Position(name.Pos()).Line},
+							}
+							builder.sets[importPath+"."+name.Name] = providerSet
+							builder.setArgs[providerSet] = &wireSetArgs{pkg: pkg, goFile: goFile, wireAlias: wireAlias, args: callExpr.Args}
+							graph.ProviderSets = append(graph.ProviderSets, providerSet)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	// Resolve the set arguments once every function and set is known
+	// 在所有函数和集合都已知后解析集合参数
+	for _, providerSet := range graph.ProviderSets {
+		setArgs := builder.setArgs[providerSet]
+		providerSet.Providers = builder.resolveProviders(setArgs.pkg, setArgs.goFile, setArgs.wireAlias, setArgs.args)
+	}
+
+	for _, pkg := range packages {
+		for _, goFile := range pkg.files {
+			wireAlias := importAlias(goFile.astFile, wireImportPath)
+			if wireAlias == "" {
+				continue
+			}
+			imports := importPaths(goFile.astFile)
+			for _, decl := range goFile.astFile.Decls {
+				funcDecl, ok := decl.(*ast.FuncDecl)
+				if !ok || funcDecl.Body == nil {
+					continue
+				}
+				var buildCall *ast.CallExpr
+				ast.Inspect(funcDecl.Body, func(node ast.Node) bool {
+					if callExpr, ok := node.(*ast.CallExpr); ok && buildCall == nil && wireCallName(callExpr, wireAlias) == "Build" {
+						buildCall = callExpr
+					}
+					return buildCall == nil
+				})
+				if buildCall == nil {
+					continue
+				}
+				injector := &WireInjector{
+					Name:       funcDecl.Name.Name,
+					Package:    pkg.name,
+					ImportPath: builder.importPaths[pkg],
+					Position:   Position{Path: goFile.srcPath, Line: goFile.fset.Position(funcDecl.Pos()).Line},
+					Params:     qualifiedFieldTypes(funcDecl.Type.Params, pkg.name, imports),
+					Results:    qualifiedFieldTypes(funcDecl.Type.Results, pkg.name, imports),
+					Providers:  builder.resolveProviders(pkg, goFile, wireAlias, buildCall.Args),
+				}
+				injector.ResolvedProviders = builder.flattenProviders(injector.Providers)
+				graph.Injectors = append(graph.Injectors, injector)
+			}
+		}
+	}
+	return &wireProject{packages: packages, builder: builder, graph: graph}, nil
+}
+
+// resolveProviders converts the arguments of a wire call into providers, inlining nested wire.NewSet calls
+//
+// resolveProviders 将 wire 调用的参数转换为提供者，内联嵌套的 wire.NewSet 调用
+func (b *wireGraphBuilder) resolveProviders(pkg *goPackage, goFile *parsedGoFile, wireAlias string, args []ast.Expr) []*WireProvider {
+	imports := importPaths(goFile.astFile)
+	providers := make([]*WireProvider, 0)
+	for _, arg := range args {
+		position := Position{Path: goFile.srcPath, Line: goFile.fset.Position(arg.Pos()).Line}
+		if callExpr, ok := arg.(*ast.CallExpr); ok {
+			callName := wireCallName(callExpr, wireAlias)
+			if callName == "NewSet" {
+				providers = append(providers, b.resolveProviders(pkg, goFile, wireAlias, callExpr.Args)...)
+				continue
+			}
+			provider := &WireProvider{Kind: WireProviderKindUnknown, Name: nodeText(goFile, callExpr), Position: position}
+			switch callName {
+			case "Bind":
+				provider.Kind = WireProviderKindBind
+				if len(callExpr.Args) == 2 {
+					provider.Type = newExprType(callExpr.Args[0], pkg.name, imports)
+					provider.Implementation = newExprType(callExpr.Args[1], pkg.name, imports)
+				}
+			case "Struct":
+				provider.Kind = WireProviderKindStruct
+			case "Value":
+				provider.Kind = WireProviderKindValue
+			case "InterfaceValue":
+				provider.Kind = WireProviderKindInterfaceValue
+			case "FieldsOf":
+				provider.Kind = WireProviderKindFieldsOf
+			}
+			if provider.Kind != WireProviderKindBind && provider.Kind != WireProviderKindValue && len(callExpr.Args) > 0 {
+				provider.Type = newExprType(callExpr.Args[0], pkg.name, imports)
+			}
+			providers = append(providers, provider)
+			continue
+		}
+
+		provider := &WireProvider{Kind: WireProviderKindUnknown, Name: nodeText(goFile, arg), Position: position}
+		var importPath, name string
+		switch x := arg.(type) {
+		case *ast.Ident:
+			importPath, name = b.importPaths[pkg], x.Name
+		case *ast.SelectorExpr:
+			if ident, ok := x.X.(*ast.Ident); ok {
+				importPath, name = imports[ident.Name], x.Sel.Name
+			}
+		}
+		if funcDecl, ok := b.funcs[importPath+"."+name]; ok {
+			funcImports := importPaths(funcDecl.goFile.astFile)
+			provider.Kind = WireProviderKindFunc
+			provider.Package = funcDecl.pkg.name
+			provider.ImportPath = importPath
+			provider.Params = qualifiedFieldTypes(funcDecl.funcDecl.Type.Params, funcDecl.pkg.name, funcImports)
+			provider.Results = qualifiedFieldTypes(funcDecl.funcDecl.Type.Results, funcDecl.pkg.name, funcImports)
+			provider.Signature = newSignature(provider.Params, provider.Results)
+			provider.DeclPosition = Position{Path: funcDecl.goFile.srcPath, Line: funcDecl.goFile.fset.Position(funcDecl.funcDecl.Pos()).Line}
+		} else if providerSet, ok := b.sets[importPath+"."+name]; ok {
+			provider.Kind = WireProviderKindSet
+			provider.Package = providerSet.Package
+			provider.ImportPath = importPath
+			provider.DeclPosition = providerSet.Position
+		} else if importPath != "" && !b.isProjectImport(importPath) {
+			provider.Kind = WireProviderKindExternal
+			provider.Package = importPackageName(importPath)
+			provider.ImportPath = importPath
+		}
+		providers = append(providers, provider)
+	}
+	return providers
+}
+
+// flattenProviders follows the set references and returns the reachable providers without duplicates
+//
+// flattenProviders 跟随集合引用并返回去重后的可达提供者
+func (b *wireGraphBuilder) flattenProviders(providers []*WireProvider) []*WireProvider {
+	resolved := make([]*WireProvider, 0)
+	seen := map[string]bool{}
+	var visit func(providers []*WireProvider)
+	visit = func(providers []*WireProvider) {
+		for _, provider := range providers {
+			key := string(provider.Kind) + " " + provider.ImportPath + " " + provider.Name + " " + provider.Type
+			if provider.Kind == WireProviderKindFunc || provider.Kind == WireProviderKindSet {
+				key = string(provider.Kind) + " " + provider.ImportPath + "." + provider.Name[strings.LastIndex(provider.Name, ".")+1:]
+			}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			if provider.Kind == WireProviderKindSet {
+				if providerSet, ok := b.sets[provider.ImportPath+"."+provider.Name[strings.LastIndex(provider.Name, ".")+1:]]; ok {
+					visit(providerSet.Providers)
+				}
+				continue
+			}
+			resolved = append(resolved, provider)
+		}
+	}
+	visit(providers)
+	return resolved
+}
+
+// isProjectImport reports whether the import path belongs to a loaded package
+//
+// isProjectImport 判断导入路径是否属于已加载的包
+func (b *wireGraphBuilder) isProjectImport(importPath string) bool {
+	for _, path := range b.importPaths {
+		if path == importPath {
+			return true
+		}
+	}
+	return false
+}
+
+// importAlias returns the local name of the import path in the file, blank when not imported
+//
+// importAlias 返回文件中该导入路径的本地名称，未导入时返回空字符串
+func importAlias(astFile *ast.File, importPath string) string {
+	for name, path := range importPaths(astFile) {
+		if path == importPath {
+			return name
+		}
+	}
+	return ""
+}
+
+// wireCallName returns the wire function name of calls such as wire.NewSet, blank for other calls
+//
+// wireCallName 返回 wire.NewSet 等调用的 wire 函数名，其他调用返回空字符串
+func wireCallName(callExpr *ast.CallExpr, wireAlias string) string {
+	selectorExpr, ok := callExpr.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	if ident, ok := selectorExpr.X.(*ast.Ident); ok && ident.Name == wireAlias {
+		return selectorExpr.Sel.Name
+	}
+	return ""
+}
+
+// newExprType returns the type T of new(T) expressions, such as the arguments of wire.Bind
+//
+// newExprType 返回 new(T) 表达式中的类型 T，例如 wire.Bind 的参数
+func newExprType(expr ast.Expr, pkgName string, imports map[string]string) string {
+	if callExpr, ok := expr.(*ast.CallExpr); ok && len(callExpr.Args) == 1 {
+		if ident, ok := callExpr.Fun.(*ast.Ident); ok && ident.Name == "new" {
+			return qualifiedTypeString(callExpr.Args[0], pkgName, imports)
+		}
+	}
+	return ""
+}
+
+// newSignature renders a function signature from qualified parameter and result types
+//
+// newSignature 根据限定的参数和返回类型渲染函数签名
+func newSignature(params []string, results []string) string {
+	signature := "func(" + strings.Join(params, ", ") + ")"
+	switch len(results) {
+	case 0:
+		return signature
+	case 1:
+		return signature + " " + results[0]
+	default:
+		return signature + " (" + strings.Join(results, ", ") + ")"
+	}
+}
+
+// nodeText returns the source text of the node
+//
+// nodeText 返回节点的源码文本
+func nodeText(goFile *parsedGoFile, node ast.Node) string {
+	start := goFile.fset.Position(node.Pos()).Offset
+	end := goFile.fset.Position(node.End()).Offset
+	return string(goFile.source[start:end])
+}
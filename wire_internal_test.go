@@ -0,0 +1,70 @@
+package astkratos
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/rese"
+)
+
+// TestAnalyzeWireGraph_Helpers tests wire.Bind, wire.Struct, wire.Value, inline sets and external references
+//
+// TestAnalyzeWireGraph_Helpers 测试 wire.Bind、wire.Struct、wire.Value、内联集合和外部引用
+func TestAnalyzeWireGraph_Helpers(t *testing.T) {
+	root := t.TempDir()
+	dataRoot := filepath.Join(root, "internal", "data")
+	must.Done(os.MkdirAll(dataRoot, 0755))
+	must.Done(os.WriteFile(filepath.Join(dataRoot, "data.go"), []byte(`package data
+
+import (
+	"demo/internal/biz"
+
+	"github.com/go-kratos/kratos/v2/log"
+	gw "github.com/google/wire"
+)
+
+var ProviderSet = gw.NewSet(
+	NewRepo,
+	gw.Bind(new(biz.Repo), new(*repo)),
+	gw.NewSet(gw.Struct(new(Options), "*")),
+	gw.Value(Options{}),
+	log.NewStdLogger,
+	missing,
+)
+
+type Options struct{}
+
+type repo struct{}
+
+func NewRepo(opts Options) *repo {
+	return &repo{}
+}
+`), 0644))
+
+	graph := rese.P1(analyzeWireGraph(root, "demo"))
+	require.Len(t, graph.ProviderSets, 1)
+	providers := graph.ProviderSets[0].Providers
+	require.Len(t, providers, 6)
+
+	require.Equal(t, WireProviderKindFunc, providers[0].Kind)
+	require.Equal(t, "func(data.Options) *data.repo", providers[0].Signature)
+
+	require.Equal(t, WireProviderKindBind, providers[1].Kind)
+	require.Equal(t, "biz.Repo", providers[1].Type)
+	require.Equal(t, "*data.repo", providers[1].Implementation)
+	require.Equal(t, 12, providers[1].Position.Line)
+
+	require.Equal(t, WireProviderKindStruct, providers[2].Kind)
+	require.Equal(t, "data.Options", providers[2].Type)
+	require.Equal(t, WireProviderKindValue, providers[3].Kind)
+
+	require.Equal(t, WireProviderKindExternal, providers[4].Kind)
+	require.Equal(t, "log", providers[4].Package)
+	require.Equal(t, "github.com/go-kratos/kratos/v2/log", providers[4].ImportPath)
+
+	require.Equal(t, WireProviderKindUnknown, providers[5].Kind)
+	require.Equal(t, "missing", providers[5].Name)
+}
@@ -0,0 +1,57 @@
+package astkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/astkratos"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/neatjson/neatjsons"
+	"github.com/yyle88/runpath"
+)
+
+// TestGetWireGraph tests ProviderSet extraction and the flattened providers of the wireApp injector
+//
+// TestGetWireGraph 测试 ProviderSet 提取以及 wireApp 注入器展开后的提供者
+func TestGetWireGraph(t *testing.T) {
+	graph := astkratos.GetWireGraph(runpath.PARENT.Join("testdata", "demokratos"))
+	t.Log(neatjsons.S(graph))
+
+	var setPackages []string
+	for _, providerSet := range graph.ProviderSets {
+		setPackages = append(setPackages, providerSet.Package+"."+providerSet.Name)
+	}
+	require.Equal(t, []string{"biz.ProviderSet", "data.ProviderSet", "server.ProviderSet", "service.ProviderSet"}, setPackages)
+
+	newData := graph.ProviderSets[1].Providers[0]
+	require.Equal(t, astkratos.WireProviderKindFunc, newData.Kind)
+	require.Equal(t, "NewData", newData.Name)
+	require.Equal(t, "demokratos/internal/data", newData.ImportPath)
+	require.Equal(t, "func(*conf.Data, log.Logger) (*data.Data, func(), error)", newData.Signature)
+	require.Equal(t, runpath.PARENT.Join("testdata", "demokratos", "internal", "data", "data.go"), newData.DeclPosition.Path)
+	require.Equal(t, 19, newData.DeclPosition.Line)
+
+	require.Len(t, graph.Injectors, 1)
+	injector := graph.Injectors[0]
+	require.Equal(t, "wireApp", injector.Name)
+	require.Equal(t, "main", injector.Package)
+	require.Equal(t, []string{"*conf.Server", "*conf.Data", "log.Logger"}, injector.Params)
+	require.Equal(t, []string{"*kratos.App", "func()", "error"}, injector.Results)
+	require.Len(t, injector.Providers, 5)
+	require.Equal(t, astkratos.WireProviderKindSet, injector.Providers[0].Kind)
+	require.Equal(t, "server.ProviderSet", injector.Providers[0].Name)
+
+	var resolved []string
+	for _, provider := range injector.ResolvedProviders {
+		resolved = append(resolved, provider.Package+"."+provider.Name)
+	}
+	require.Equal(t, []string{
+		"server.NewGRPCServer",
+		"server.NewHTTPServer",
+		"data.NewData",
+		"data.NewGreeterRepo",
+		"biz.NewGreeterUsecase",
+		"service.NewGreeterService",
+		"service.NewEchoService",
+		"main.newApp",
+	}, resolved)
+}
@@ -0,0 +1,263 @@
+// Package astkratos wire staleness: Comparison of wire_gen.go with wire.go and the ProviderSets
+// Collects the constructor calls of each generated injector in wire_gen.go
+// Reports providers added to the sets but never called, calls of providers that were removed,
+// and calls whose argument or result counts or argument types no longer match the constructor signature
+//
+// astkratos wire 过期检查：比较 wire_gen.go 与 wire.go 和 ProviderSet
+// 收集 wire_gen.go 中每个生成注入器的构造函数调用
+// 报告已加入集合但从未被调用的提供者、已被移除但仍被调用的提供者，
+// 以及参数或返回值数量或参数类型与构造函数签名不再一致的调用
+package astkratos
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+)
+
+// WireStaleKind represents how wire_gen.go lags behind the providers
+//
+// WireStaleKind 表示 wire_gen.go 落后于提供者的方式
+type WireStaleKind string
+
+const (
+	WireStaleAdded           WireStaleKind = "added"            // Provider in the sets but not called in wire_gen.go // 在集合中但 wire_gen.go 中未调用的提供者
+	WireStaleRemoved         WireStaleKind = "removed"          // Called in wire_gen.go but no longer in the sets // wire_gen.go 中调用但已不在集合中
+	WireStaleChanged         WireStaleKind = "changed"          // Called with a different signature than declared // 调用方式与声明的签名不一致
+	WireStaleMissingInjector WireStaleKind = "missing_injector" // Injector in wire.go without a generated function // wire.go 中的注入器没有对应的生成函数
+)
+
+// WireStaleness represents one difference between wire_gen.go and the current providers
+//
+// WireStaleness 表示 wire_gen.go 与当前提供者之间的一处差异
+type WireStaleness struct {
+	Kind              WireStaleKind // Added, removed, changed or missing_injector // 新增、移除、变更或缺少注入器
+	Injector          string        // Injector name, such as wireApp // 注入器名称，例如 wireApp
+	Provider          string        // Provider as package.Name, such as data.NewData // 以 package.Name 表示的提供者，例如 data.NewData
+	ImportPath        string        // Import path of the provider package // 提供者所在包的导入路径
+	Detail            string        // Human readable description // 可读的描述
+	Position          Position      // Position of the provider declaration or the injector in wire.go // 提供者声明或 wire.go 中注入器的位置
+	GeneratedPosition Position      // Position in wire_gen.go // 在 wire_gen.go 中的位置
+}
+
+// wireGenCall represents a constructor call in a generated injector
+//
+// wireGenCall 表示生成注入器中的一次构造函数调用
+type wireGenCall struct {
+	key      string        // importPath.Name of the callee // 被调用者的 importPath.Name
+	decl     *wireFuncDecl // Callee declaration // 被调用者的声明
+	args     int           // Number of arguments // 参数数量
+	argTypes []string      // Qualified type of each argument, blank when unknown // 每个参数的限定类型，未知时为空
+	lhs      int           // Number of assigned values, 0 when not assigned // 赋值的数量，未赋值时为 0
+	position Position      // Position of the call // 调用所在的位置
+}
+
+// checkStaleness compares each injector of wire.go with its generated function in wire_gen.go
+//
+// checkStaleness 比较 wire.go 中的每个注入器与 wire_gen.go 中对应的生成函数
+func (p *wireProject) checkStaleness() []*WireStaleness {
+	stalenesses := make([]*WireStaleness, 0)
+	for _, injector := range p.graph.Injectors {
+		pkg, goFile, funcDecl := p.findGeneratedInjector(injector)
+		if funcDecl == nil {
+			stalenesses = append(stalenesses, &WireStaleness{
+				Kind:     WireStaleMissingInjector,
+				Injector: injector.Name,
+				Detail:   "injector " + injector.Name + " has no generated function, run wire",
+				Position: injector.Position,
+			})
+			continue
+		}
+		generatedPosition := Position{Path: goFile.srcPath, Line: goFile.fset.Position(funcDecl.Pos()).Line}
+		calls := p.collectGeneratedCalls(pkg, goFile, funcDecl)
+
+		providers := map[string]*WireProvider{}
+		binds := map[string]bool{}
+		for _, provider := range injector.ResolvedProviders {
+			switch provider.Kind {
+			case WireProviderKindFunc:
+				providers[wireProviderKey(provider)] = provider
+			case WireProviderKindBind:
+				// wire passes the implementation where the bound interface is expected
+				// wire 在需要绑定接口的地方传入实现
+				binds[provider.Type+" "+provider.Implementation] = true
+			}
+		}
+		called := map[string]bool{}
+		for _, call := range calls {
+			called[call.key] = true
+			providerName := call.decl.pkg.name + "." + call.decl.funcDecl.Name.Name
+			declPosition := Position{Path: call.decl.goFile.srcPath, Line: call.decl.goFile.fset.Position(call.decl.funcDecl.Pos()).Line}
+			provider, ok := providers[call.key]
+			if !ok {
+				stalenesses = append(stalenesses, &WireStaleness{
+					Kind:              WireStaleRemoved,
+					Injector:          injector.Name,
+					Provider:          providerName,
+					ImportPath:        call.key[:strings.LastIndex(call.key, ".")],
+					Detail:            providerName + " is called in wire_gen.go but is no longer provided to " + injector.Name,
+					Position:          declPosition,
+					GeneratedPosition: call.position,
+				})
+				continue
+			}
+			var changes []string
+			if call.args != len(provider.Params) {
+				changes = append(changes, fmt.Sprintf("%d params, generated call passes %d", len(provider.Params), call.args))
+			}
+			if call.lhs > 0 && call.lhs != len(provider.Results) {
+				changes = append(changes, fmt.Sprintf("%d results, generated call assigns %d", len(provider.Results), call.lhs))
+			}
+			if call.args == len(provider.Params) {
+				for idx, argType := range call.argTypes {
+					if argType != "" && argType != provider.Params[idx] && !binds[provider.Params[idx]+" "+argType] {
+						changes = append(changes, fmt.Sprintf("param %d of type %s, generated call passes %s", idx+1, provider.Params[idx], argType))
+					}
+				}
+			}
+			if len(changes) > 0 {
+				stalenesses = append(stalenesses, &WireStaleness{
+					Kind:              WireStaleChanged,
+					Injector:          injector.Name,
+					Provider:          providerName,
+					ImportPath:        provider.ImportPath,
+					Detail:            providerName + " now has " + strings.Join(changes, "; "),
+					Position:          declPosition,
+					GeneratedPosition: call.position,
+				})
+			}
+		}
+		for _, provider := range injector.ResolvedProviders {
+			if provider.Kind != WireProviderKindFunc || called[wireProviderKey(provider)] {
+				continue
+			}
+			providerName := provider.Package + "." + provider.Name[strings.LastIndex(provider.Name, ".")+1:]
+			stalenesses = append(stalenesses, &WireStaleness{
+				Kind:              WireStaleAdded,
+				Injector:          injector.Name,
+				Provider:          providerName,
+				ImportPath:        provider.ImportPath,
+				Detail:            providerName + " is provided to " + injector.Name + " but never called in wire_gen.go",
+				Position:          provider.DeclPosition,
+				GeneratedPosition: generatedPosition,
+			})
+		}
+	}
+	return stalenesses
+}
+
+// findGeneratedInjector finds the function of the injector package with the same name and without wire.Build
+//
+// findGeneratedInjector 查找注入器所在包中同名且不调用 wire.Build 的函数
+func (p *wireProject) findGeneratedInjector(injector *WireInjector) (*goPackage, *parsedGoFile, *ast.FuncDecl) {
+	for _, pkg := range p.packages {
+		if p.builder.importPaths[pkg] != injector.ImportPath {
+			continue
+		}
+		for _, goFile := range pkg.files {
+			wireAlias := importAlias(goFile.astFile, wireImportPath)
+			for _, decl := range goFile.astFile.Decls {
+				funcDecl, ok := decl.(*ast.FuncDecl)
+				if !ok || funcDecl.Recv != nil || funcDecl.Body == nil || funcDecl.Name.Name != injector.Name {
+					continue
+				}
+				if wireAlias != "" && containsWireBuild(funcDecl, wireAlias) {
+					continue
+				}
+				return pkg, goFile, funcDecl
+			}
+		}
+	}
+	return nil, nil, nil
+}
+
+// collectGeneratedCalls collects the calls of project functions in the generated injector body
+// Tracks the types of the injector params and of the values assigned from project calls,
+// so that each argument passed as such a variable gets its qualified type
+//
+// collectGeneratedCalls 收集生成注入器函数体中对项目函数的调用
+// 跟踪注入器参数以及由项目函数调用赋值的变量的类型，
+// 使以这些变量传入的每个参数都能得到其限定类型
+func (p *wireProject) collectGeneratedCalls(pkg *goPackage, goFile *parsedGoFile, funcDecl *ast.FuncDecl) []*wireGenCall {
+	imports := importPaths(goFile.astFile)
+	assigned := map[*ast.CallExpr][]ast.Expr{}
+	ast.Inspect(funcDecl.Body, func(node ast.Node) bool {
+		if assignStmt, ok := node.(*ast.AssignStmt); ok && len(assignStmt.Rhs) == 1 {
+			if callExpr, ok := assignStmt.Rhs[0].(*ast.CallExpr); ok {
+				assigned[callExpr] = assignStmt.Lhs
+			}
+		}
+		return true
+	})
+	varTypes := map[string]string{}
+	for _, field := range funcDecl.Type.Params.List {
+		for _, name := range field.Names {
+			varTypes[name.Name] = qualifiedTypeString(field.Type, pkg.name, imports)
+		}
+	}
+
+	var calls []*wireGenCall
+	ast.Inspect(funcDecl.Body, func(node ast.Node) bool {
+		callExpr, ok := node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		var key string
+		switch x := callExpr.Fun.(type) {
+		case *ast.Ident:
+			key = p.builder.importPaths[pkg] + "." + x.Name
+		case *ast.SelectorExpr:
+			if ident, ok := x.X.(*ast.Ident); ok && imports[ident.Name] != "" {
+				key = imports[ident.Name] + "." + x.Sel.Name
+			}
+		}
+		if decl, ok := p.builder.funcs[key]; ok {
+			argTypes := make([]string, 0, len(callExpr.Args))
+			for _, arg := range callExpr.Args {
+				var argType string
+				if ident, ok := arg.(*ast.Ident); ok {
+					argType = varTypes[ident.Name]
+				}
+				argTypes = append(argTypes, argType)
+			}
+			lhs := assigned[callExpr]
+			results := qualifiedFieldTypes(decl.funcDecl.Type.Results, decl.pkg.name, importPaths(decl.goFile.astFile))
+			for idx, expr := range lhs {
+				if ident, ok := expr.(*ast.Ident); ok && idx < len(results) {
+					varTypes[ident.Name] = results[idx]
+				}
+			}
+			calls = append(calls, &wireGenCall{
+				key:      key,
+				decl:     decl,
+				args:     len(callExpr.Args),
+				argTypes: argTypes,
+				lhs:      len(lhs),
+				position: Position{Path: goFile.srcPath, Line: goFile.fset.Position(callExpr.Pos()).Line},
+			})
+		}
+		return true
+	})
+	return calls
+}
+
+// containsWireBuild reports whether the function body calls wire.Build
+//
+// containsWireBuild 判断函数体是否调用 wire.Build
+func containsWireBuild(funcDecl *ast.FuncDecl, wireAlias string) bool {
+	found := false
+	ast.Inspect(funcDecl.Body, func(node ast.Node) bool {
+		if callExpr, ok := node.(*ast.CallExpr); ok && wireCallName(callExpr, wireAlias) == "Build" {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// wireProviderKey returns importPath.Name of a function provider
+//
+// wireProviderKey 返回函数提供者的 importPath.Name
+func wireProviderKey(provider *WireProvider) string {
+	return provider.ImportPath + "." + provider.Name[strings.LastIndex(provider.Name, ".")+1:]
+}
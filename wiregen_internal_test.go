@@ -0,0 +1,202 @@
+package astkratos
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/rese"
+)
+
+// TestWireProject_CheckStaleness tests added, removed and changed providers and an injector without generated code
+//
+// TestWireProject_CheckStaleness 测试新增、移除、变更的提供者以及没有生成代码的注入器
+func TestWireProject_CheckStaleness(t *testing.T) {
+	root := t.TempDir()
+	dataRoot := filepath.Join(root, "internal", "data")
+	must.Done(os.MkdirAll(dataRoot, 0755))
+	must.Done(os.WriteFile(filepath.Join(dataRoot, "data.go"), []byte(`package data
+
+import "github.com/google/wire"
+
+var ProviderSet = wire.NewSet(NewData, NewRepo, NewCache)
+
+type Data struct{}
+
+type Repo struct{}
+
+type Cache struct{}
+
+func NewData() (*Data, func(), error) {
+	return &Data{}, func() {}, nil
+}
+
+func NewRepo(data *Data, cache *Cache) *Repo {
+	return &Repo{}
+}
+
+func NewCache() *Cache {
+	return &Cache{}
+}
+
+func NewLegacy(data *Data) *Repo {
+	return &Repo{}
+}
+`), 0644))
+
+	cmdRoot := filepath.Join(root, "cmd", "app")
+	must.Done(os.MkdirAll(cmdRoot, 0755))
+	must.Done(os.WriteFile(filepath.Join(cmdRoot, "wire.go"), []byte(`//go:build wireinject
+
+package main
+
+import (
+	"demo/internal/data"
+
+	"github.com/google/wire"
+)
+
+func wireApp() (*data.Repo, func(), error) {
+	panic(wire.Build(data.ProviderSet))
+}
+
+func wireTool() (*data.Cache, error) {
+	panic(wire.Build(data.NewCache))
+}
+`), 0644))
+	must.Done(os.WriteFile(filepath.Join(cmdRoot, "wire_gen.go"), []byte(`// Code generated by Wire. DO NOT EDIT.
+
+//go:build !wireinject
+
+package main
+
+import (
+	"demo/internal/data"
+)
+
+func wireApp() (*data.Repo, func(), error) {
+	dataData, cleanup, err := data.NewData()
+	if err != nil {
+		return nil, nil, err
+	}
+	legacy := data.NewLegacy(dataData)
+	_ = legacy
+	repo := data.NewRepo(dataData)
+	return repo, func() {
+		cleanup()
+	}, nil
+}
+`), 0644))
+
+	project := rese.P1(loadWireProject(root, "demo"))
+	stalenesses := project.checkStaleness()
+	require.Len(t, stalenesses, 4)
+
+	require.Equal(t, WireStaleRemoved, stalenesses[0].Kind)
+	require.Equal(t, "wireApp", stalenesses[0].Injector)
+	require.Equal(t, "data.NewLegacy", stalenesses[0].Provider)
+	require.Equal(t, "demo/internal/data", stalenesses[0].ImportPath)
+	require.Equal(t, 25, stalenesses[0].Position.Line)
+	require.Equal(t, 16, stalenesses[0].GeneratedPosition.Line)
+
+	require.Equal(t, WireStaleChanged, stalenesses[1].Kind)
+	require.Equal(t, "data.NewRepo", stalenesses[1].Provider)
+	require.Equal(t, "data.NewRepo now has 2 params, generated call passes 1", stalenesses[1].Detail)
+	require.Equal(t, 18, stalenesses[1].GeneratedPosition.Line)
+
+	require.Equal(t, WireStaleAdded, stalenesses[2].Kind)
+	require.Equal(t, "data.NewCache", stalenesses[2].Provider)
+	require.Equal(t, 21, stalenesses[2].Position.Line)
+	require.Equal(t, 11, stalenesses[2].GeneratedPosition.Line)
+
+	require.Equal(t, WireStaleMissingInjector, stalenesses[3].Kind)
+	require.Equal(t, "wireTool", stalenesses[3].Injector)
+	require.Equal(t, 15, stalenesses[3].Position.Line)
+}
+
+// TestWireProject_CheckStaleness_ParamTypes tests a changed param type with the same count and a bound interface
+//
+// TestWireProject_CheckStaleness_ParamTypes 测试数量不变但类型变更的参数以及绑定的接口
+func TestWireProject_CheckStaleness_ParamTypes(t *testing.T) {
+	root := t.TempDir()
+	dataRoot := filepath.Join(root, "internal", "data")
+	must.Done(os.MkdirAll(dataRoot, 0755))
+	must.Done(os.WriteFile(filepath.Join(dataRoot, "data.go"), []byte(`package data
+
+import "github.com/google/wire"
+
+var ProviderSet = wire.NewSet(NewData, NewCache, NewRepo, NewUsecase, wire.Bind(new(Store), new(*Repo)))
+
+type Data struct{}
+
+type Cache struct{}
+
+type Repo struct{}
+
+type Store interface{}
+
+type Usecase struct{}
+
+func NewData() *Data {
+	return &Data{}
+}
+
+func NewCache() *Cache {
+	return &Cache{}
+}
+
+func NewRepo(cache *Cache) *Repo {
+	return &Repo{}
+}
+
+func NewUsecase(store Store, data *Data) *Usecase {
+	return &Usecase{}
+}
+`), 0644))
+
+	cmdRoot := filepath.Join(root, "cmd", "app")
+	must.Done(os.MkdirAll(cmdRoot, 0755))
+	must.Done(os.WriteFile(filepath.Join(cmdRoot, "wire.go"), []byte(`//go:build wireinject
+
+package main
+
+import (
+	"demo/internal/data"
+
+	"github.com/google/wire"
+)
+
+func wireApp() *data.Usecase {
+	panic(wire.Build(data.ProviderSet))
+}
+`), 0644))
+	must.Done(os.WriteFile(filepath.Join(cmdRoot, "wire_gen.go"), []byte(`// Code generated by Wire. DO NOT EDIT.
+
+//go:build !wireinject
+
+package main
+
+import (
+	"demo/internal/data"
+)
+
+func wireApp() *data.Usecase {
+	dataData := data.NewData()
+	cache := data.NewCache()
+	repo := data.NewRepo(dataData)
+	usecase := data.NewUsecase(repo, dataData)
+	_ = cache
+	return usecase
+}
+`), 0644))
+
+	project := rese.P1(loadWireProject(root, "demo"))
+	stalenesses := project.checkStaleness()
+	require.Len(t, stalenesses, 1)
+	require.Equal(t, WireStaleChanged, stalenesses[0].Kind)
+	require.Equal(t, "data.NewRepo", stalenesses[0].Provider)
+	require.Equal(t, "data.NewRepo now has param 1 of type *data.Cache, generated call passes *data.Data", stalenesses[0].Detail)
+	require.Equal(t, 14, stalenesses[0].GeneratedPosition.Line)
+}
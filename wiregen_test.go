@@ -0,0 +1,17 @@
+package astkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/astkratos"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/runpath"
+)
+
+// TestCheckWireStaleness tests that the demo wire_gen.go is in sync with the ProviderSets
+//
+// TestCheckWireStaleness 测试示例 wire_gen.go 与 ProviderSet 保持同步
+func TestCheckWireStaleness(t *testing.T) {
+	stalenesses := astkratos.CheckWireStaleness(runpath.PARENT.Join("testdata", "demokratos"))
+	require.Empty(t, stalenesses)
+}
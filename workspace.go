@@ -0,0 +1,280 @@
+// Package astkratos workspace: Discovery and analysis of every Kratos app in a monorepo
+// Finds the apps by their cmd and internal/server directories, skipping vendor and hidden trees
+// Locates the go.mod governing each app and the api tree it uses, even when shared at the repo root
+// Returns one report per app along with a summary adding up the findings
+//
+// astkratos 工作区：发现并分析单仓库中的每个 Kratos 应用
+// 通过 cmd 和 internal/server 目录查找应用，跳过 vendor 和隐藏目录
+// 定位管理每个应用的 go.mod 以及应用使用的 api 目录树，包括位于仓库根目录的共享 api
+// 返回每个应用的报告以及汇总发现结果的摘要
+package astkratos
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yyle88/erero"
+	"github.com/yyle88/osexistpath"
+)
+
+// workspaceSkipDirs lists the directory names never searched for apps
+//
+// workspaceSkipDirs 列出从不搜索应用的目录名称
+var workspaceSkipDirs = map[string]bool{
+	"vendor":       true,
+	"node_modules": true,
+	"third_party":  true,
+}
+
+// WorkspaceApp represents one Kratos app found in the workspace
+//
+// WorkspaceApp 表示在工作区中找到的一个 Kratos 应用
+type WorkspaceApp struct {
+	Name       string         `json:"name"`       // Slash path relative to the workspace root, such as app/shop/service // 相对工作区根目录的斜杠路径，例如 app/shop/service
+	Root       string         `json:"root"`       // Absolute app root // 应用根目录的绝对路径
+	ModuleRoot string         `json:"moduleRoot"` // Directory of the go.mod governing the app // 管理该应用的 go.mod 所在目录
+	ImportPath string         `json:"importPath"` // Import path of the app root // 应用根目录的导入路径
+	ApiRoot    string         `json:"apiRoot"`    // Api tree used by the app, blank when none // 应用使用的 api 目录树，没有时为空
+	Report     *ProjectReport `json:"report"`     // Analysis report of the app // 应用的分析报告
+}
+
+// WorkspaceSummary adds up the findings of every app
+//
+// WorkspaceSummary 汇总每个应用的发现结果
+type WorkspaceSummary struct {
+	Apps              int `json:"apps"`              // Number of apps // 应用数量
+	Modules           int `json:"modules"`           // Number of distinct modules // 不同模块的数量
+	Services          int `json:"services"`          // Number of gRPC services // gRPC 服务数量
+	HttpRoutes        int `json:"httpRoutes"`        // Number of HTTP routes // HTTP 路由数量
+	UnimplementedRpcs int `json:"unimplementedRpcs"` // Number of RPCs falling back to the Unimplemented stub // 回退到 Unimplemented 存根的 RPC 数量
+	ProtoDrifts       int `json:"protoDrifts"`       // Number of proto drifts // proto 差异数量
+	GeneratedIssues   int `json:"generatedIssues"`   // Number of generated file inconsistencies // 生成文件不一致的数量
+	WireStaleness     int `json:"wireStaleness"`     // Number of wire_gen.go differences // wire_gen.go 差异数量
+	LayerViolations   int `json:"layerViolations"`   // Number of forbidden imports // 被禁止的导入数量
+	ConfigIssues      int `json:"configIssues"`      // Number of config file problems // 配置文件问题数量
+	UpgradeFindings   int `json:"upgradeFindings"`   // Number of usages to revisit before bumping Kratos // 升级 Kratos 前需要重新检查的用法数量
+}
+
+// WorkspaceReport holds the reports of every app in the workspace and their summary
+//
+// WorkspaceReport 保存工作区中每个应用的报告及其摘要
+type WorkspaceReport struct {
+	Root    string            `json:"root"`    // Absolute workspace root // 工作区根目录的绝对路径
+	Apps    []*WorkspaceApp   `json:"apps"`    // Apps in walk order // 按遍历顺序排列的应用
+	Summary *WorkspaceSummary `json:"summary"` // Aggregate of the app reports // 应用报告的汇总
+	GoWork  *WorkspaceModules `json:"goWork"`  // Modules of go.work in the root, nil without go.work // 根目录中 go.work 的模块，没有 go.work 时为 nil
+}
+
+// analyzeWorkspace finds the apps under the root and analyzes each of them
+//
+// analyzeWorkspace 查找根目录下的应用并逐个分析
+func analyzeWorkspace(root string) (*WorkspaceReport, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	appRoots, err := findWorkspaceApps(root)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+
+	report := &WorkspaceReport{
+		Root:    root,
+		Apps:    make([]*WorkspaceApp, 0, len(appRoots)),
+		Summary: &WorkspaceSummary{},
+	}
+	modules := map[string]bool{}
+	for _, appRoot := range appRoots {
+		module, err := resolveProjectModule(appRoot)
+		if err != nil {
+			return nil, erero.Wro(err)
+		}
+		moduleRoot, moduleInfo, importPath := module.root, module.info, module.importPath
+		apiRoot := findWorkspaceApiRoot(root, appRoot)
+		var apiRoots []string
+		if apiRoot != "" {
+			apiRoots = []string{apiRoot}
+		}
+		projectReport, err := NewAnalyzer().analyzeProject(context.Background(), appRoot, apiRoots, importPath, moduleInfo)
+		if err != nil {
+			return nil, erero.Wrapf(err, "analyze app %s", appRoot)
+		}
+		name, err := filepath.Rel(root, appRoot)
+		if err != nil {
+			return nil, erero.Wro(err)
+		}
+		report.Apps = append(report.Apps, &WorkspaceApp{
+			Name:       filepath.ToSlash(name),
+			Root:       appRoot,
+			ModuleRoot: moduleRoot,
+			ImportPath: importPath,
+			ApiRoot:    apiRoot,
+			Report:     projectReport,
+		})
+		modules[moduleRoot] = true
+		report.Summary.add(projectReport)
+	}
+	report.Summary.Modules = len(modules)
+
+	// Resolve the modules of go.work so that cross-module requires point at local directories
+	// 解析 go.work 中的模块，使跨模块依赖指向本地目录
+	if info, err := os.Stat(filepath.Join(root, "go.work")); err == nil && !info.IsDir() {
+		if report.GoWork, err = GetWorkspaceModules(root); err != nil {
+			return nil, erero.Wro(err)
+		}
+	}
+	return report, nil
+}
+
+// add adds the findings of one app report to the summary
+//
+// add 将单个应用报告的发现结果累加到摘要
+func (s *WorkspaceSummary) add(report *ProjectReport) {
+	s.Apps++
+	s.Services += len(report.Services)
+	s.HttpRoutes += len(report.HttpRoutes)
+	for _, coverage := range report.Coverage {
+		s.UnimplementedRpcs += len(coverage.UnimplementedRpcs)
+	}
+	s.ProtoDrifts += len(report.ProtoDrifts)
+	s.GeneratedIssues += len(report.GeneratedIssues)
+	s.WireStaleness += len(report.WireStaleness)
+	s.LayerViolations += len(report.LayerViolations)
+	s.ConfigIssues += len(report.ConfigIssues)
+	for _, upgradeFile := range report.Upgrade.Files {
+		s.UpgradeFindings += len(upgradeFile.Findings)
+	}
+}
+
+// findWorkspaceApps walks the root and returns the directories holding cmd and internal/server
+// Apps are not searched for nested apps
+//
+// findWorkspaceApps 遍历根目录并返回包含 cmd 和 internal/server 的目录
+// 不会在应用内部继续搜索嵌套的应用
+func findWorkspaceApps(root string) ([]string, error) {
+	if _, err := osexistpath.ROOT(root); err != nil {
+		return nil, erero.Wro(err)
+	}
+	appRoots := make([]string, 0)
+	if err := filepath.WalkDir(root, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return erero.Wro(err)
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		if path != root && (strings.HasPrefix(entry.Name(), ".") || workspaceSkipDirs[entry.Name()]) {
+			return filepath.SkipDir
+		}
+		if isDirectory(filepath.Join(path, "cmd")) && isDirectory(filepath.Join(path, "internal", "server")) {
+			appRoots = append(appRoots, path)
+			return filepath.SkipDir
+		}
+		return nil
+	}); err != nil {
+		return nil, erero.Wro(err)
+	}
+	return appRoots, nil
+}
+
+// projectModule holds the go.mod governing a project root and the import path of that root
+//
+// projectModule 保存管理项目根目录的 go.mod 以及该根目录的导入路径
+type projectModule struct {
+	root       string      // Directory holding go.mod // 包含 go.mod 的目录
+	info       *ModuleInfo // Parsed go.mod // 解析后的 go.mod
+	importPath string      // Import path of the project root, such as ws/app/shop/service // 项目根目录的导入路径，例如 ws/app/shop/service
+}
+
+// resolveProjectModule finds the go.mod governing the project root and derives the import path of the root
+// The import path is the module path joined with the directory of the root relative to the module root,
+// so that an app nested in a monorepo sees its own packages as local
+//
+// resolveProjectModule 查找管理项目根目录的 go.mod 并推导该根目录的导入路径
+// 导入路径为模块路径拼接上项目根目录相对模块根目录的路径，
+// 使嵌套在单仓库中的应用将自身的包视为本地包
+func resolveProjectModule(projectRoot string) (*projectModule, error) {
+	projectRoot, err := filepath.Abs(projectRoot)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	moduleRoot := findModuleRoot(projectRoot)
+	if moduleRoot == "" {
+		return nil, erero.Errorf("no go.mod governs the app %s", projectRoot)
+	}
+	moduleInfo, err := ParseModuleFile(filepath.Join(moduleRoot, "go.mod"))
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	relDir, err := filepath.Rel(moduleRoot, projectRoot)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	importPath := moduleInfo.Module.Path
+	if relDir != "." {
+		importPath += "/" + filepath.ToSlash(relDir)
+	}
+	return &projectModule{root: moduleRoot, info: moduleInfo, importPath: importPath}, nil
+}
+
+// findModuleRoot returns the nearest directory holding go.mod at or above the app root
+//
+// findModuleRoot 返回应用根目录或其上层中最近的包含 go.mod 的目录
+func findModuleRoot(appRoot string) string {
+	for dir := appRoot; ; dir = filepath.Dir(dir) {
+		if info, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil && !info.IsDir() {
+			return dir
+		}
+		if filepath.Dir(dir) == dir {
+			return ""
+		}
+	}
+}
+
+// findWorkspaceApiRoot returns the api tree of the app, searching from the app root up to the workspace root
+// A shared api tree is narrowed to the deepest subtree mirroring the leading parts of the app path,
+// taken in full or below the container directory of the apps, so app/shop/service uses api/shop/service
+// when it exists, and app/user/service never uses api/service
+//
+// findWorkspaceApiRoot 返回应用的 api 目录树，从应用根目录向上搜索到工作区根目录
+// 共享的 api 目录树会缩小到与应用路径前导部分对应的最深子树，应用路径取完整路径或应用容器目录之下的部分，
+// 因此 app/shop/service 在 api/shop/service 存在时会使用它，而 app/user/service 不会使用 api/service
+func findWorkspaceApiRoot(root string, appRoot string) string {
+	for dir := appRoot; ; dir = filepath.Dir(dir) {
+		apiRoot := filepath.Join(dir, "api")
+		if isDirectory(apiRoot) {
+			if dir == appRoot {
+				return apiRoot
+			}
+			relDir, err := filepath.Rel(dir, appRoot)
+			if err != nil {
+				return apiRoot
+			}
+			parts := strings.Split(filepath.ToSlash(relDir), "/")
+			// Start at the full path, then below the container directory such as app, never deeper
+			// 先从完整路径开始，再从 app 等容器目录之下开始，不会更深
+			for start := 0; start < min(len(parts), 2); start++ {
+				for end := len(parts); end > start; end-- {
+					subRoot := filepath.Join(append([]string{apiRoot}, parts[start:end]...)...)
+					if isDirectory(subRoot) {
+						return subRoot
+					}
+				}
+			}
+			return apiRoot
+		}
+		if dir == root || filepath.Dir(dir) == dir {
+			return ""
+		}
+	}
+}
+
+// isDirectory reports whether the path is an existing directory
+//
+// isDirectory 判断路径是否为已存在的目录
+func isDirectory(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,147 @@
+package astkratos_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/orzkratos/astkratos"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/neatjson/neatjsons"
+	"github.com/yyle88/runpath"
+)
+
+// TestAnalyzeWorkspace tests that the demo project is found as the single app of testdata
+//
+// TestAnalyzeWorkspace 测试示例项目被识别为 testdata 中唯一的应用
+func TestAnalyzeWorkspace(t *testing.T) {
+	report := astkratos.AnalyzeWorkspace(runpath.PARENT.Join("testdata"))
+	t.Log(neatjsons.S(report.Summary))
+
+	require.Len(t, report.Apps, 1)
+	app := report.Apps[0]
+	require.Equal(t, "demokratos", app.Name)
+	require.Equal(t, runpath.PARENT.Join("testdata", "demokratos"), app.ModuleRoot)
+	require.Equal(t, "demokratos", app.ImportPath)
+	require.Equal(t, runpath.PARENT.Join("testdata", "demokratos", "api"), app.ApiRoot)
+	require.Len(t, app.Report.Services, 3)
+
+	require.Equal(t, 1, report.Summary.Apps)
+	require.Equal(t, 1, report.Summary.Modules)
+	require.Equal(t, 3, report.Summary.Services)
+	require.Equal(t, 2, report.Summary.UnimplementedRpcs)
+	require.Equal(t, 2, report.Summary.GeneratedIssues)
+}
+
+// TestAnalyzeWorkspace_MultiApp tests apps sharing one go.mod and one api tree in the kratos-layout multi-app style
+//
+// TestAnalyzeWorkspace_MultiApp 测试 kratos-layout 多应用风格中共享一个 go.mod 和一个 api 目录树的应用
+func TestAnalyzeWorkspace_MultiApp(t *testing.T) {
+	root := t.TempDir()
+	must.Done(os.WriteFile(filepath.Join(root, "go.mod"), []byte("module shop\n\ngo 1.25.0\n"), 0644))
+	for _, app := range []string{"shop", "user"} {
+		appRoot := filepath.Join(root, "app", app, "service")
+		must.Done(os.MkdirAll(filepath.Join(appRoot, "cmd", app), 0755))
+		must.Done(os.WriteFile(filepath.Join(appRoot, "cmd", app, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644))
+		must.Done(os.MkdirAll(filepath.Join(appRoot, "internal", "server"), 0755))
+		must.Done(os.WriteFile(filepath.Join(appRoot, "internal", "server", "server.go"), []byte("package server\n"), 0644))
+	}
+	must.Done(os.MkdirAll(filepath.Join(root, "app", "shop", "service", "internal", "biz"), 0755))
+	must.Done(os.WriteFile(filepath.Join(root, "app", "shop", "service", "internal", "biz", "biz.go"), []byte(`package biz
+
+import "shop/app/shop/service/internal/server"
+
+var _ = server.Name
+`), 0644))
+	must.Done(os.MkdirAll(filepath.Join(root, "api", "shop", "service", "v1"), 0755))
+	must.Done(os.MkdirAll(filepath.Join(root, "api", "service", "v1"), 0755))
+	must.Done(os.MkdirAll(filepath.Join(root, "vendor", "app", "cmd"), 0755))
+	must.Done(os.MkdirAll(filepath.Join(root, "vendor", "app", "internal", "server"), 0755))
+
+	report := astkratos.AnalyzeWorkspace(root)
+	require.Len(t, report.Apps, 2)
+
+	shop := report.Apps[0]
+	require.Equal(t, "app/shop/service", shop.Name)
+	require.Equal(t, root, shop.ModuleRoot)
+	require.Equal(t, "shop/app/shop/service", shop.ImportPath)
+	require.Equal(t, filepath.Join(root, "api", "shop", "service"), shop.ApiRoot)
+	require.Len(t, shop.Report.LayerViolations, 1)
+	require.Equal(t, "shop/app/shop/service/internal/server", shop.Report.LayerViolations[0].ImportPath)
+
+	user := report.Apps[1]
+	require.Equal(t, "app/user/service", user.Name)
+	require.Equal(t, filepath.Join(root, "api"), user.ApiRoot)
+	require.Empty(t, user.Report.Services)
+
+	require.Equal(t, 2, report.Summary.Apps)
+	require.Equal(t, 1, report.Summary.Modules)
+	require.Equal(t, 1, report.Summary.LayerViolations)
+	require.Nil(t, report.GoWork)
+}
+
+// TestNestedApp tests that the standalone entry points see the packages of an app nested in a module as local
+//
+// TestNestedApp 测试独立入口将嵌套在模块中的应用的包视为本地包
+func TestNestedApp(t *testing.T) {
+	root := t.TempDir()
+	must.Done(os.WriteFile(filepath.Join(root, "go.mod"), []byte("module ws\n\ngo 1.25.0\n"), 0644))
+	appRoot := filepath.Join(root, "app", "shop", "service")
+	for _, dir := range []string{"cmd/shop", "internal/biz", "internal/server"} {
+		must.Done(os.MkdirAll(filepath.Join(appRoot, dir), 0755))
+	}
+	must.Done(os.MkdirAll(filepath.Join(root, "api"), 0755))
+	must.Done(os.WriteFile(filepath.Join(appRoot, "cmd", "shop", "wire.go"), []byte(`//go:build wireinject
+
+package main
+
+import (
+	"ws/app/shop/service/internal/biz"
+
+	"github.com/google/wire"
+)
+
+func wireApp() (*biz.Usecase, error) {
+	panic(wire.Build(biz.ProviderSet))
+}
+`), 0644))
+	must.Done(os.WriteFile(filepath.Join(appRoot, "internal", "biz", "biz.go"), []byte(`package biz
+
+import (
+	"github.com/google/wire"
+
+	"ws/app/shop/service/internal/server"
+)
+
+var ProviderSet = wire.NewSet(NewUsecase)
+
+type Usecase struct{}
+
+func NewUsecase() *Usecase {
+	return &Usecase{Name: server.Name}
+}
+`), 0644))
+	must.Done(os.WriteFile(filepath.Join(appRoot, "internal", "server", "server.go"), []byte("package server\n\nconst Name = \"shop\"\n"), 0644))
+
+	workspace := astkratos.AnalyzeWorkspace(root)
+	require.Len(t, workspace.Apps, 1)
+	require.Len(t, workspace.Apps[0].Report.LayerViolations, 1)
+
+	violations := astkratos.CheckLayering(appRoot)
+	require.Len(t, violations, 1)
+	require.Equal(t, "ws/app/shop/service/internal/server", violations[0].ImportPath)
+
+	graph := astkratos.GetWireGraph(appRoot)
+	require.Len(t, graph.ProviderSets, 1)
+	require.Equal(t, "ws/app/shop/service/internal/biz", graph.ProviderSets[0].ImportPath)
+	require.Len(t, graph.Injectors, 1)
+	require.Equal(t, astkratos.WireProviderKindSet, graph.Injectors[0].Providers[0].Kind)
+	require.Equal(t, "NewUsecase", graph.Injectors[0].ResolvedProviders[0].Name)
+
+	report, err := astkratos.NewAnalyzer(astkratos.WithApiRoots(filepath.Join(root, "api"))).Analyze(context.Background(), appRoot)
+	require.NoError(t, err)
+	require.Len(t, report.LayerViolations, 1)
+	require.Equal(t, graph.Injectors[0].ResolvedProviders[0].Kind, report.WireGraph.Injectors[0].ResolvedProviders[0].Kind)
+}